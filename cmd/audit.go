@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"log"
+	"os"
+
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/marcosQuesada/log-api/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+var serverSigningPubKey string
+
+// auditVerifyCmd verifies audit bundles offline
+var auditVerifyCmd = &cobra.Command{
+	Use:   "audit-verify [bundle.tar]",
+	Short: "verify audit bundle",
+	Long:  `verify every audit bundle log line against its proofs and the bundle server state, without contacting any server`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var pk *ecdsa.PublicKey
+		if serverSigningPubKey != "" {
+			k, err := signer.ParsePublicKeyFile(serverSigningPubKey)
+			if err != nil {
+				log.Fatalf("unable to parse server signing public key %s, error %v", serverSigningPubKey, err)
+			}
+			pk = k
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalf("unable to open bundle %s, error %v", args[0], err)
+		}
+		defer f.Close()
+
+		res, err := audit.Verify(f, pk)
+		if err != nil {
+			log.Fatalf("Bundle %s does not verify after %d log lines, error %v", args[0], res.Verified, err)
+		}
+
+		if !res.SignatureChecked {
+			log.Printf("Server state signature not checked, provide server-signing-pub-key to check it")
+		}
+		log.Printf("Verified %d log lines from bucket %s against database %s state at tx %d", res.Verified, res.Manifest.Bucket, res.State.Database, res.State.TxID)
+	},
+}
+
+func init() {
+	auditVerifyCmd.PersistentFlags().StringVar(&serverSigningPubKey, "server-signing-pub-key", "", "immudb server signing public key file, checks bundle state signature")
+}
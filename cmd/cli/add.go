@@ -2,20 +2,24 @@ package cli
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/signing"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
 var (
-	logLineData string
+	logLineData      string
+	sourceSigningKey string
 )
 
 // addCmd represents the add command
@@ -28,10 +32,13 @@ var addCmd = &cobra.Command{
 		if err := json.Unmarshal([]byte(logLineData), req); err != nil {
 			log.Fatalf("unable to unmarshall CreateLogLineRequest, data %s error %v", logLineData, err)
 		}
+		if sourceSigningKey != "" {
+			req.Signature = signRequest(req)
+		}
 
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -52,7 +59,19 @@ var addCmd = &cobra.Command{
 	},
 }
 
+// signRequest signs log line with source signing key, the server verifies it with source registered public key
+func signRequest(req *v1.CreateLogLineRequest) []byte {
+	seed, err := base64.StdEncoding.DecodeString(sourceSigningKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		log.Fatalf("source signing key must be a base64 encoded 32 bytes ed25519 seed")
+	}
+
+	l := service.NewSourceLogLine(req.GetSource(), req.GetBucket(), req.GetValue(), req.GetCreatedAt().AsTime())
+	return ed25519.Sign(ed25519.NewKeyFromSeed(seed), signing.Canonical(string(l.Key()), req.GetValue(), req.GetBucket(), l.Time()))
+}
+
 func init() {
 	ClientCmd.AddCommand(addCmd)
-	ClientCmd.PersistentFlags().StringVar(&logLineData, "line-data", `{"source":"fake_source_a","bucket":"fake_bucket","value":"fake data value xxx","created_at":{"seconds":1659469226,"nanos":165084420}}`, "raw json encoded data")
+	addCmd.PersistentFlags().StringVar(&sourceSigningKey, "signing-key", "", "base64 encoded 32 bytes ed25519 seed signing the log line as its source")
+	ClientCmd.PersistentFlags().StringVar(&logLineData, "line-data", `{"source":"fake-source-a","bucket":"fake_bucket","value":"fake data value xxx","created_at":{"seconds":1659469226,"nanos":165084420}}`, "raw json encoded data")
 }
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/audit"
+	"github.com/marcosQuesada/log-api/internal/export"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	auditBucket string
+	auditFrom   string
+	auditTo     string
+	auditOut    string
+)
+
+// auditBundleCmd streams bucket log lines with their proofs into a tar bundle
+var auditBundleCmd = &cobra.Command{
+	Use:   "audit-bundle",
+	Short: "build bucket audit bundle",
+	Long:  "build a tar bundle with bucket log lines created on time range (RFC3339), their immudb transactions, inclusion proofs and the server state they are proven against",
+	Run: func(cmd *cobra.Command, args []string) {
+		req := &v1.AuditBundleRequest{Bucket: auditBucket, From: timeFlag("from", auditFrom), To: timeFlag("to", auditTo)}
+
+		f, err := os.Create(auditOut)
+		if err != nil {
+			log.Fatalf("unable to create file %s, error %v", auditOut, err)
+		}
+		defer f.Close()
+
+		addr := fmt.Sprintf("localhost:%d", grpcPort)
+		conn, err := grpc.Dial(addr,
+			transportCredentials(),
+		)
+		if err != nil {
+			log.Fatalf("client unable to connect, error: %v", err)
+		}
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", jwtToken))
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := v1.NewAuditServiceClient(conn).ExportAuditBundle(ctx, req)
+		if err != nil {
+			log.Fatalf("could not audit bucket %s: %v", auditBucket, err)
+		}
+
+		w := audit.NewWriter(f)
+		var signed bool
+		for {
+			e, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("could not audit bucket %s: %v", auditBucket, err)
+			}
+
+			if s := e.GetState(); s != nil {
+				signed = s.GetSigned()
+				err = w.WriteState(&audit.State{Database: s.GetDatabase(), TxID: s.GetTxId(), Signed: s.GetSigned(), State: s.GetState()})
+			}
+			if l := e.GetLine(); l != nil {
+				err = w.WriteLine(&audit.Line{
+					Record: export.Record{
+						Key:       l.GetKey(),
+						Bucket:    l.GetBucket(),
+						Value:     l.GetValue(),
+						Tx:        l.GetTx(),
+						Revision:  l.GetRevision(),
+						CreatedAt: l.GetCreatedAt().AsTime(),
+					},
+					Proof: l.GetProof(),
+				})
+			}
+			if err != nil {
+				log.Fatalf("unable to write bundle %s, error %v", auditOut, err)
+			}
+		}
+
+		m := &audit.Manifest{Bucket: auditBucket, CreatedAt: time.Now().UTC()}
+		if req.From != nil {
+			m.From = req.From.AsTime()
+		}
+		if req.To != nil {
+			m.To = req.To.AsTime()
+		}
+		if err := w.Close(m); err != nil {
+			log.Fatalf("unable to flush bundle %s, error %v", auditOut, err)
+		}
+
+		if !signed {
+			log.Printf("Server state is not signed, enable immudb state signing to get signed bundles")
+		}
+		log.Printf("Bundled %d log lines from bucket %s to %s", m.Lines, auditBucket, auditOut)
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(auditBundleCmd)
+	auditBundleCmd.PersistentFlags().StringVar(&auditBucket, "bucket", "", "bucket to audit")
+	auditBundleCmd.PersistentFlags().StringVar(&auditFrom, "from", "", "audit lines created from RFC3339 time, empty audits from first line")
+	auditBundleCmd.PersistentFlags().StringVar(&auditTo, "to", "", "audit lines created up to RFC3339 time, empty audits up to last line")
+	auditBundleCmd.PersistentFlags().StringVar(&auditOut, "out", "bundle.tar", "bundle file path")
+}
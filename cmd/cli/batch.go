@@ -10,7 +10,6 @@ import (
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -31,7 +30,7 @@ var batchCmd = &cobra.Command{
 
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -54,5 +53,5 @@ var batchCmd = &cobra.Command{
 
 func init() {
 	ClientCmd.AddCommand(batchCmd)
-	ClientCmd.PersistentFlags().StringVar(&logLinesData, "lines-data", `{"lines":[{"source":"fake_source_a","bucket":"fake_bucket","value":"fake data value xxx","created_at":{"seconds":1659469108,"nanos":710408961}},{"source":"fake_source_b","bucket":"fake_bucket","value":"fake data value xaxaxax","created_at":{"seconds":1659469108,"nanos":710409242}}]}`, "raw json encoded data")
+	ClientCmd.PersistentFlags().StringVar(&logLinesData, "lines-data", `{"lines":[{"source":"fake-source-a","bucket":"fake_bucket","value":"fake data value xxx","created_at":{"seconds":1659469108,"nanos":710408961}},{"source":"fake-source-b","bucket":"fake_bucket","value":"fake data value xaxaxax","created_at":{"seconds":1659469108,"nanos":710409242}}]}`, "raw json encoded data")
 }
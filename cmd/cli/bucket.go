@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	bucketName        string
+	bucketDescription string
+	bucketRetention   string
+	includeArchived   bool
+)
+
+// bucketCmd groups bucket management commands
+var bucketCmd = &cobra.Command{
+	Use:   "bucket",
+	Short: "bucket management",
+	Long:  "bucket management",
+}
+
+var bucketCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create bucket",
+	Long:  "create bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		withBucketClient(func(ctx context.Context, c v1.BucketServiceClient) {
+			b, err := c.CreateBucket(ctx, &v1.CreateBucketRequest{Name: bucketName, Description: bucketDescription, Retention: bucketRetention})
+			if err != nil {
+				log.Fatalf("could not create bucket %s: %v", bucketName, err)
+			}
+			log.Printf("Created Bucket %v", b)
+		})
+	},
+}
+
+var bucketListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list buckets",
+	Long:  "list buckets",
+	Run: func(cmd *cobra.Command, args []string) {
+		withBucketClient(func(ctx context.Context, c v1.BucketServiceClient) {
+			res, err := c.ListBuckets(ctx, &v1.ListBucketsRequest{IncludeArchived: includeArchived})
+			if err != nil {
+				log.Fatalf("could not list buckets: %v", err)
+			}
+			for _, b := range res.Buckets {
+				log.Printf("Bucket %s: %v", b.GetName(), b)
+			}
+		})
+	},
+}
+
+var bucketGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "get bucket",
+	Long:  "get bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		withBucketClient(func(ctx context.Context, c v1.BucketServiceClient) {
+			b, err := c.GetBucket(ctx, &v1.GetBucketRequest{Name: bucketName})
+			if err != nil {
+				log.Fatalf("could not get bucket %s: %v", bucketName, err)
+			}
+			log.Printf("Bucket %s: %v", b.GetName(), b)
+		})
+	},
+}
+
+var bucketArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "archive bucket",
+	Long:  "archive bucket, archived buckets do not accept new log lines",
+	Run: func(cmd *cobra.Command, args []string) {
+		withBucketClient(func(ctx context.Context, c v1.BucketServiceClient) {
+			b, err := c.ArchiveBucket(ctx, &v1.ArchiveBucketRequest{Name: bucketName})
+			if err != nil {
+				log.Fatalf("could not archive bucket %s: %v", bucketName, err)
+			}
+			log.Printf("Archived Bucket %v", b)
+		})
+	},
+}
+
+func withBucketClient(f func(ctx context.Context, c v1.BucketServiceClient)) {
+	addr := fmt.Sprintf("localhost:%d", grpcPort)
+	conn, err := grpc.Dial(addr,
+		transportCredentials(),
+	)
+	if err != nil {
+		log.Fatalf("client unable to connect, error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", jwtToken))
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	f(ctx, v1.NewBucketServiceClient(conn))
+}
+
+func init() {
+	ClientCmd.AddCommand(bucketCmd)
+	bucketCmd.AddCommand(bucketCreateCmd, bucketListCmd, bucketGetCmd, bucketArchiveCmd)
+	bucketCmd.PersistentFlags().StringVar(&bucketName, "name", "", "bucket name")
+	bucketCreateCmd.PersistentFlags().StringVar(&bucketDescription, "description", "", "bucket description")
+	bucketCreateCmd.PersistentFlags().StringVar(&bucketRetention, "retention", "forever", "bucket retention, as 7d, 12h or forever")
+	bucketListCmd.PersistentFlags().BoolVar(&includeArchived, "include-archived", false, "include archived buckets")
+}
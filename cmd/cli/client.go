@@ -1,12 +1,26 @@
 package cli
 
 import (
+	"log"
+
+	"github.com/marcosQuesada/log-api/internal/tlsconfig"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
 	jwtToken string
 	grpcPort int
+
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+
+	asOfTx   uint64
+	asOfTime string
 )
 
 var ClientCmd = &cobra.Command{
@@ -18,4 +32,28 @@ var ClientCmd = &cobra.Command{
 func init() {
 	ClientCmd.PersistentFlags().StringVar(&jwtToken, "token", "", "jwt jwtSecret")
 	ClientCmd.PersistentFlags().IntVar(&grpcPort, "grpc-port", 9000, "grpc port")
+	ClientCmd.PersistentFlags().StringVar(&caFile, "ca", "", "PEM CA file verifying server certificate, enables TLS")
+	ClientCmd.PersistentFlags().StringVar(&certFile, "cert", "", "PEM client certificate file for mTLS, enables TLS")
+	ClientCmd.PersistentFlags().StringVar(&keyFile, "key", "", "PEM client private key file for mTLS")
+	ClientCmd.PersistentFlags().StringVar(&serverName, "server-name", "", "server certificate name to verify, dialed host by default")
+}
+
+// transportCredentials returns server dial credentials, plaintext unless a CA or client certificate is set
+func transportCredentials() grpc.DialOption {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+
+	c, err := tlsconfig.Client(caFile, certFile, keyFile, serverName)
+	if err != nil {
+		log.Fatalf("client unable to build TLS config, error: %v", err)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(c))
+}
+
+// addAsOfFlags enables point in time reads
+func addAsOfFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Uint64Var(&asOfTx, "as-of-tx", 0, "read log lines as they were on this transaction")
+	cmd.PersistentFlags().StringVar(&asOfTime, "as-of-time", "", "read log lines as they were on this RFC3339 time")
 }
@@ -9,7 +9,6 @@ import (
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -22,7 +21,7 @@ var countCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/export"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	exportBucket string
+	exportFrom   string
+	exportTo     string
+	exportFormat string
+	exportOut    string
+)
+
+// exportCmd streams bucket log lines to a file
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export bucket log lines",
+	Long:  "export bucket log lines created on time range (RFC3339) to ndjson, csv or parquet file, lines are written as they are received",
+	Run: func(cmd *cobra.Command, args []string) {
+		req := &v1.ExportLogLinesRequest{Bucket: exportBucket, From: timeFlag("from", exportFrom), To: timeFlag("to", exportTo)}
+
+		f, err := os.Create(exportOut)
+		if err != nil {
+			log.Fatalf("unable to create file %s, error %v", exportOut, err)
+		}
+		defer f.Close()
+
+		w, err := export.NewWriter(exportFormat, f)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		addr := fmt.Sprintf("localhost:%d", grpcPort)
+		conn, err := grpc.Dial(addr,
+			transportCredentials(),
+		)
+		if err != nil {
+			log.Fatalf("client unable to connect, error: %v", err)
+		}
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", jwtToken))
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := v1.NewLogServiceClient(conn).ExportLogLines(ctx, req)
+		if err != nil {
+			log.Fatalf("could not export bucket %s: %v", exportBucket, err)
+		}
+
+		var total int
+		for {
+			l, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("could not export bucket %s: %v", exportBucket, err)
+			}
+
+			err = w.Write(&export.Record{
+				Key:       l.GetKey(),
+				Bucket:    l.GetBucket(),
+				Value:     l.GetValue(),
+				Tx:        l.GetTx(),
+				Revision:  l.GetRevision(),
+				CreatedAt: l.GetCreatedAt().AsTime(),
+			})
+			if err != nil {
+				log.Fatalf("unable to write key %s, error %v", l.GetKey(), err)
+			}
+			total++
+		}
+
+		if err := w.Close(); err != nil {
+			log.Fatalf("unable to flush file %s, error %v", exportOut, err)
+		}
+		log.Printf("Exported %d log lines from bucket %s to %s", total, exportBucket, exportOut)
+	},
+}
+
+// timeFlag parses RFC3339 time flag, empty flags are left nil
+func timeFlag(name, value string) *timestamppb.Timestamp {
+	if value == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("invalid %s time %s, error %v", name, value, err)
+	}
+
+	return timestamppb.New(t)
+}
+
+func init() {
+	ClientCmd.AddCommand(exportCmd)
+	exportCmd.PersistentFlags().StringVar(&exportBucket, "bucket", "", "bucket to export")
+	exportCmd.PersistentFlags().StringVar(&exportFrom, "from", "", "export lines created from RFC3339 time, empty exports from first line")
+	exportCmd.PersistentFlags().StringVar(&exportTo, "to", "", "export lines created up to RFC3339 time, empty exports up to last line")
+	exportCmd.PersistentFlags().StringVar(&exportFormat, "format", export.FormatNDJSON, "export format, ndjson, csv or parquet")
+	exportCmd.PersistentFlags().StringVar(&exportOut, "out", "", "export file path")
+}
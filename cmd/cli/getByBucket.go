@@ -9,7 +9,6 @@ import (
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -25,7 +24,7 @@ var getByBucketCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -37,7 +36,7 @@ var getByBucketCmd = &cobra.Command{
 		defer cancel()
 
 		c := v1.NewLogServiceClient(conn)
-		u, err := c.GetLogLinesByBucket(ctx, &v1.LogLineByBucketRequest{Bucket: bucket})
+		u, err := c.GetLogLinesByBucket(ctx, &v1.LogLineByBucketRequest{Bucket: bucket, AsOfTx: asOfTx, AsOfTime: timeFlag("as-of-time", asOfTime)})
 		if err != nil {
 			log.Fatalf("could not get by bucket %s: %v", prefix, err)
 		}
@@ -50,4 +49,5 @@ var getByBucketCmd = &cobra.Command{
 func init() {
 	ClientCmd.AddCommand(getByBucketCmd)
 	getByBucketCmd.PersistentFlags().StringVar(&bucket, "bucket", "", "key bucket")
+	addAsOfFlags(getByBucketCmd)
 }
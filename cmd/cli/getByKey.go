@@ -7,10 +7,11 @@ import (
 	"time"
 
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/signing"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var (
@@ -25,7 +26,7 @@ var getByKeyCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -38,15 +39,49 @@ var getByKeyCmd = &cobra.Command{
 
 		c := v1.NewLogServiceClient(conn)
 
-		u, err := c.GetLogLineByKey(ctx, &v1.LogLineByKeyRequest{Key: key})
+		u, err := c.GetLogLineByKey(ctx, &v1.LogLineByKeyRequest{Key: key, AsOfTx: asOfTx, AsOfTime: timeFlag("as-of-time", asOfTime)})
 		if err != nil {
 			log.Fatalf("could not get by ID: %v", err)
 		}
 		log.Printf("User: %v", u)
+
+		if len(u.GetSignature()) == 0 {
+			log.Printf("Log line %s is not signed", u.GetKey())
+			return
+		}
+
+		keys, err := c.GetSigningKeys(ctx, &emptypb.Empty{})
+		if err != nil {
+			log.Fatalf("could not get signing keys: %v", err)
+		}
+		verifySignature(u, keys.GetKeys())
 	},
 }
 
+// verifySignature checks log line signature with its signing key
+func verifySignature(l *v1.LogLine, keys []*v1.SigningKey) {
+	for _, k := range keys {
+		if k.GetId() != l.GetSigningKeyId() {
+			continue
+		}
+
+		if !signing.Verify(k.GetPublicKey(), l.GetKey(), l.GetValue(), l.GetBucket(), l.GetCreatedAt().AsTime(), l.GetSignature()) {
+			log.Fatalf("Log line %s signature does not verify with key %s", l.GetKey(), k.GetId())
+		}
+
+		signer := "server"
+		if k.GetSource() != "" {
+			signer = "source " + k.GetSource()
+		}
+		log.Printf("Log line %s signature verified with %s key %s", l.GetKey(), signer, k.GetId())
+		return
+	}
+
+	log.Fatalf("Log line %s signing key %s is unknown", l.GetKey(), l.GetSigningKeyId())
+}
+
 func init() {
 	ClientCmd.AddCommand(getByKeyCmd)
 	getByKeyCmd.PersistentFlags().StringVar(&key, "key", "", "key name")
+	addAsOfFlags(getByKeyCmd)
 }
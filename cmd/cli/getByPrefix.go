@@ -9,7 +9,6 @@ import (
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -21,11 +20,11 @@ var (
 var getByPrefixCmd = &cobra.Command{
 	Use:   "get-by-prefix",
 	Short: "get prefixed log lines",
-	Long:  "get prefixed log lines",
+	Long:  "get log lines whose source starts by prefix, prefixes on the versioned key encoding narrow them down by creation time",
 	Run: func(cmd *cobra.Command, args []string) {
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -37,7 +36,7 @@ var getByPrefixCmd = &cobra.Command{
 		defer cancel()
 
 		c := v1.NewLogServiceClient(conn)
-		u, err := c.GetLogLinesByPrefix(ctx, &v1.LogLineByPrefixRequest{Prefix: prefix})
+		u, err := c.GetLogLinesByPrefix(ctx, &v1.LogLineByPrefixRequest{Prefix: prefix, AsOfTx: asOfTx, AsOfTime: timeFlag("as-of-time", asOfTime)})
 		if err != nil {
 			log.Fatalf("could not get by prefix %s: %v", prefix, err)
 		}
@@ -49,5 +48,6 @@ var getByPrefixCmd = &cobra.Command{
 
 func init() {
 	ClientCmd.AddCommand(getByPrefixCmd)
-	getByPrefixCmd.PersistentFlags().StringVar(&prefix, "prefix", "", "key prefix")
+	getByPrefixCmd.PersistentFlags().StringVar(&prefix, "prefix", "", "log line source prefix, or versioned key prefix")
+	addAsOfFlags(getByPrefixCmd)
 }
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	historyKey  string
+	historyDiff bool
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "get log line revisions",
+	Long:  "get log line revisions with its transaction timestamp and writer, with diff each revision shows its changes from the previous one",
+	Run: func(cmd *cobra.Command, args []string) {
+		addr := fmt.Sprintf("localhost:%d", grpcPort)
+		conn, err := grpc.Dial(addr,
+			transportCredentials(),
+		)
+		if err != nil {
+			log.Fatalf("client unable to connect, error: %v", err)
+		}
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", jwtToken))
+		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+		defer cancel()
+
+		c := v1.NewLogServiceClient(conn)
+		h, err := c.GetLogLineHistory(ctx, &v1.LogLineHistoryRequest{Key: historyKey, Diff: historyDiff})
+		if err != nil {
+			log.Fatalf("could not get history: %v", err)
+		}
+
+		for _, rv := range h.GetRevision() {
+			principal := rv.GetPrincipal()
+			if principal == "" {
+				principal = "unknown"
+			}
+			fmt.Printf("revision %d tx %d committed at %s by %s\n", rv.GetRevision(), rv.GetTx(), rv.GetCommittedAt().AsTime().Format(time.RFC3339), principal)
+
+			out := rv.GetValue()
+			if historyDiff {
+				out = rv.GetDiff()
+			}
+			fmt.Println(strings.TrimSuffix(out, "\n") + "\n")
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(historyCmd)
+	historyCmd.PersistentFlags().StringVar(&historyKey, "key", "", "log line key")
+	historyCmd.PersistentFlags().BoolVar(&historyDiff, "diff", false, "show each revision changes, json patch on structured values, unified diff otherwise")
+}
@@ -9,12 +9,12 @@ import (
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
 	user     string
 	password string
+	tenantID string
 )
 
 // loginCmd represents the login command
@@ -25,7 +25,7 @@ var loginCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		addr := fmt.Sprintf("localhost:%d", grpcPort)
 		conn, err := grpc.Dial(addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			transportCredentials(),
 		)
 		if err != nil {
 			log.Fatalf("client unable to connect, error: %v", err)
@@ -36,7 +36,7 @@ var loginCmd = &cobra.Command{
 		defer cancel()
 
 		c := v1.NewAuthServiceClient(conn)
-		u, err := c.Login(ctx, &v1.LoginRequest{Username: user, Password: password})
+		u, err := c.Login(ctx, &v1.LoginRequest{Username: user, Password: password, Tenant: tenantID})
 		if err != nil {
 			log.Fatalf("could not get by ID: %v", err)
 		}
@@ -48,4 +48,5 @@ func init() {
 	ClientCmd.AddCommand(loginCmd)
 	loginCmd.PersistentFlags().StringVar(&user, "user", "fake_user", "login user name")
 	loginCmd.PersistentFlags().StringVar(&password, "password", "fake_password", "login password")
+	loginCmd.PersistentFlags().StringVar(&tenantID, "tenant", "", "login tenant, empty uses server default database")
 }
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var quotaBuckets []string
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show caller rate limits and daily quotas usage",
+	Long:  "Show caller principal and API key rate limits and daily quotas usage, plus requested buckets ones",
+	Run: func(cmd *cobra.Command, args []string) {
+		addr := fmt.Sprintf("localhost:%d", grpcPort)
+		conn, err := grpc.Dial(addr,
+			transportCredentials(),
+		)
+		if err != nil {
+			log.Fatalf("client unable to connect, error: %v", err)
+		}
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", jwtToken))
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		c := v1.NewQuotaServiceClient(conn)
+		res, err := c.GetQuotaUsage(ctx, &v1.QuotaUsageRequest{Buckets: quotaBuckets})
+		if err != nil {
+			log.Fatalf("could not get quota usage: %v", err)
+		}
+
+		for _, u := range res.GetUsages() {
+			log.Printf("%s %s rate %v burst %d available %.1f, lines %d/%d bytes %d/%d, resets at %s",
+				u.GetKind(), u.GetId(), u.GetRate(), u.GetBurst(), u.GetAvailable(), u.GetUsedLines(), u.GetDailyLines(),
+				u.GetUsedBytes(), u.GetDailyBytes(), u.GetResetsAt().AsTime().Format(time.RFC3339))
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(quotaCmd)
+	quotaCmd.Flags().StringSliceVar(&quotaBuckets, "bucket", nil, "buckets to report, repeatable")
+}
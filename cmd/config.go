@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/marcosQuesada/log-api/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Modes
+const (
+	modeDevelopment = "development"
+	modeProduction  = "production"
+)
+
+const (
+	defaultJWTSecret      = "jwt-secret"
+	defaultImmudbPassword = "immudb"
+	minJWTSecretSize      = 32
+)
+
+var (
+	configFile string
+	mode       string
+)
+
+// secretFlags are redacted on printed configuration
+var secretFlags = map[string]bool{
+	"jwt-secret":            true,
+	"immudb-password":       true,
+	"encryption-master-key": true,
+	"signing-key":           true,
+	"token":                 true,
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "configuration commands",
+	Long:  `configuration commands, flags are set from command line, then LOG_API_* environment variables, then config file`,
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "print effective server configuration",
+	Long:  `print effective server configuration as yaml with secrets redacted, it exits with error once it is not valid`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fs := pflag.NewFlagSet(serverCmd.Name(), pflag.ContinueOnError)
+		fs.AddFlagSet(serverCmd.PersistentFlags())
+		if err := config.Load(fs, configPath(), knownKey); err != nil {
+			log.Fatalln("Unable to load configuration, error:", err)
+		}
+
+		if err := config.Print(os.Stdout, fs, secretFlags); err != nil {
+			log.Fatalln("Unable to print configuration, error:", err)
+		}
+
+		if err := validateServerConfig(); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPrintCmd)
+}
+
+// loadConfig sets command flags not given on command line from environment and config file,
+// flags are already parsed so configuration errors do not print usage
+func loadConfig(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	return config.Load(cmd.Flags(), configPath(), knownKey)
+}
+
+// configPath returns config file path from --config flag, or LOG_API_CONFIG as it is read before any other
+func configPath() string {
+	if configFile != "" {
+		return configFile
+	}
+
+	return os.Getenv(config.EnvName("config"))
+}
+
+// knownKey matches any command flag, so a single config file serves every command
+func knownKey(key string) bool {
+	return hasFlag(rootCmd, key)
+}
+
+func hasFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, c := range cmd.Commands() {
+		if hasFlag(c, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateServerConfig refuses inconsistent server configurations, production mode refuses default secrets too
+func validateServerConfig() error {
+	var problems []string
+	if mode != modeDevelopment && mode != modeProduction {
+		problems = append(problems, fmt.Sprintf("mode %q is not %s nor %s", mode, modeDevelopment, modeProduction))
+	}
+	for _, p := range []struct {
+		name string
+		port int
+	}{{"grpc-port", grpcPort}, {"http-port", httpPort}, {"metrics-port", metricsPort}, {"immudb-port", immudbPort}} {
+		if p.port < 0 || p.port > 65535 {
+			problems = append(problems, fmt.Sprintf("%s %d out of range", p.name, p.port))
+		}
+	}
+	if grpcPort == httpPort {
+		problems = append(problems, fmt.Sprintf("grpc-port and http-port are both %d", grpcPort))
+	}
+	if metricsPort != 0 && (metricsPort == grpcPort || metricsPort == httpPort) {
+		problems = append(problems, fmt.Sprintf("metrics-port %d is taken by grpc-port or http-port", metricsPort))
+	}
+	if (tlsCert == "") != (tlsKey == "") {
+		problems = append(problems, "tls-cert and tls-key must be set together")
+	}
+	if clientCA != "" && tlsCert == "" {
+		problems = append(problems, "client-ca requires tls-cert and tls-key")
+	}
+	if (immudbTLSCert == "") != (immudbTLSKey == "") {
+		problems = append(problems, "immudb-tls-cert and immudb-tls-key must be set together")
+	}
+	if encryptionKeyStore != "" && encryptionMasterKey == "" {
+		problems = append(problems, "encryption-keystore requires encryption-master-key")
+	}
+	if traceRatio < 0 || traceRatio > 1 {
+		problems = append(problems, fmt.Sprintf("trace-ratio %v out of [0, 1]", traceRatio))
+	}
+	if shutdownTimeout <= 0 {
+		problems = append(problems, "shutdown-timeout must be positive")
+	}
+	if immudbKeepAlive <= 0 {
+		problems = append(problems, "immudb-keep-alive must be positive")
+	}
+	if maxValueSize < 0 || maxBatchSize < 0 {
+		problems = append(problems, "max-value-size and max-batch-size must not be negative")
+	}
+
+	if mode == modeProduction {
+		if jwtSecret == defaultJWTSecret {
+			problems = append(problems, "jwt-secret is the default one")
+		}
+		if len(jwtSecret) < minJWTSecretSize {
+			problems = append(problems, fmt.Sprintf("jwt-secret shorter than %d bytes", minJWTSecretSize))
+		}
+		if immudbPassword == defaultImmudbPassword {
+			problems = append(problems, "immudb-password is the default one")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s, error %w", strings.Join(problems, "; "), config.ErrInvalid)
+}
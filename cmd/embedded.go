@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/embedded"
+	"github.com/spf13/cobra"
+)
+
+var (
+	embeddedDB               string
+	embeddedDBSynced         bool
+	embeddedDBBackupHook     string
+	embeddedDBBackupInterval time.Duration
+)
+
+func addEmbeddedFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&embeddedDB, "embedded-db", "", "data directory, runs immudb in process listening on immudb-host and immudb-port instead of connecting to an immudb server")
+	cmd.PersistentFlags().BoolVar(&embeddedDBSynced, "embedded-db-synced", true, "sync embedded immudb writes to disk on each commit")
+	cmd.PersistentFlags().StringVar(&embeddedDBBackupHook, "embedded-db-backup-hook", "", "shell command run once embedded immudb stops on shutdown, data directory is on "+embedded.DirEnv)
+	cmd.PersistentFlags().DurationVar(&embeddedDBBackupInterval, "embedded-db-backup-interval", 0, "runs backup hook periodically on running immudb too, hook must take an atomic filesystem snapshot")
+}
+
+// startEmbeddedDB starts immudb in process, returned stop stops it once the server is drained and runs backup hook.
+// Immudb stopping by itself before ends the process
+func startEmbeddedDB() func() error {
+	if storage != storageImmudb {
+		log.Fatalf("Embedded immudb requires %s storage, got %s", storageImmudb, storage)
+	}
+
+	s, err := embedded.Start(&embedded.Options{
+		Dir:           embeddedDB,
+		Address:       immudbHost,
+		Port:          immudbPort,
+		Synced:        embeddedDBSynced,
+		AdminPassword: immudbPassword,
+	})
+	if err != nil {
+		log.Fatalln("Unable to start embedded immudb, error:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if embeddedDBBackupHook != "" && embeddedDBBackupInterval > 0 {
+		go s.RunHooks(ctx, embeddedDBBackupHook, embeddedDBBackupInterval)
+	}
+
+	go func() {
+		<-s.Done()
+		if ctx.Err() == nil {
+			log.Fatalf("Embedded immudb stopped unexpectedly, error %v", s.Err())
+		}
+	}()
+
+	return func() error {
+		cancel()
+		if err := s.Stop(); err != nil {
+			return err
+		}
+		if err := s.Err(); err != nil {
+			log.Printf("Embedded immudb stopped, error %v", err)
+		}
+
+		if embeddedDBBackupHook != "" {
+			if err := s.RunHook(context.Background(), embeddedDBBackupHook); err != nil {
+				return err
+			}
+		}
+		log.Println("Embedded immudb stopped")
+
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/tenant"
+)
+
+const (
+	healthWatchInterval = time.Second * 5
+	initRetryInterval   = time.Second * 5
+)
+
+// initDefaultStack opens default database stack, retrying until its repository gets initialized.
+// Readiness fails meanwhile, so orchestrators hold traffic instead of restarting the server
+func initDefaultStack(router *tenant.Router) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		_, err := router.Stack(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		log.Printf("Unable to initialize log lines repository, retrying in %v, error %v", initRetryInterval, err)
+		time.Sleep(initRetryInterval)
+	}
+}
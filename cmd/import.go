@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/marcosQuesada/log-api/internal/envelope"
+	"github.com/marcosQuesada/log-api/internal/export"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFile       string
+	importFormat     string
+	importTenant     string
+	importBatchSize  int
+	importCheckpoint string
+)
+
+// importCmd re-ingests exported log lines
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import exported log lines",
+	Long: `import exported log lines from ndjson or csv archives, keys are rebuilt from its source and creation time.
+Already stored keys are skipped, reporting conflicts when stored values differ. Interrupted imports resume from its checkpoint file`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if importFile == "" {
+			log.Fatalln("file is required")
+		}
+
+		format := importFormat
+		if format == "" {
+			format = export.FormatNDJSON
+			if strings.EqualFold(filepath.Ext(importFile), ".csv") {
+				format = export.FormatCSV
+			}
+		}
+
+		checkpoint := importCheckpoint
+		if checkpoint == "" {
+			checkpoint = importFile + ".checkpoint"
+		}
+
+		position, err := readCheckpoint(checkpoint)
+		if err != nil {
+			log.Fatalf("unable to read checkpoint %s, error %v", checkpoint, err)
+		}
+
+		f, err := os.Open(importFile)
+		if err != nil {
+			log.Fatalf("unable to open file %s, error %v", importFile, err)
+		}
+		defer f.Close()
+
+		rd, err := export.NewReader(format, f)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		var ks envelope.KeyStore
+		if encryptionKeyStore != "" {
+			ks = buildKeyStore()
+		}
+
+		database := immudbDatabase
+		if importTenant != "" {
+			if err := tenant.Validate(importTenant); err != nil {
+				log.Fatalln(err)
+			}
+			database = importTenant
+		}
+
+		ctx := context.Background()
+		st, err := storageStackBuilder(ks, buildSigningKeys())(ctx, database)
+		if err != nil {
+			log.Fatalf("unable to open database %s, error %v", database, err)
+		}
+		if st.Close != nil {
+			defer st.Close(ctx)
+		}
+
+		if position > 0 {
+			log.Printf("Resuming import from record %d", position)
+		}
+
+		imp := export.NewImporter(st.Logs, importBatchSize).WithBucketGuard(service.NewBucketService(st.Buckets, true))
+		rep, err := imp.Import(ctx, rd, position, func(p int) error {
+			return writeCheckpoint(checkpoint, p)
+		})
+		if rep != nil {
+			for _, c := range rep.Conflicts {
+				log.Printf("Conflict on key %s, stored value %q imported value %q", c.Key, c.Stored, c.Imported)
+			}
+		}
+		if err != nil {
+			log.Fatalf("import interrupted after record %d, error %v", rep.Position, err)
+		}
+
+		if err := os.Remove(checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("unable to remove checkpoint %s, error %v", checkpoint, err)
+		}
+
+		log.Printf("Imported %d log lines, skipped %d already stored, %d conflicts", rep.Imported, rep.Skipped, len(rep.Conflicts))
+	},
+}
+
+// readCheckpoint returns last stored record position, zero if there is no checkpoint
+func readCheckpoint(path string) (int, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(raw)))
+}
+
+// writeCheckpoint replaces checkpoint file atomically
+func writeCheckpoint(path string, position int) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(position)), 0600); err != nil {
+		return fmt.Errorf("unable to write checkpoint, error %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func init() {
+	addImmudbFlags(importCmd)
+	addRetentionFlags(importCmd)
+	importCmd.PersistentFlags().StringVar(&importFile, "file", "", "exported archive file path")
+	importCmd.PersistentFlags().StringVar(&importFormat, "format", "", "archive format, ndjson or csv, empty guesses it from file extension")
+	importCmd.PersistentFlags().StringVar(&importTenant, "tenant", "", "import into tenant database, empty imports into immudb-database")
+	importCmd.PersistentFlags().IntVar(&importBatchSize, "batch-size", export.DefaultBatchSize, "records stored per batch, checkpoint is written after each batch")
+	importCmd.PersistentFlags().StringVar(&importCheckpoint, "checkpoint", "", "checkpoint file path, defaults to file path with .checkpoint suffix")
+	importCmd.PersistentFlags().StringVar(&encryptionKeyStore, "encryption-keystore", "", "data keys store file path, encrypts imported values")
+	importCmd.PersistentFlags().StringVar(&encryptionMasterKey, "encryption-master-key", "", "base64 encoded 32 bytes master key wrapping data keys")
+	addSigningFlags(importCmd)
+	addStorageFlags(importCmd)
+}
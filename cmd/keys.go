@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	shredBucket string
+	shredTenant string
+)
+
+// keysCmd groups encryption data keys management commands
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "encryption data keys management",
+	Long:  `encryption data keys management, data keys are kept in the key store file, outside immudb`,
+}
+
+// shredCmd destroys bucket data keys, making its log lines unreadable
+var shredCmd = &cobra.Command{
+	Use:   "shred",
+	Short: "shred bucket data keys",
+	Long:  `shred bucket data keys, log line values encrypted with them become unreadable while their history remains verifiable`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if encryptionKeyStore == "" || shredBucket == "" {
+			log.Fatalln("encryption-keystore and bucket are required")
+		}
+
+		ks := buildKeyStore()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		scope := shredBucket
+		if shredTenant != "" {
+			scope = shredTenant + "/" + shredBucket
+		}
+
+		total, err := ks.Shred(ctx, scope)
+		if err != nil {
+			log.Fatalf("unable to shred bucket %s data keys, error %v", shredBucket, err)
+		}
+
+		log.Printf("Shredded %d data keys from bucket %s", total, shredBucket)
+	},
+}
+
+func init() {
+	keysCmd.PersistentFlags().StringVar(&encryptionKeyStore, "encryption-keystore", "", "data keys store file path")
+	keysCmd.PersistentFlags().StringVar(&encryptionMasterKey, "encryption-master-key", "", "base64 encoded 32 bytes master key wrapping data keys")
+	shredCmd.PersistentFlags().StringVar(&shredBucket, "bucket", "", "bucket to shred")
+	shredCmd.PersistentFlags().StringVar(&shredTenant, "tenant", "", "bucket tenant, empty on default database")
+	keysCmd.AddCommand(shredCmd)
+}
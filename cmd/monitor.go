@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/marcosQuesada/log-api/internal/immudb"
+	"github.com/marcosQuesada/log-api/internal/monitor"
+	"github.com/spf13/cobra"
+)
+
+// inconsistentStateExitCode is returned by monitor once an inconsistency is detected
+const inconsistentStateExitCode = 2
+
+var (
+	monitorInterval      time.Duration
+	checkpointsPath      string
+	checkpointSigningKey string
+	alertWebhook         string
+)
+
+// monitorCmd periodically verifies immudb state consistency
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "immudb state consistency monitor",
+	Long:  `periodically verifies immudb current state is consistent with the last recorded one, appending signed checkpoints to a local file and alerting on any inconsistency`,
+	Run: func(cmd *cobra.Command, args []string) {
+		seed, err := base64.StdEncoding.DecodeString(checkpointSigningKey)
+		if err != nil {
+			log.Fatalf("unable to decode checkpoint signing key, error %v", err)
+		}
+
+		cps, err := monitor.OpenCheckpoints(checkpointsPath, seed)
+		if err != nil {
+			log.Fatalf("unable to open checkpoints, error %v", err)
+		}
+
+		var pk *ecdsa.PublicKey
+		if serverSigningPubKey != "" {
+			if pk, err = signer.ParsePublicKeyFile(serverSigningPubKey); err != nil {
+				log.Fatalf("unable to parse server signing public key %s, error %v", serverSigningPubKey, err)
+			}
+		}
+
+		var alerter monitor.Alerter
+		if alertWebhook != "" {
+			alerter = monitor.NewWebhookAlerter(alertWebhook)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		log.Printf("Monitoring database %s state each %s, checkpoints %s", immudbDatabase, monitorInterval, checkpointsPath)
+		m := monitor.NewMonitor(immudb.NewConnRepository(buildConn()), cps, alerter, pk)
+		if err := m.Run(ctx, monitorInterval); errors.Is(err, monitor.ErrInconsistentState) {
+			log.Printf("Inconsistent immudb state, error %v", err)
+			cancel()
+			os.Exit(inconsistentStateExitCode)
+		}
+	},
+}
+
+func init() {
+	addImmudbFlags(monitorCmd)
+	monitorCmd.PersistentFlags().DurationVar(&monitorInterval, "interval", time.Minute, "state check interval")
+	monitorCmd.PersistentFlags().StringVar(&checkpointsPath, "checkpoints", "checkpoints.log", "signed checkpoints file path")
+	monitorCmd.PersistentFlags().StringVar(&checkpointSigningKey, "checkpoint-signing-key", "", "base64 encoded 32 bytes ed25519 seed signing checkpoints")
+	monitorCmd.PersistentFlags().StringVar(&alertWebhook, "alert-webhook", "", "url inconsistency alerts are posted to as json")
+	monitorCmd.PersistentFlags().StringVar(&serverSigningPubKey, "server-signing-pub-key", "", "immudb server signing public key file, refuses unsigned or wrongly signed states")
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quotaPolicy         string
+	quotaReloadInterval time.Duration
+)
+
+func addQuotaFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&quotaPolicy, "quota-policy", "", "json rate limits and daily quotas policy file, by principal, API key and bucket, unlimited by default")
+	cmd.PersistentFlags().DurationVar(&quotaReloadInterval, "quota-reload-interval", time.Second*10, "quota policy file changes check interval")
+}
+
+// buildLimiter returns quota limiter, its policy file gets reloaded on changes until context is done
+func buildLimiter(ctx context.Context) *quota.Limiter {
+	if quotaPolicy == "" {
+		return quota.NewLimiter(&quota.Policy{})
+	}
+
+	p, err := quota.LoadPolicy(quotaPolicy)
+	if err != nil {
+		log.Fatalln("Unable to load quota policy, error:", err)
+	}
+	l := quota.NewLimiter(p)
+	go l.Watch(ctx, quotaPolicy, quotaReloadInterval)
+	log.Printf("Quota policy %s enforced", quotaPolicy)
+
+	return l
+}
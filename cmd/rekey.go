@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/marcosQuesada/log-api/internal/envelope"
+	"github.com/marcosQuesada/log-api/internal/immudb"
+	"github.com/marcosQuesada/log-api/internal/rekey"
+	"github.com/marcosQuesada/log-api/internal/signing"
+	"github.com/marcosQuesada/log-api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rekeyTenant    string
+	rekeyBatchSize int
+	rekeyDryRun    bool
+)
+
+// migrateKeysCmd rewrites legacy log line keys on the versioned key encoding
+var migrateKeysCmd = &cobra.Command{
+	Use:   "migrate-keys",
+	Short: "rewrite legacy log line keys",
+	Long: `rewrite log lines legacy source_unixnano keys on the versioned key encoding, bucket lines keep its bucket,
+found from bucket sorted set entries even without bucket metadata, and legacy keys without bucket are found by a key
+scan. Values are re-encrypted and re-signed on its new key, legacy keys
+get logically deleted on immudb so their history remains available. Legacy keys left are reported.
+Signed lines get signed by the server key, without --signing-key the migration stops on them.
+Interrupted migrations resume by running them again`,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := immudbDatabase
+		if rekeyTenant != "" {
+			if err := tenant.Validate(rekeyTenant); err != nil {
+				log.Fatalln(err)
+			}
+			database = rekeyTenant
+		}
+
+		var ks envelope.KeyStore
+		if encryptionKeyStore != "" {
+			ks = buildKeyStore()
+		}
+
+		ctx := context.Background()
+		cl, err := openClient(ctx, database)
+		if err != nil {
+			log.Fatalf("unable to open database %s, error %v", database, err)
+		}
+		defer cl.CloseSession(ctx)
+
+		repo := immudb.NewRepository(cl).WithRetention(buildRetentionPolicies(), false)
+		if err := repo.Initialize(ctx); err != nil {
+			log.Fatalf("unable to initialize repository, error %v", err)
+		}
+
+		// signatures get decoded on reads even without keys, so signed lines are found and refused without server key
+		sk := buildSigningKeys()
+		if sk == nil {
+			sk, _ = signing.NewKeys(nil, nil)
+		}
+		m := rekey.NewMigrator(decorateLogs(repo, database, ks, sk), repo, rekeyBatchSize)
+		if signingKey == "" {
+			m.WithoutServerSigning()
+		}
+		rep, err := m.Migrate(ctx, rekeyDryRun)
+		if rep != nil {
+			for _, mv := range rep.Moves {
+				log.Printf("  %s: %s -> %s", mv.Bucket, mv.From, mv.To)
+			}
+		}
+		if err != nil {
+			log.Fatalf("unable to migrate keys, error %v", err)
+		}
+
+		for _, k := range rep.Left {
+			log.Printf("  left: %s", k)
+		}
+
+		if rekeyDryRun {
+			log.Printf("Would rewrite %d legacy keys, %d legacy keys would be left", len(rep.Moves), len(rep.Left))
+			return
+		}
+		log.Printf("Rewrote %d legacy keys, %d already rewritten by an interrupted migration, %d legacy keys left", rep.Migrated, rep.Reused, len(rep.Left))
+	},
+}
+
+func init() {
+	addImmudbFlags(migrateKeysCmd)
+	addRetentionFlags(migrateKeysCmd)
+	addSigningFlags(migrateKeysCmd)
+	migrateKeysCmd.PersistentFlags().StringVar(&rekeyTenant, "tenant", "", "migrate tenant database, empty migrates immudb-database")
+	migrateKeysCmd.PersistentFlags().IntVar(&rekeyBatchSize, "batch-size", rekey.DefaultBatchSize, "log lines rewritten per transaction")
+	migrateKeysCmd.PersistentFlags().BoolVar(&rekeyDryRun, "dry-run", false, "report legacy keys without rewriting them")
+	migrateKeysCmd.PersistentFlags().StringVar(&encryptionKeyStore, "encryption-keystore", "", "data keys store file path, rewritten values get encrypted on its new key")
+	migrateKeysCmd.PersistentFlags().StringVar(&encryptionMasterKey, "encryption-master-key", "", "base64 encoded 32 bytes master key wrapping data keys")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/immudb"
+	"github.com/marcosQuesada/log-api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retentionDryRun bool
+	retentionTenant string
+)
+
+// retentionCmd groups bucket retention commands
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "bucket retention policies",
+	Long:  `bucket retention policies, expired log lines are hidden from reads and pruned from buckets`,
+}
+
+// retentionApplyCmd prunes expired log lines from buckets with retention policy
+var retentionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "prune expired log lines",
+	Long: `prune expired log lines, they get logically deleted on immudb so their history remains available.
+Buckets retention set on its metadata is loaded too, taking over retention flags on the same bucket`,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := immudbDatabase
+		if retentionTenant != "" {
+			if err := tenant.Validate(retentionTenant); err != nil {
+				log.Fatalln(err)
+			}
+			database = retentionTenant
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		cl, err := openClient(ctx, database)
+		if err != nil {
+			log.Fatalf("unable to open database %s, error %v", database, err)
+		}
+		defer cl.CloseSession(context.Background())
+
+		repo := immudb.NewRepository(cl).WithRetention(buildRetentionPolicies(), false)
+		if err := repo.Initialize(ctx); err != nil {
+			log.Fatalf("unable to initialize repository, error %v", err)
+		}
+
+		res, err := repo.ApplyRetention(ctx, retentionDryRun)
+		if err != nil {
+			log.Fatalf("unable to apply retention policies, error %v", err)
+		}
+
+		action := "Pruned"
+		if retentionDryRun {
+			action = "Would prune"
+		}
+		for _, e := range res {
+			log.Printf("%s %d log lines from bucket %s older than %s", action, len(e.Keys), e.Bucket, e.Cutoff.Format(time.RFC3339))
+			for _, k := range e.Keys {
+				log.Printf("  %s", k)
+			}
+		}
+	},
+}
+
+func init() {
+	addImmudbFlags(retentionCmd)
+	addRetentionFlags(retentionCmd)
+	retentionApplyCmd.PersistentFlags().BoolVar(&retentionDryRun, "dry-run", false, "report expired log lines without pruning them")
+	retentionApplyCmd.PersistentFlags().StringVar(&retentionTenant, "tenant", "", "prune tenant database, empty prunes immudb-database")
+	retentionCmd.AddCommand(retentionApplyCmd)
+}
@@ -27,5 +27,16 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(cli.ClientCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(retentionCmd)
+	rootCmd.AddCommand(tenantCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(auditVerifyCmd)
+	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(migrateKeysCmd)
 
+	// flags not given on command line are set from LOG_API_* environment variables, then from config file
+	rootCmd.PersistentPreRunE = loadConfig
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "yaml or toml config file keyed by flag names, LOG_API_CONFIG by default")
 }
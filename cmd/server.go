@@ -2,24 +2,41 @@ package cmd
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/client"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/marcosQuesada/log-api/internal/envelope"
+	"github.com/marcosQuesada/log-api/internal/health"
 	"github.com/marcosQuesada/log-api/internal/immudb"
 	"github.com/marcosQuesada/log-api/internal/jwt"
+	"github.com/marcosQuesada/log-api/internal/metrics"
 	"github.com/marcosQuesada/log-api/internal/proto"
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/retention"
 	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/signing"
+	"github.com/marcosQuesada/log-api/internal/tenant"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const maxReceivedMessageSize = 1024 * 1024 * 20
@@ -34,6 +51,34 @@ var (
 	immudbDatabase string
 	immudbPort     int
 	immudbHost     string
+
+	immudbKeepAlive  time.Duration
+	immudbMaxBackoff time.Duration
+
+	encryptionKeyStore  string
+	encryptionMasterKey string
+
+	signingKey        string
+	signingSourceKeys string
+
+	retentionPolicies           string
+	retentionExpirationMetadata bool
+
+	autoCreateBuckets bool
+
+	bucketReadConcurrency int
+	maxResultSize         int
+
+	maxValueSize int
+	maxBatchSize int
+
+	requireTenant bool
+	userTenants   []string
+
+	metricsHost string
+	metricsPort int
+
+	legacyKeyReads bool
 )
 
 // serverCmd represents the server command
@@ -41,34 +86,86 @@ var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "api server",
 	Long:  `api server`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateServerConfig()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Printf("API server started, gRPC port %d HTTP gRPC-Gateway %d", grpcPort, httpPort)
 
+		shutdownTracing := setupTracing()
+
+		var stopEmbeddedDB func() error
+		if embeddedDB != "" {
+			stopEmbeddedDB = startEmbeddedDB()
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
 		if err != nil {
 			log.Fatalln("Unable to start grpc listener, error:", err)
 		}
-		defer lis.Close()
 
 		jwtProc := jwt.NewProcessor(jwtSecret)
 		auth := proto.NewJWTAuthAdapter(jwtProc)
 
-		cl := buildClient()
-		repo := immudb.NewRepository(cl)
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		if err := repo.Initialize(ctx); err != nil {
-			cancel()
-			log.Fatalf("Unable to initialize log lines repository, error %v", err)
+		tlsConfig := serverTLS()
+		var creds []grpc.ServerOption
+		if tlsConfig != nil {
+			creds = append(creds, grpc.Creds(credentials.NewTLS(tlsConfig)))
+			log.Printf("TLS enabled, certificate %s", tlsCert)
 		}
-		cancel()
+		if clientCA != "" {
+			auth.WithClientCertificates()
+			log.Printf("mTLS client authentication enabled, client CA %s", clientCA)
+		}
+
+		var ks envelope.KeyStore
+		if encryptionKeyStore != "" {
+			ks = buildKeyStore()
+			log.Printf("Log line values encryption enabled, key store %s", encryptionKeyStore)
+		}
+
+		sk := buildSigningKeys()
+		if sk != nil {
+			log.Printf("Log line signatures enabled, %d signing keys", len(sk.Keys()))
+		}
+
+		router := tenant.NewRouter(storageStackBuilder(ks, sk), immudbDatabase, requireTenant)
+		if !requireTenant {
+			go initDefaultStack(router)
+		}
+		hc := health.New().WithCheck("storage", router.Ready)
 
-		opt := grpc.UnaryInterceptor(auth.Interceptor)
-		s := grpc.NewServer(opt)
-		svc := service.NewLogService(repo)
+		limiter := buildLimiter(ctx)
+		quotas := proto.NewQuotaAdapter(limiter)
+
+		repo := tenant.NewRepository(router)
+		buckets := service.NewBucketService(repo, autoCreateBuckets)
+
+		s := grpc.NewServer(append(creds,
+			grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), metrics.UnaryInterceptor, auth.Interceptor, quotas.Interceptor, router.Interceptor),
+			grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), metrics.StreamInterceptor, auth.StreamInterceptor, quotas.StreamInterceptor, router.StreamInterceptor),
+		)...)
+		svc := service.NewLogService(repo).WithBucketGuard(buckets).
+			WithLineLimits(service.LineLimits{MaxValueSize: maxValueSize, MaxBatchSize: maxBatchSize})
+		if sk != nil {
+			svc.WithSignatures(sk)
+		}
+		if legacyKeyReads {
+			svc.WithLegacyKeyReads()
+		}
 		v1.RegisterLogServiceServer(s, svc)
-		v1.RegisterAuthServiceServer(s, service.NewAuth(jwtProc, service.NewAuthFakeRepository()))
+		v1.RegisterBucketServiceServer(s, buckets)
+		v1.RegisterAuditServiceServer(s, service.NewAuditService(repo))
+		v1.RegisterAuthServiceServer(s, service.NewAuth(jwtProc, service.NewAuthFakeRepository(userTenants...)))
+		v1.RegisterQuotaServiceServer(s, service.NewQuotaService(limiter))
+
+		hs := grpchealth.NewServer()
+		healthpb.RegisterHealthServer(s, hs)
+		go hc.Watch(ctx, hs, healthWatchInterval)
 
-		// @TODO: Signal chan, add graceful gRPC & http shutdown
 		go func() {
 			if err := s.Serve(lis); err != nil {
 				log.Fatalf("error serving %v", err)
@@ -80,7 +177,9 @@ var serverCmd = &cobra.Command{
 			context.Background(),
 			fmt.Sprintf("%s:%d", "localhost", grpcPort),
 			grpc.WithBlock(),
-			grpc.WithInsecure(),
+			loopbackCredentials(),
+			grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
 			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxReceivedMessageSize), grpc.MaxCallSendMsgSize(maxReceivedMessageSize)),
 		)
 		if err != nil {
@@ -92,87 +191,281 @@ var serverCmd = &cobra.Command{
 			log.Fatalln("Failed to register log service http grpc gateway:", err)
 		}
 
+		if err = v1.RegisterBucketServiceHandler(context.Background(), mux, conn); err != nil {
+			log.Fatalln("Failed to register bucket service http grpc gateway:", err)
+		}
+
+		if err = v1.RegisterAuditServiceHandler(context.Background(), mux, conn); err != nil {
+			log.Fatalln("Failed to register audit service http grpc gateway:", err)
+		}
+
 		if err = v1.RegisterAuthServiceHandler(context.Background(), mux, conn); err != nil {
 			log.Fatalln("Failed to register auth service http grpc gateway:", err)
 		}
 
+		if err = v1.RegisterQuotaServiceHandler(context.Background(), mux, conn); err != nil {
+			log.Fatalln("Failed to register quota service http grpc gateway:", err)
+		}
+
 		gws := &http.Server{
 			Addr:         fmt.Sprintf("0.0.0.0:%d", httpPort),
-			Handler:      mux,
+			Handler:      hc.Handler(otelhttp.NewHandler(mux, "grpc-gateway")),
 			ReadTimeout:  10 * time.Second,
 			WriteTimeout: 10 * time.Second,
+			TLSConfig:    tlsConfig,
+		}
+
+		go func() {
+			serve := gws.ListenAndServe
+			if tlsConfig != nil {
+				serve = func() error { return gws.ListenAndServeTLS("", "") }
+			}
+			if err := serve(); !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalln(err)
+			}
+		}()
+
+		var ms *http.Server
+		if metricsPort != 0 {
+			ms = &http.Server{
+				Addr:         net.JoinHostPort(metricsHost, strconv.Itoa(metricsPort)),
+				Handler:      metrics.Handler(),
+				ReadTimeout:  10 * time.Second,
+				WriteTimeout: 10 * time.Second,
+			}
+			go func() {
+				if err := ms.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					log.Fatalln(err)
+				}
+			}()
+			log.Printf("Serving metrics on %s/metrics", ms.Addr)
+		}
+
+		<-ctx.Done()
+		stop()
+		log.Printf("Shutting down, draining in-flight requests up to %v", shutdownTimeout)
+		hs.Shutdown()
+
+		dctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		drain(dctx, gws, s)
+		cancel()
+		if ms != nil {
+			_ = ms.Close()
+		}
+		_ = conn.Close()
+
+		cctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		router.Close(cctx)
+		cancel()
+
+		exitCode := 0
+		if stopEmbeddedDB != nil {
+			if err := stopEmbeddedDB(); err != nil {
+				log.Printf("Unable to stop embedded immudb, error %v", err)
+				exitCode = 1
+			}
 		}
+		shutdownTracing()
 
-		log.Fatalln(gws.ListenAndServe())
+		log.Println("API server stopped")
+		os.Exit(exitCode)
 	},
 }
 
 func init() {
 	serverCmd.PersistentFlags().IntVar(&grpcPort, "grpc-port", 9000, "grpc port")
 	serverCmd.PersistentFlags().IntVar(&httpPort, "http-port", 9090, "http grpc gateway port")
-	serverCmd.PersistentFlags().StringVar(&jwtSecret, "jwt-secret", "jwt-secret", "jwt secret signature")
-	serverCmd.PersistentFlags().StringVar(&immudbUserName, "immudb-user-name", "immudb", "immudb user name")
-	serverCmd.PersistentFlags().StringVar(&immudbPassword, "immudb-password", "immudb", "immudb password")
-	serverCmd.PersistentFlags().StringVar(&immudbDatabase, "immudb-database", "defaultdb", "immudb database")
-	serverCmd.PersistentFlags().StringVar(&immudbHost, "immudb-host", "localhost", "immudb host")
-	serverCmd.PersistentFlags().IntVar(&immudbPort, "immudb-port", 3322, "immudb port")
-
-	if p := os.Getenv("jwt-secret"); p != "" {
-		jwtSecret = p
-	}
-	if p := os.Getenv("immudb-user-name"); p != "" {
-		immudbUserName = p
-	}
-	if p := os.Getenv("immudb-password"); p != "" {
-		immudbPassword = p
+	serverCmd.PersistentFlags().StringVar(&metricsHost, "metrics-host", "localhost", "prometheus metrics listener host, metrics are not served on the gateway port")
+	serverCmd.PersistentFlags().IntVar(&metricsPort, "metrics-port", 9091, "prometheus metrics listener port, 0 disables metrics")
+	serverCmd.PersistentFlags().StringVar(&jwtSecret, "jwt-secret", defaultJWTSecret, "jwt secret signature, production mode refuses the default one")
+	serverCmd.PersistentFlags().StringVar(&mode, "mode", modeDevelopment, "development or production, production refuses default secrets")
+	addImmudbFlags(serverCmd)
+	addTLSFlags(serverCmd)
+	serverCmd.PersistentFlags().DurationVar(&shutdownTimeout, "shutdown-timeout", time.Second*30, "max time draining in-flight requests on SIGINT and SIGTERM, remaining ones get cancelled")
+	addTracingFlags(serverCmd)
+	addRetentionFlags(serverCmd)
+	addQuotaFlags(serverCmd)
+	serverCmd.PersistentFlags().BoolVar(&requireTenant, "require-tenant", false, "refuse requests whose token carries no tenant, otherwise they are routed to immudb-database")
+	serverCmd.PersistentFlags().BoolVar(&legacyKeyReads, "legacy-key-reads", true, "scan legacy source_unixnano keys on source prefix reads too, disable once migrate-keys leaves no legacy keys")
+	serverCmd.PersistentFlags().StringSliceVar(&userTenants, "user-tenants", nil, "tenants users are allowed to log in to, logins requesting any other tenant are refused")
+	serverCmd.PersistentFlags().BoolVar(&autoCreateBuckets, "auto-create-buckets", true, "create unknown buckets on log lines ingestion, otherwise ingestion requires an existing bucket")
+	serverCmd.PersistentFlags().BoolVar(&retentionExpirationMetadata, "retention-expiration-metadata", false, "write immudb expiration metadata on log lines from buckets with retention policy")
+	serverCmd.PersistentFlags().StringVar(&encryptionKeyStore, "encryption-keystore", "", "data keys store file path, enables log line values encryption")
+	serverCmd.PersistentFlags().StringVar(&encryptionMasterKey, "encryption-master-key", "", "base64 encoded 32 bytes master key wrapping data keys")
+	addSigningFlags(serverCmd)
+	addStorageFlags(serverCmd)
+	addEmbeddedFlags(serverCmd)
+	serverCmd.PersistentFlags().IntVar(&bucketReadConcurrency, "bucket-read-concurrency", 8, "immudb calls in flight from bucket reads, 0 leaves them unbounded")
+	serverCmd.PersistentFlags().IntVar(&maxResultSize, "max-result-size", 10000, "max log lines returned by a bucket read, bigger buckets must be exported, 0 leaves them unbounded")
+	serverCmd.PersistentFlags().IntVar(&maxValueSize, "max-value-size", 256*1024, "max ingested log line value bytes, 0 leaves them unbounded")
+	serverCmd.PersistentFlags().IntVar(&maxBatchSize, "max-batch-size", 500, "max log lines on a batch, immudb bounds transaction entries too, 0 leaves them unbounded")
+}
+
+func addImmudbFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&immudbUserName, "immudb-user-name", "immudb", "immudb user name")
+	cmd.PersistentFlags().StringVar(&immudbPassword, "immudb-password", defaultImmudbPassword, "immudb password")
+	cmd.PersistentFlags().StringVar(&immudbDatabase, "immudb-database", "defaultdb", "immudb database")
+	cmd.PersistentFlags().StringVar(&immudbHost, "immudb-host", "localhost", "immudb host")
+	cmd.PersistentFlags().IntVar(&immudbPort, "immudb-port", 3322, "immudb port")
+	cmd.PersistentFlags().DurationVar(&immudbKeepAlive, "immudb-keep-alive", time.Second*20, "immudb session keep alive and check interval")
+	cmd.PersistentFlags().DurationVar(&immudbMaxBackoff, "immudb-max-backoff", time.Second*10, "max backoff between immudb session reopen retries")
+	addImmudbTLSFlags(cmd)
+}
+
+func addSigningFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&signingKey, "signing-key", "", "base64 encoded 32 bytes ed25519 seed, enables server signatures on ingested log lines")
+	cmd.PersistentFlags().StringVar(&signingSourceKeys, "signing-source-keys", "", "json file mapping sources to its base64 encoded ed25519 public key, enables client signed log lines")
+}
+
+func addRetentionFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&retentionPolicies, "retention", "", "bucket retention policies, as debug=7d,audit=forever")
+}
+
+func buildRetentionPolicies() *retention.Policies {
+	p, err := retention.Parse(retentionPolicies)
+	if err != nil {
+		log.Fatalln("Unable to parse retention policies, error:", err)
 	}
-	if p := os.Getenv("immudb-database"); p != "" {
-		immudbDatabase = p
+
+	return p
+}
+
+func buildClient() client.ImmuClient {
+	cl, err := openClient(context.Background(), immudbDatabase)
+	if err != nil {
+		log.Fatalln("Failed to open session on Immudb server, Reason:", err)
 	}
-	if p := os.Getenv("immudb-host"); p != "" {
-		immudbHost = p
+
+	return cl
+}
+
+// buildConn opens a long lived immudb session on default database, it gets reopened once lost
+func buildConn() *immudb.Conn {
+	conn, err := openConn(context.Background(), immudbDatabase)
+	if err != nil {
+		log.Fatalln("Failed to open session on Immudb server, Reason:", err)
 	}
 
-	if p := os.Getenv("grpc-port"); p != "" {
-		gp, err := strconv.ParseInt(p, 10, 64)
-		if err != nil {
-			log.Fatalf("unable to parse grpc port, got %s error %v", p, err)
-		}
-		grpcPort = int(gp)
+	return conn
+}
+
+// openConn opens an immudb session on database kept alive and reopened once lost
+func openConn(ctx context.Context, database string) (*immudb.Conn, error) {
+	conn := immudb.NewConn(clientOptions(database)).
+		WithKeepAlive(immudbKeepAlive).
+		WithBackoff(time.Millisecond*100, immudbMaxBackoff)
+	if err := conn.Open(ctx); err != nil {
+		return nil, err
 	}
-	if p := os.Getenv("http-port"); p != "" {
-		hp, err := strconv.ParseInt(p, 10, 64)
-		if err != nil {
-			log.Fatalf("unable to parse http port, got %s error %v", p, err)
-		}
-		httpPort = int(hp)
+
+	return conn, nil
+}
+
+// openClient opens an immudb session on database, used by one shot commands
+func openClient(ctx context.Context, database string) (client.ImmuClient, error) {
+	o := clientOptions(database)
+	cl := client.NewClient().WithOptions(o)
+	if err := cl.OpenSession(ctx, []byte(o.Username), []byte(o.Password), o.Database); err != nil {
+		return nil, fmt.Errorf("failed to OpenSession on Immudb server, error %w", err)
 	}
-	if p := os.Getenv("immudb-port"); p != "" {
-		ip, err := strconv.ParseInt(p, 10, 64)
-		if err != nil {
-			log.Fatalf("unable to parse immudb port, got %s error %v", p, err)
-		}
-		immudbPort = int(ip)
+
+	if _, err := cl.UseDatabase(ctx, &schema.Database{DatabaseName: o.Database}); err != nil {
+		return nil, fmt.Errorf("failed to use database %s on Immudb server, error %w", database, err)
 	}
+
+	return cl, nil
 }
 
-func buildClient() client.ImmuClient {
+func clientOptions(database string) *client.Options {
 	o := client.DefaultOptions()
 	o.Username = immudbUserName
 	o.Password = immudbPassword
-	o.Database = immudbDatabase
+	o.Database = database
 	o.Port = immudbPort
 	o.Address = immudbHost
+	o.DialOptions = append([]grpc.DialOption{immudbCredentials()},
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	)
 
-	cl := client.NewClient().WithOptions(o)
-	ctx := context.Background()
-	if err := cl.OpenSession(ctx, []byte(o.Username), []byte(o.Password), o.Database); err != nil {
-		log.Fatalln("Failed to OpenSession on Immudb server, Reason:", err.Error())
+	return o
+}
+
+// databaseNotExists matches immudb session errors on missing databases, its status code is not specific
+const databaseNotExists = "database does not exist"
+
+// tenantStackBuilder builds immudb tenant repositories, each tenant database gets its own retention policies and data key scope
+func tenantStackBuilder(ks envelope.KeyStore, sk *signing.Keys) tenant.Builder {
+	return func(ctx context.Context, database string) (*tenant.Stack, error) {
+		conn, err := openConn(ctx, database)
+		if err != nil && strings.Contains(err.Error(), databaseNotExists) {
+			return nil, fmt.Errorf("database %s not found, error %w", database, tenant.ErrUnknownTenant)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		immuRepo := immudb.NewConnRepository(conn).WithRetention(buildRetentionPolicies(), retentionExpirationMetadata).
+			WithReadLimits(bucketReadConcurrency, maxResultSize)
+		if err := immuRepo.Initialize(ctx); err != nil {
+			_ = conn.Close(ctx)
+			return nil, err
+		}
+
+		return &tenant.Stack{Close: conn.Close, Ready: conn.Ready, Logs: decorateLogs(immuRepo, database, ks, sk), Buckets: immuRepo, Audit: immuRepo}, nil
 	}
+}
 
-	if _, err := cl.UseDatabase(ctx, &schema.Database{DatabaseName: o.Database}); err != nil {
-		log.Fatalln("Failed to use the database  on Immudb server, Reason:", err)
+// tenantKeyScope returns tenant data keys scope prefix, default database keeps unscoped data keys
+func tenantKeyScope(database string) string {
+	if database == immudbDatabase {
+		return ""
 	}
+	return database + "/"
+}
 
-	return cl
+func buildKeyStore() envelope.KeyStore {
+	mk, err := base64.StdEncoding.DecodeString(encryptionMasterKey)
+	if err != nil {
+		log.Fatalln("Unable to decode encryption master key, error:", err)
+	}
+
+	ks, err := envelope.NewFileKeyStore(encryptionKeyStore, mk)
+	if err != nil {
+		log.Fatalln("Unable to open encryption key store, error:", err)
+	}
+
+	return ks
+}
+
+// buildSigningKeys returns log line signing keys, nil when signatures are not enabled
+func buildSigningKeys() *signing.Keys {
+	if signingKey == "" && signingSourceKeys == "" {
+		return nil
+	}
+
+	var seed []byte
+	if signingKey != "" {
+		s, err := base64.StdEncoding.DecodeString(signingKey)
+		if err != nil {
+			log.Fatalln("Unable to decode signing key, error:", err)
+		}
+		seed = s
+	}
+
+	var sources map[string]ed25519.PublicKey
+	if signingSourceKeys != "" {
+		s, err := signing.LoadSourceKeys(signingSourceKeys)
+		if err != nil {
+			log.Fatalln("Unable to load signing source keys, error:", err)
+		}
+		sources = s
+	}
+
+	sk, err := signing.NewKeys(seed, sources)
+	if err != nil {
+		log.Fatalln("Unable to build signing keys, error:", err)
+	}
+
+	return sk
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const closeTimeout = time.Second * 5
+
+var shutdownTimeout time.Duration
+
+// drain stops accepting requests and waits for in-flight ones until ctx is done, remaining ones get cancelled then.
+// Gateway goes first, as its requests are in-flight gRPC calls too
+func drain(ctx context.Context, gws *http.Server, s *grpc.Server) {
+	if err := gws.Shutdown(ctx); err != nil {
+		log.Printf("Gateway requests not drained, error %v", err)
+		_ = gws.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("gRPC requests not drained, error %v", ctx.Err())
+		s.Stop()
+		<-done
+	}
+}
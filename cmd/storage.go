@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/marcosQuesada/log-api/internal/envelope"
+	"github.com/marcosQuesada/log-api/internal/memory"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/signing"
+	"github.com/marcosQuesada/log-api/internal/sqlite"
+	"github.com/marcosQuesada/log-api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+const (
+	storageImmudb = "immudb"
+	storageMemory = "memory"
+	storageSQLite = "sqlite"
+)
+
+var (
+	storage   string
+	sqliteDir string
+)
+
+func addStorageFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&storage, "storage", storageImmudb, "log lines storage, immudb, memory or sqlite. Audit bundles and retention pruning require immudb")
+	cmd.PersistentFlags().StringVar(&sqliteDir, "sqlite-dir", "data", "sqlite storage directory, each tenant database gets its own file")
+}
+
+// storageStackBuilder builds tenant repositories on the selected storage
+func storageStackBuilder(ks envelope.KeyStore, sk *signing.Keys) tenant.Builder {
+	switch storage {
+	case storageImmudb:
+		return tenantStackBuilder(ks, sk)
+	case storageMemory:
+		return memoryStackBuilder(ks, sk)
+	case storageSQLite:
+		return sqliteStackBuilder(ks, sk)
+	}
+
+	log.Fatalf("Unknown storage %s, expected %s, %s or %s", storage, storageImmudb, storageMemory, storageSQLite)
+	return nil
+}
+
+// memoryStackBuilder builds in memory tenant repositories, data is lost on restart
+func memoryStackBuilder(ks envelope.KeyStore, sk *signing.Keys) tenant.Builder {
+	return func(ctx context.Context, database string) (*tenant.Stack, error) {
+		repo := memory.NewRepository()
+		return &tenant.Stack{Logs: decorateLogs(repo, database, ks, sk), Buckets: repo}, nil
+	}
+}
+
+// sqliteStackBuilder builds tenant repositories on its own sqlite database file
+func sqliteStackBuilder(ks envelope.KeyStore, sk *signing.Keys) tenant.Builder {
+	return func(ctx context.Context, database string) (*tenant.Stack, error) {
+		if err := os.MkdirAll(sqliteDir, 0700); err != nil {
+			return nil, fmt.Errorf("unable to create sqlite directory %s, error %w", sqliteDir, err)
+		}
+
+		db, err := sqlite.Open(filepath.Join(sqliteDir, database+".db"))
+		if err != nil {
+			return nil, err
+		}
+
+		repo := sqlite.NewRepository(db)
+		if err := repo.Initialize(ctx); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+
+		return &tenant.Stack{
+			Close:   func(context.Context) error { return db.Close() },
+			Logs:    decorateLogs(repo, database, ks, sk),
+			Buckets: repo,
+		}, nil
+	}
+}
+
+// decorateLogs wraps storage log lines repository with values encryption and signatures when enabled.
+// Signatures wrap plain values, so they get encrypted with them
+func decorateLogs(logs service.Repository, database string, ks envelope.KeyStore, sk *signing.Keys) service.Repository {
+	if ks != nil {
+		logs = envelope.NewRepository(logs, ks).WithScope(tenantKeyScope(database))
+	}
+	if sk != nil {
+		logs = signing.NewRepository(logs, sk)
+	}
+
+	return logs
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/immudb"
+	"github.com/marcosQuesada/log-api/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+var tenantName string
+
+// tenantCmd groups tenant provisioning commands
+var tenantCmd = &cobra.Command{
+	Use:   "tenant",
+	Short: "tenant provisioning",
+	Long:  `tenant provisioning, each tenant stores its log lines on its own immudb database`,
+}
+
+// tenantCreateCmd creates and initializes tenant database
+var tenantCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create tenant",
+	Long:  `create tenant database and initialize its log lines repository`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := tenant.Validate(tenantName); err != nil {
+			log.Fatalln(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		cl := buildClient()
+		defer cl.CloseSession(ctx)
+
+		if _, err := cl.CreateDatabaseV2(ctx, tenantName, &schema.DatabaseNullableSettings{}); err != nil {
+			log.Fatalf("unable to create tenant %s database, error %v", tenantName, err)
+		}
+
+		tc, err := openClient(ctx, tenantName)
+		if err != nil {
+			log.Fatalf("unable to open tenant %s database session, error %v", tenantName, err)
+		}
+		defer tc.CloseSession(ctx)
+
+		if err := immudb.NewRepository(tc).Initialize(ctx); err != nil {
+			log.Fatalf("unable to initialize tenant %s repository, error %v", tenantName, err)
+		}
+
+		log.Printf("Created tenant %s", tenantName)
+	},
+}
+
+// tenantListCmd lists immudb databases
+var tenantListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list tenants",
+	Long:  `list tenants, as immudb databases`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		cl := buildClient()
+		defer cl.CloseSession(ctx)
+
+		res, err := cl.DatabaseListV2(ctx)
+		if err != nil {
+			log.Fatalf("unable to list databases, error %v", err)
+		}
+
+		for _, db := range res.Databases {
+			if tenant.Validate(db.Name) != nil {
+				continue
+			}
+			log.Printf("Tenant %s loaded %t", db.Name, db.Loaded)
+		}
+	},
+}
+
+func init() {
+	addImmudbFlags(tenantCmd)
+	tenantCreateCmd.PersistentFlags().StringVar(&tenantName, "name", "", "tenant id, lowercase alphanumeric")
+	tenantCmd.AddCommand(tenantCreateCmd, tenantListCmd)
+}
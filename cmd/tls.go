@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"log"
+
+	"github.com/marcosQuesada/log-api/internal/tlsconfig"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	tlsCert  string
+	tlsKey   string
+	clientCA string
+
+	immudbTLS     bool
+	immudbTLSCA   string
+	immudbTLSCert string
+	immudbTLSKey  string
+)
+
+func addTLSFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&tlsCert, "tls-cert", "", "PEM server certificate file, enables TLS on gRPC and HTTP gateway ports")
+	cmd.PersistentFlags().StringVar(&tlsKey, "tls-key", "", "PEM server private key file")
+	cmd.PersistentFlags().StringVar(&clientCA, "client-ca", "", "PEM client CA file, enables mTLS client authentication, requests without token get its certificate subject as principal")
+}
+
+func addImmudbTLSFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&immudbTLS, "immudb-tls", false, "connect to immudb over TLS")
+	cmd.PersistentFlags().StringVar(&immudbTLSCA, "immudb-tls-ca", "", "PEM CA file verifying immudb certificate, system roots by default")
+	cmd.PersistentFlags().StringVar(&immudbTLSCert, "immudb-tls-cert", "", "PEM client certificate file presented to immudb")
+	cmd.PersistentFlags().StringVar(&immudbTLSKey, "immudb-tls-key", "", "PEM client private key file presented to immudb")
+}
+
+// serverTLS returns server TLS config, nil when TLS is not enabled
+func serverTLS() *tls.Config {
+	if tlsCert == "" && tlsKey == "" {
+		if clientCA != "" {
+			log.Fatalln("Client CA requires TLS server certificate and key")
+		}
+		return nil
+	}
+
+	c, err := tlsconfig.Server(tlsCert, tlsKey, clientCA)
+	if err != nil {
+		log.Fatalln("Unable to build server TLS config, error:", err)
+	}
+
+	return c
+}
+
+// loopbackCredentials returns gateway dial credentials to its own gRPC server
+func loopbackCredentials() grpc.DialOption {
+	if tlsCert == "" {
+		return grpc.WithInsecure()
+	}
+
+	c, err := tlsconfig.Loopback(tlsCert)
+	if err != nil {
+		log.Fatalln("Unable to build gateway TLS config, error:", err)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(c))
+}
+
+// immudbCredentials returns immudb dial credentials
+func immudbCredentials() grpc.DialOption {
+	if !immudbTLS {
+		return grpc.WithInsecure()
+	}
+
+	c, err := tlsconfig.Client(immudbTLSCA, immudbTLSCert, immudbTLSKey, "")
+	if err != nil {
+		log.Fatalln("Unable to build immudb TLS config, error:", err)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(c))
+}
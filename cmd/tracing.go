@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceExporter string
+	traceFile     string
+	traceEndpoint string
+	traceInsecure bool
+	traceRatio    float64
+)
+
+func addTracingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&traceExporter, "trace-exporter", tracing.ExporterNone, "trace exporter, none, file or otlp")
+	cmd.PersistentFlags().StringVar(&traceFile, "trace-file", "traces.json", "file exporter spans file, one JSON span per line")
+	cmd.PersistentFlags().StringVar(&traceEndpoint, "trace-endpoint", "localhost:4317", "otlp exporter collector gRPC endpoint")
+	cmd.PersistentFlags().BoolVar(&traceInsecure, "trace-insecure", true, "send spans to otlp collector without TLS")
+	cmd.PersistentFlags().Float64Var(&traceRatio, "trace-ratio", 1, "sampled traces ratio, incoming sampled traces are always sampled")
+}
+
+// setupTracing installs trace exporter, returned func flushes pending spans
+func setupTracing() func() {
+	shutdown, err := tracing.Setup(context.Background(), &tracing.Options{
+		Exporter: traceExporter,
+		File:     traceFile,
+		Endpoint: traceEndpoint,
+		Insecure: traceInsecure,
+		Ratio:    traceRatio,
+	})
+	if err != nil {
+		log.Fatalln("Unable to setup tracing, error:", err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			log.Printf("Unable to shutdown tracing, error %v", err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/export"
+)
+
+// Bundle layout, state goes first so lines can be verified while they are read, manifest closes the bundle
+const (
+	stateFile    = "state.json"
+	linesDir     = "lines/"
+	manifestFile = "manifest.json"
+)
+
+var ErrInvalidBundle = errors.New("invalid audit bundle")
+
+// State holds immudb state bundle lines are proven against, State is immudb ImmutableState serialized
+type State struct {
+	Database string `json:"database"`
+	TxID     uint64 `json:"tx_id"`
+	Signed   bool   `json:"signed"`
+	State    []byte `json:"state"`
+}
+
+// Line holds an audited log line, Proof is immudb VerifiableEntry serialized
+type Line struct {
+	export.Record
+	Proof []byte `json:"proof"`
+}
+
+// Manifest describes bundle contents
+type Manifest struct {
+	Bucket    string    `json:"bucket"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	CreatedAt time.Time `json:"created_at"`
+	Lines     int       `json:"lines"`
+}
+
+// Writer writes bundle entries as they are received, each line gets its own file
+type Writer struct {
+	tar   *tar.Writer
+	state bool
+	lines int
+}
+
+// NewWriter instantiates bundle writer on top of w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{tar: tar.NewWriter(w)}
+}
+
+// WriteState writes bundle state, it must be written before any line
+func (w *Writer) WriteState(s *State) error {
+	if w.state {
+		return fmt.Errorf("state already written, error %w", ErrInvalidBundle)
+	}
+	w.state = true
+
+	return w.writeJSON(stateFile, s)
+}
+
+// WriteLine writes an audited line
+func (w *Writer) WriteLine(l *Line) error {
+	if !w.state {
+		return fmt.Errorf("line %s before state, error %w", l.Key, ErrInvalidBundle)
+	}
+	w.lines++
+
+	return w.writeJSON(fmt.Sprintf("%s%08d.json", linesDir, w.lines), l)
+}
+
+// Close writes bundle manifest and flushes the archive
+func (w *Writer) Close(m *Manifest) error {
+	m.Lines = w.lines
+	if err := w.writeJSON(manifestFile, m); err != nil {
+		return err
+	}
+
+	return w.tar.Close()
+}
+
+func (w *Writer) writeJSON(name string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s, error %w", name, err)
+	}
+
+	err = w.tar.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(raw)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write %s header, error %w", name, err)
+	}
+
+	if _, err := w.tar.Write(raw); err != nil {
+		return fmt.Errorf("unable to write %s, error %w", name, err)
+	}
+
+	return nil
+}
+
+// reader reads bundle entries in order
+type reader struct {
+	tar *tar.Reader
+}
+
+func newReader(r io.Reader) *reader {
+	return &reader{tar: tar.NewReader(r)}
+}
+
+// next decodes next bundle entry, returns its name or io.EOF once the bundle is consumed
+func (r *reader) next(state *State, line *Line, manifest *Manifest) (string, error) {
+	h, err := r.tar.Next()
+	for err == nil && h.Typeflag == tar.TypeDir {
+		h, err = r.tar.Next()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var v interface{}
+	switch {
+	case h.Name == stateFile:
+		v = state
+	case h.Name == manifestFile:
+		v = manifest
+	case strings.HasPrefix(h.Name, linesDir):
+		v = line
+	default:
+		return "", fmt.Errorf("unexpected file %s, error %w", h.Name, ErrInvalidBundle)
+	}
+
+	if err := json.NewDecoder(r.tar).Decode(v); err != nil {
+		return "", fmt.Errorf("unable to decode %s, error %v, %w", h.Name, err, ErrInvalidBundle)
+	}
+
+	return h.Name, nil
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/export"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestItRefusesLinesBeforeState(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{})
+	if err := w.WriteLine(&Line{Record: export.Record{Key: "foo_0"}}); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestItFailsVerifyingBundlesWithoutManifest(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	if err := w.WriteState(fakeState(t)); err != nil {
+		t.Fatalf("unable to write state, error %v", err)
+	}
+	if err := w.tar.Close(); err != nil {
+		t.Fatalf("unable to close bundle, error %v", err)
+	}
+
+	if _, err := Verify(buf, nil); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestItVerifiesEmptyBundles(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	if err := w.WriteState(fakeState(t)); err != nil {
+		t.Fatalf("unable to write state, error %v", err)
+	}
+	if err := w.Close(&Manifest{Bucket: "fake_bucket"}); err != nil {
+		t.Fatalf("unable to close bundle, error %v", err)
+	}
+
+	res, err := Verify(buf, nil)
+	if err != nil {
+		t.Fatalf("unexpected error verifying bundle, error %v", err)
+	}
+
+	if expected, got := "fake_bucket", res.Manifest.Bucket; expected != got {
+		t.Errorf("buckets do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItRefusesStatesNotMatchingItsDeclaration(t *testing.T) {
+	s := fakeState(t)
+	s.TxID++
+
+	if _, err := ParseState(s); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestItRefusesUnsignedStatesWhenCheckingSignature(t *testing.T) {
+	st, err := ParseState(fakeState(t))
+	if err != nil {
+		t.Fatalf("unable to parse state, error %v", err)
+	}
+
+	if err := CheckSignature(st, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func fakeState(t *testing.T) *State {
+	raw, err := proto.Marshal(&schema.ImmutableState{Db: "defaultdb", TxId: 3, TxHash: make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("unable to marshal state, error %v", err)
+	}
+
+	return &State{Database: "defaultdb", TxID: 3, State: raw}
+}
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrInvalidProof     = errors.New("log line proof does not verify")
+	ErrProofMismatch    = errors.New("log line does not match its proof")
+	ErrInvalidSignature = errors.New("state signature does not verify")
+)
+
+// Result summarizes a verified bundle
+type Result struct {
+	Manifest *Manifest
+	State    *State
+	Verified int
+	// SignatureChecked is true when state signature has been verified with server signing public key
+	SignatureChecked bool
+}
+
+// Verify validates all bundle lines against the bundle state without contacting any server.
+// State signature is checked if server signing public key is provided, unsigned states are refused then
+func Verify(r io.Reader, pubKey *ecdsa.PublicKey) (*Result, error) {
+	br := newReader(r)
+	res := &Result{}
+
+	var st *schema.ImmutableState
+	for {
+		state, line, manifest := &State{}, &Line{}, &Manifest{}
+		name, err := br.next(state, line, manifest)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return res, err
+		}
+
+		switch {
+		case name == stateFile:
+			if st != nil {
+				return res, fmt.Errorf("duplicated state, error %w", ErrInvalidBundle)
+			}
+			st, err = ParseState(state)
+			if err != nil {
+				return res, err
+			}
+			if pubKey != nil {
+				if err := CheckSignature(st, pubKey); err != nil {
+					return res, err
+				}
+				res.SignatureChecked = true
+			}
+			res.State = state
+
+		case name == manifestFile:
+			res.Manifest = manifest
+
+		default:
+			if st == nil {
+				return res, fmt.Errorf("line %s before state, error %w", line.Key, ErrInvalidBundle)
+			}
+			if res.Manifest != nil {
+				return res, fmt.Errorf("line %s after manifest, error %w", line.Key, ErrInvalidBundle)
+			}
+			if err := VerifyLine(st, line); err != nil {
+				return res, err
+			}
+			res.Verified++
+		}
+	}
+
+	if st == nil || res.Manifest == nil {
+		return res, fmt.Errorf("missing state or manifest, error %w", ErrInvalidBundle)
+	}
+	if res.Manifest.Lines != res.Verified {
+		return res, fmt.Errorf("manifest declares %d lines, bundle holds %d, error %w", res.Manifest.Lines, res.Verified, ErrInvalidBundle)
+	}
+
+	return res, nil
+}
+
+// ParseState decodes bundle immudb state, checking it matches its declared database and transaction
+func ParseState(s *State) (*schema.ImmutableState, error) {
+	st := &schema.ImmutableState{}
+	if err := proto.Unmarshal(s.State, st); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state, error %w", err)
+	}
+
+	if st.GetDb() != s.Database || st.GetTxId() != s.TxID || len(st.GetTxHash()) != sha256.Size {
+		return nil, fmt.Errorf("state does not match its declaration, error %w", ErrInvalidBundle)
+	}
+
+	return st, nil
+}
+
+// CheckSignature verifies state has been signed by immudb server signing key
+func CheckSignature(st *schema.ImmutableState, pubKey *ecdsa.PublicKey) error {
+	if st.GetSignature() == nil {
+		return fmt.Errorf("state is not signed, error %w", ErrInvalidSignature)
+	}
+
+	ok, err := st.CheckSignature(pubKey)
+	if err != nil {
+		return fmt.Errorf("unable to check state signature, error %v, %w", err, ErrInvalidSignature)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyLine checks line entry is included on its transaction, and its transaction is consistent with state.
+// It applies the same verification immudb client does on verified reads
+func VerifyLine(st *schema.ImmutableState, l *Line) error {
+	ve := &schema.VerifiableEntry{}
+	if err := proto.Unmarshal(l.Proof, ve); err != nil {
+		return fmt.Errorf("unable to unmarshal key %s proof, error %w", l.Key, err)
+	}
+
+	e := ve.GetEntry()
+	vtx := ve.GetVerifiableTx()
+	if e == nil || e.GetReferencedBy() != nil || ve.GetInclusionProof() == nil || vtx.GetTx().GetHeader() == nil ||
+		vtx.GetDualProof().GetSourceTxHeader() == nil || vtx.GetDualProof().GetTargetTxHeader() == nil || vtx.GetDualProof().GetLinearProof() == nil {
+		return fmt.Errorf("incomplete key %s proof, error %w", l.Key, ErrInvalidProof)
+	}
+
+	if string(e.GetKey()) != l.Key || !bytes.Equal(e.GetValue(), []byte(l.Value)) || e.GetTx() != l.Tx {
+		return fmt.Errorf("key %s, error %w", l.Key, ErrProofMismatch)
+	}
+	if l.Tx > st.GetTxId() {
+		return fmt.Errorf("key %s written after state, error %w", l.Key, ErrProofMismatch)
+	}
+
+	entrySpecDigest, err := store.EntrySpecDigestFor(int(vtx.GetTx().GetHeader().GetVersion()))
+	if err != nil {
+		return fmt.Errorf("key %s, error %v, %w", l.Key, err, ErrInvalidProof)
+	}
+
+	inclusionProof := schema.InclusionProofFromProto(ve.GetInclusionProof())
+	dualProof := schema.DualProofFromProto(vtx.GetDualProof())
+
+	var eh, sourceAlh, targetAlh [sha256.Size]byte
+	var sourceID, targetID uint64
+	if st.GetTxId() <= l.Tx {
+		eh = schema.DigestFromProto(vtx.GetDualProof().GetTargetTxHeader().GetEH())
+		sourceID, sourceAlh = st.GetTxId(), schema.DigestFromProto(st.GetTxHash())
+		targetID, targetAlh = l.Tx, dualProof.TargetTxHeader.Alh()
+	} else {
+		eh = schema.DigestFromProto(vtx.GetDualProof().GetSourceTxHeader().GetEH())
+		sourceID, sourceAlh = l.Tx, dualProof.SourceTxHeader.Alh()
+		targetID, targetAlh = st.GetTxId(), schema.DigestFromProto(st.GetTxHash())
+	}
+
+	spec := database.EncodeEntrySpec([]byte(l.Key), schema.KVMetadataFromProto(e.GetMetadata()), e.GetValue())
+	if !store.VerifyInclusion(inclusionProof, entrySpecDigest(spec), eh) {
+		return fmt.Errorf("key %s inclusion, error %w", l.Key, ErrInvalidProof)
+	}
+
+	if !store.VerifyDualProof(dualProof, sourceID, targetID, sourceAlh, targetAlh) {
+		return fmt.Errorf("key %s consistency, error %w", l.Key, ErrInvalidProof)
+	}
+
+	return nil
+}
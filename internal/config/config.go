@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix prefixes environment variables, grpc-port flag is set from LOG_API_GRPC_PORT
+const EnvPrefix = "LOG_API"
+
+const redacted = "[redacted]"
+
+var ErrUnknownKey = errors.New("unknown configuration key")
+var ErrInvalid = errors.New("invalid configuration")
+
+// Load sets flags not given on command line from LOG_API_* environment variables, then from file keys,
+// leaving its defaults otherwise. File format follows its extension, as yaml, yml or toml, and its keys
+// are flag names, so keys not known refuse the whole file
+func Load(fs *pflag.FlagSet, file string, known func(key string) bool) error {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	if file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("unable to read config file %s, error %w", file, err)
+		}
+		for _, k := range v.AllKeys() {
+			if !known(k) {
+				return fmt.Errorf("key %s on %s, error %w", k, file, ErrUnknownKey)
+			}
+		}
+	}
+
+	if err := v.BindPFlags(fs); err != nil {
+		return fmt.Errorf("unable to bind flags, error %w", err)
+	}
+
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || !v.IsSet(f.Name) {
+			return
+		}
+		if e := fs.Set(f.Name, v.GetString(f.Name)); e != nil {
+			err = fmt.Errorf("unable to set %s from %s or config file, error %w", f.Name, EnvName(f.Name), e)
+		}
+	})
+
+	return err
+}
+
+// EnvName returns flag environment variable name
+func EnvName(flag string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Print writes flag set effective values as yaml keys sorted by name, secrets values are redacted once set
+func Print(w io.Writer, fs *pflag.FlagSet, secrets map[string]bool) error {
+	var names []string
+	fs.VisitAll(func(f *pflag.Flag) {
+		names = append(names, f.Name)
+	})
+	sort.Strings(names)
+
+	for _, name := range names {
+		val := fs.Lookup(name).Value.String()
+		if secrets[name] && val != "" {
+			val = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s: %q\n", name, val); err != nil {
+			return fmt.Errorf("unable to print %s, error %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestItLayersFlagsOverEnvironmentOverConfigFile(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		file string
+		body string
+	}{
+		{"yaml", "config.yaml", "grpc-port: 9100\nhttp-port: 9190\nimmudb-host: from-file\n"},
+		{"toml", "config.toml", "grpc-port = 9100\nhttp-port = 9190\nimmudb-host = \"from-file\"\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(file, []byte(tc.body), 0o600); err != nil {
+				t.Fatalf("unexpected error writing config file %v", err)
+			}
+			t.Setenv("LOG_API_HTTP_PORT", "9290")
+			t.Setenv("LOG_API_IMMUDB_HOST", "from-env")
+
+			fs, vals := fakeFlagSet()
+			if err := fs.Parse([]string{"--immudb-host=from-flag"}); err != nil {
+				t.Fatalf("unexpected error parsing flags %v", err)
+			}
+
+			if err := Load(fs, file, func(k string) bool { return fs.Lookup(k) != nil }); err != nil {
+				t.Fatalf("unexpected error loading config %v", err)
+			}
+
+			if expected, got := 9100, *vals.grpcPort; expected != got {
+				t.Errorf("grpc ports do not match, expected %d got %d", expected, got)
+			}
+			if expected, got := 9290, *vals.httpPort; expected != got {
+				t.Errorf("http ports do not match, expected %d got %d", expected, got)
+			}
+			if expected, got := "from-flag", *vals.immudbHost; expected != got {
+				t.Errorf("immudb hosts do not match, expected %s got %s", expected, got)
+			}
+			if expected, got := "jwt-secret", *vals.jwtSecret; expected != got {
+				t.Errorf("jwt secrets do not match, expected %s got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestItRefusesUnknownConfigFileKeys(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("grpc-prot: 9100\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error writing config file %v", err)
+	}
+
+	fs, _ := fakeFlagSet()
+	if err := Load(fs, file, func(k string) bool { return fs.Lookup(k) != nil }); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unexpected error loading config, got %v", err)
+	}
+}
+
+func TestItFailsOnInvalidEnvironmentValues(t *testing.T) {
+	t.Setenv("LOG_API_GRPC_PORT", "not-a-port")
+
+	fs, _ := fakeFlagSet()
+	if err := Load(fs, "", func(k string) bool { return true }); err == nil || !strings.Contains(err.Error(), "LOG_API_GRPC_PORT") {
+		t.Errorf("unexpected error loading config, got %v", err)
+	}
+}
+
+func TestItPrintsEffectiveConfigurationWithSecretsRedacted(t *testing.T) {
+	fs, _ := fakeFlagSet()
+	if err := fs.Parse([]string{"--grpc-port=9100"}); err != nil {
+		t.Fatalf("unexpected error parsing flags %v", err)
+	}
+
+	b := &bytes.Buffer{}
+	if err := Print(b, fs, map[string]bool{"jwt-secret": true}); err != nil {
+		t.Fatalf("unexpected error printing config %v", err)
+	}
+
+	expected := "grpc-port: \"9100\"\nhttp-port: \"9090\"\nimmudb-host: \"localhost\"\njwt-secret: \"[redacted]\"\n"
+	if got := b.String(); expected != got {
+		t.Errorf("printed configurations do not match, expected %s got %s", expected, got)
+	}
+}
+
+type fakeValues struct {
+	grpcPort   *int
+	httpPort   *int
+	immudbHost *string
+	jwtSecret  *string
+}
+
+func fakeFlagSet() (*pflag.FlagSet, *fakeValues) {
+	fs := pflag.NewFlagSet("fake", pflag.ContinueOnError)
+	return fs, &fakeValues{
+		grpcPort:   fs.Int("grpc-port", 9000, "grpc port"),
+		httpPort:   fs.Int("http-port", 9090, "http port"),
+		immudbHost: fs.String("immudb-host", "localhost", "immudb host"),
+		jwtSecret:  fs.String("jwt-secret", "jwt-secret", "jwt secret"),
+	}
+}
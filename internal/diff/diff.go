@@ -0,0 +1,156 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff formats
+const (
+	FormatUnified   = "unified"
+	FormatJSONPatch = "json-patch"
+)
+
+// Operation is a RFC 6902 JSON patch operation
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Diff describes changes from one value to another. Structured values, JSON objects or arrays,
+// get a JSON patch, anything else a unified diff. Empty from value stands for a non existent one
+func Diff(from, to []byte, fromLabel, toLabel string) (format string, diff string, err error) {
+	a, aok := structured(from)
+	b, bok := structured(to)
+	if bok && (aok || len(from) == 0) {
+		ops, err := Patch(a, b)
+		if err != nil {
+			return "", "", err
+		}
+		raw, err := json.Marshal(ops)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to marshal json patch, error %w", err)
+		}
+		return FormatJSONPatch, string(raw), nil
+	}
+
+	d, err := Unified(string(from), string(to), fromLabel, toLabel)
+	if err != nil {
+		return "", "", err
+	}
+
+	return FormatUnified, d, nil
+}
+
+// Unified returns unified diff between from and to values
+func Unified(from, to, fromLabel, toLabel string) (string, error) {
+	var a []string
+	if from != "" {
+		a = difflib.SplitLines(from)
+	}
+
+	d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        difflib.SplitLines(to),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to build unified diff, error %w", err)
+	}
+
+	return d, nil
+}
+
+// Patch returns JSON patch operations turning from into to, nil from value means a non existent document.
+// Objects are compared member by member, any other changed value gets replaced
+func Patch(from, to interface{}) ([]Operation, error) {
+	ops := []Operation{}
+	if from == nil {
+		return add(ops, "", to)
+	}
+
+	return compare(ops, "", from, to)
+}
+
+func compare(ops []Operation, path string, from, to interface{}) ([]Operation, error) {
+	a, aok := from.(map[string]interface{})
+	b, bok := to.(map[string]interface{})
+	if !aok || !bok {
+		if reflect.DeepEqual(from, to) {
+			return ops, nil
+		}
+		return op(ops, "replace", path, to)
+	}
+
+	var err error
+	for _, k := range sortedKeys(a) {
+		if _, ok := b[k]; !ok {
+			ops = append(ops, Operation{Op: "remove", Path: path + "/" + escape(k)})
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		p := path + "/" + escape(k)
+		if _, ok := a[k]; !ok {
+			ops, err = add(ops, p, b[k])
+		} else {
+			ops, err = compare(ops, p, a[k], b[k])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ops, nil
+}
+
+func add(ops []Operation, path string, v interface{}) ([]Operation, error) {
+	return op(ops, "add", path, v)
+}
+
+func op(ops []Operation, name, path string, v interface{}) ([]Operation, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal %s value, error %w", path, err)
+	}
+
+	return append(ops, Operation{Op: name, Path: path, Value: raw}), nil
+}
+
+// structured decodes JSON objects and arrays
+func structured(v []byte) (interface{}, bool) {
+	t := bytes.TrimSpace(v)
+	if len(t) == 0 || (t[0] != '{' && t[0] != '[') {
+		return nil, false
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(t, &res); err != nil {
+		return nil, false
+	}
+
+	return res, true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// escape encodes JSON pointer reference token
+func escape(k string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+}
@@ -0,0 +1,50 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestItBuildsUnifiedDiffsOnPlainValues(t *testing.T) {
+	format, d, err := Diff([]byte("foo\nbar"), []byte("foo\nbaz"), "revision 1", "revision 2")
+	if err != nil {
+		t.Fatalf("unexpected error building diff, error %v", err)
+	}
+
+	if expected, got := FormatUnified, format; expected != got {
+		t.Errorf("formats do not match, expected %s got %s", expected, got)
+	}
+
+	for _, l := range []string{"--- revision 1", "+++ revision 2", "-bar", "+baz", " foo"} {
+		if !strings.Contains(d, l+"\n") {
+			t.Errorf("expected diff line %q, got %s", l, d)
+		}
+	}
+}
+
+func TestItBuildsJSONPatchesOnStructuredValues(t *testing.T) {
+	format, d, err := Diff([]byte(`{"level":"info","msg":"foo","a/b":1}`), []byte(`{"level":"error","msg":"foo","ctx":{"id":2}}`), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error building diff, error %v", err)
+	}
+
+	if expected, got := FormatJSONPatch, format; expected != got {
+		t.Errorf("formats do not match, expected %s got %s", expected, got)
+	}
+
+	expected := `[{"op":"remove","path":"/a~1b"},{"op":"add","path":"/ctx","value":{"id":2}},{"op":"replace","path":"/level","value":"error"}]`
+	if expected != d {
+		t.Errorf("patches do not match, expected %s got %s", expected, d)
+	}
+}
+
+func TestItAddsWholeDocumentOnFirstStructuredRevision(t *testing.T) {
+	_, d, err := Diff(nil, []byte(`{"msg":"foo"}`), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error building diff, error %v", err)
+	}
+
+	if expected, got := `[{"op":"add","path":"","value":{"msg":"foo"}}]`, d; expected != got {
+		t.Errorf("patches do not match, expected %s got %s", expected, got)
+	}
+}
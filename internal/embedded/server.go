@@ -0,0 +1,180 @@
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DirEnv holds embedded immudb data directory on backup hooks environment
+const DirEnv = "LOG_API_EMBEDDED_DB_DIR"
+
+const defaultStartTimeout = time.Second * 10
+
+var ErrNotStarted = errors.New("embedded immudb did not start")
+
+// Options configures embedded immudb
+type Options struct {
+	Dir           string
+	Address       string
+	Port          int
+	Synced        bool
+	AdminPassword string
+}
+
+// Server runs immudb in process, it listens on its address so immudb tools can reach it too
+type Server struct {
+	srv  *server.ImmuServer
+	dir  string
+	done chan struct{}
+	err  error
+}
+
+// Start initializes immudb on data directory and waits until it serves requests.
+// Metrics, web console and pgsql servers are disabled, so just immudb grpc port is opened.
+// Immudb SIGINT and SIGTERM handler gets removed so callers stop it once drained, Start goes before their signal handlers
+func Start(o *Options) (*Server, error) {
+	opts := server.DefaultOptions().
+		WithDir(o.Dir).
+		WithAddress(o.Address).
+		WithPort(o.Port).
+		WithSynced(o.Synced).
+		WithAdminPassword(o.AdminPassword).
+		WithMetricsServer(false).
+		WithWebServer(false).
+		WithPgsqlServer(false).
+		WithConfig("")
+
+	srv := server.DefaultServer().WithOptions(opts).(*server.ImmuServer)
+	if err := srv.Initialize(); err != nil {
+		return nil, fmt.Errorf("unable to initialize embedded immudb on %s, error %w", o.Dir, err)
+	}
+
+	s := &Server{srv: srv, dir: o.Dir, done: make(chan struct{})}
+	go func() {
+		s.err = srv.Start()
+		close(s.done)
+	}()
+
+	if err := s.wait(defaultStartTimeout); err != nil {
+		_ = s.Stop()
+		return nil, err
+	}
+	signal.Reset(os.Interrupt, syscall.SIGTERM)
+
+	return s, nil
+}
+
+// Addr returns embedded immudb listening address
+func (s *Server) Addr() net.Addr {
+	return s.srv.Listener.Addr()
+}
+
+// Dir returns embedded immudb data directory
+func (s *Server) Dir() string {
+	return s.dir
+}
+
+// Done is closed once embedded immudb stops
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns immudb result once done
+func (s *Server) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Stop closes embedded immudb databases, already stopped immudb is left as it is
+func (s *Server) Stop() error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+
+	if err := s.srv.Stop(); err != nil {
+		return fmt.Errorf("unable to stop embedded immudb, error %w", err)
+	}
+
+	return nil
+}
+
+// RunHook runs backup hook command through shell with data directory on its environment.
+// Data files are just consistent once stopped, hooks on a running immudb must take atomic filesystem snapshots
+func (s *Server) RunHook(ctx context.Context, hook string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+	cmd.Env = append(os.Environ(), DirEnv+"="+s.dir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to run backup hook, error %w", err)
+	}
+
+	return nil
+}
+
+// RunHooks runs backup hook on each interval until context is done, failures are logged
+func (s *Server) RunHooks(ctx context.Context, hook string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.RunHook(ctx, hook); err != nil {
+			log.Printf("Embedded immudb backup failed, error %v", err)
+		}
+	}
+}
+
+// wait calls immudb until it serves requests, its listener accepts connections before.
+// Immudb installs its signal handler before serving, so it is in place once served
+func (s *Server) wait(timeout time.Duration) error {
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		return fmt.Errorf("unable to dial embedded immudb, error %v, %w", err, ErrNotStarted)
+	}
+	defer conn.Close()
+	cl := schema.NewImmuServiceClient(conn)
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-s.done:
+			return fmt.Errorf("embedded immudb stopped on start, error %v, %w", s.err, ErrNotStarted)
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := cl.Health(ctx, &empty.Empty{})
+		cancel()
+		if status.Code(err) != codes.Unavailable && status.Code(err) != codes.DeadlineExceeded {
+			log.Printf("Embedded immudb listening on %s, data directory %s", s.Addr(), s.dir)
+			return nil
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+
+	return fmt.Errorf("embedded immudb not listening after %v, error %w", timeout, ErrNotStarted)
+}
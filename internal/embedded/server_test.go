@@ -0,0 +1,66 @@
+package embedded
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/client"
+)
+
+func TestItServesImmudbInProcessAndRunsBackupHooks(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Start(&Options{Dir: filepath.Join(dir, "data"), Address: "127.0.0.1", Port: 0, Synced: true, AdminPassword: "fake-password"})
+	if err != nil {
+		t.Fatalf("unexpected error starting embedded immudb, error %v", err)
+	}
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	o := client.DefaultOptions()
+	o.Address = "127.0.0.1"
+	o.Port = s.Addr().(*net.TCPAddr).Port
+	o.Dir = dir
+	cl := client.NewClient().WithOptions(o)
+	if err := cl.OpenSession(ctx, []byte("immudb"), []byte("fake-password"), "defaultdb"); err != nil {
+		t.Fatalf("unexpected error opening session, error %v", err)
+	}
+
+	if _, err := cl.Set(ctx, []byte("foo"), []byte("bar")); err != nil {
+		t.Fatalf("unexpected error setting key, error %v", err)
+	}
+	e, err := cl.Get(ctx, []byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "bar", string(e.Value); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	_ = cl.CloseSession(ctx)
+
+	marker := filepath.Join(dir, "backup")
+	if err := s.RunHook(ctx, `echo -n "$`+DirEnv+`" > `+marker); err != nil {
+		t.Fatalf("unexpected error running hook, error %v", err)
+	}
+	raw, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("unexpected error reading hook output, error %v", err)
+	}
+	if expected, got := s.Dir(), string(raw); expected != got {
+		t.Errorf("hook directories do not match, expected %s got %s", expected, got)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unexpected error stopping embedded immudb, error %v", err)
+	}
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("embedded immudb not done after stop")
+	}
+}
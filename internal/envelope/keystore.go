@@ -0,0 +1,296 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const dataKeySize = 32
+
+var (
+	// ErrKeyShredded happens on reading a data key that has been shredded
+	ErrKeyShredded = errors.New("data key has been shredded")
+	// ErrInvalidMasterKey happens when master key is not a valid AES-256 key
+	ErrInvalidMasterKey = errors.New("invalid master key, 32 bytes required")
+)
+
+// KeyStore keeps data keys outside immudb, so they can be destroyed
+type KeyStore interface {
+	// ActiveKey returns the current data key for a scope, creating it if needed
+	ActiveKey(ctx context.Context, scope string) (id string, key []byte, err error)
+	// Key returns a data key by its id, ErrKeyShredded if it no longer exists
+	Key(ctx context.Context, id string) ([]byte, error)
+	// Shred destroys all data keys from a scope, returns how many keys were destroyed
+	Shred(ctx context.Context, scope string) (int, error)
+}
+
+type fileKeyStoreData struct {
+	Active   map[string]string `json:"active"`
+	Keys     map[string][]byte `json:"keys"`
+	Scopes   map[string]string `json:"scopes"`
+	Shredded map[string]string `json:"shredded"`
+}
+
+// fileKeyStore keeps data keys on a json file shared with other processes, as keys shred does. Each access takes
+// the lock file and reloads the store once its file got replaced, so shredded keys are neither read nor restored
+type fileKeyStore struct {
+	path   string
+	master cipher.AEAD
+	mutex  sync.Mutex
+	lock   *os.File
+	info   os.FileInfo
+	data   *fileKeyStoreData
+}
+
+// NewFileKeyStore instantiates a json file key store, data keys are wrapped with the master key
+func NewFileKeyStore(path string, masterKey []byte) (*fileKeyStore, error) {
+	if len(masterKey) != dataKeySize {
+		return nil, ErrInvalidMasterKey
+	}
+
+	m, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build master key cipher, error %w", err)
+	}
+
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open key store lock %s.lock, error %w", path, err)
+	}
+
+	f := &fileKeyStore{
+		path:   path,
+		master: m,
+		lock:   lock,
+		data:   newFileKeyStoreData(),
+	}
+
+	unlock, err := f.acquire(false)
+	if err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+	defer unlock()
+
+	return f, nil
+}
+
+func newFileKeyStoreData() *fileKeyStoreData {
+	return &fileKeyStoreData{
+		Active:   map[string]string{},
+		Keys:     map[string][]byte{},
+		Scopes:   map[string]string{},
+		Shredded: map[string]string{},
+	}
+}
+
+// acquire takes the lock file, exclusive on writes, and reloads the store if another process replaced it
+func (f *fileKeyStore) acquire(exclusive bool) (func(), error) {
+	if err := lockFile(f.lock, exclusive); err != nil {
+		return nil, fmt.Errorf("unable to lock key store %s, error %w", f.path, err)
+	}
+	unlock := func() { _ = unlockFile(f.lock) }
+
+	if err := f.reload(); err != nil {
+		unlock()
+		return nil, err
+	}
+
+	return unlock, nil
+}
+
+// reload reads the store file once it is not the last one read or written, persist replaces it on each write
+func (f *fileKeyStore) reload() error {
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to stat key store %s, error %w", f.path, err)
+	}
+	if f.info != nil && os.SameFile(f.info, info) {
+		return nil
+	}
+
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read key store %s, error %w", f.path, err)
+	}
+
+	data := newFileKeyStoreData()
+	if err := json.Unmarshal(raw, data); err != nil {
+		return fmt.Errorf("unable to decode key store %s, error %w", f.path, err)
+	}
+	f.data, f.info = data, info
+
+	return nil
+}
+
+// ActiveKey returns scope data key, a new one is generated on first usage or after being shredded
+func (f *fileKeyStore) ActiveKey(ctx context.Context, scope string) (string, []byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	unlock, err := f.acquire(true)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+
+	if id, ok := f.data.Active[scope]; ok {
+		k, err := f.unwrap(id)
+		return id, k, err
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", nil, fmt.Errorf("unable to generate data key, error %w", err)
+	}
+
+	id := uuid.New().String()
+	wrapped, err := seal(f.master, key, []byte(id))
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to wrap data key, error %w", err)
+	}
+
+	f.data.Active[scope] = id
+	f.data.Keys[id] = wrapped
+	f.data.Scopes[id] = scope
+	if err := f.persist(); err != nil {
+		return "", nil, err
+	}
+
+	return id, key, nil
+}
+
+// Key returns data key by id
+func (f *fileKeyStore) Key(ctx context.Context, id string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	unlock, err := f.acquire(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return f.unwrap(id)
+}
+
+// Shred removes all scope data keys from the store, lines encrypted with them become unreadable
+func (f *fileKeyStore) Shred(ctx context.Context, scope string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	unlock, err := f.acquire(true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	total := 0
+	for id, s := range f.data.Scopes {
+		if s != scope {
+			continue
+		}
+		delete(f.data.Keys, id)
+		delete(f.data.Scopes, id)
+		f.data.Shredded[id] = scope
+		total++
+	}
+	delete(f.data.Active, scope)
+
+	return total, f.persist()
+}
+
+func (f *fileKeyStore) unwrap(id string) ([]byte, error) {
+	wrapped, ok := f.data.Keys[id]
+	if !ok {
+		if _, ok := f.data.Shredded[id]; ok {
+			return nil, ErrKeyShredded
+		}
+		return nil, fmt.Errorf("data key %s not found", id)
+	}
+
+	k, err := open(f.master, wrapped, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap data key %s, error %w", id, err)
+	}
+
+	return k, nil
+}
+
+// persist writes the whole store to a temporary file and renames it, old key material is not left behind
+func (f *fileKeyStore) persist() error {
+	raw, err := json.Marshal(f.data)
+	if err != nil {
+		return fmt.Errorf("unable to encode key store, error %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".keystore-*")
+	if err != nil {
+		return fmt.Errorf("unable to create key store temporary file, error %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("unable to write key store, error %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("unable to sync key store, error %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to close key store, error %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("unable to replace key store %s, error %w", f.path, err)
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to stat key store %s, error %w", f.path, err)
+	}
+	f.info = info
+
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(b)
+}
+
+func seal(a cipher.AEAD, plain, additional []byte) ([]byte, error) {
+	nonce := make([]byte, a.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return a.Seal(nonce, nonce, plain, additional), nil
+}
+
+func open(a cipher.AEAD, raw, additional []byte) ([]byte, error) {
+	if len(raw) < a.NonceSize() {
+		return nil, errors.New("sealed payload too short")
+	}
+
+	return a.Open(nil, raw[:a.NonceSize()], raw[a.NonceSize():], additional)
+}
@@ -0,0 +1,123 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+var fakeMasterKey = bytes.Repeat([]byte{7}, dataKeySize)
+
+func TestItReusesScopeActiveKeyAfterReopeningKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	ks, err := NewFileKeyStore(path, fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to create key store, error %v", err)
+	}
+
+	ctx := context.Background()
+	id, key, err := ks.ActiveKey(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	ks, err = NewFileKeyStore(path, fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to reopen key store, error %v", err)
+	}
+
+	reopenedID, reopenedKey, err := ks.ActiveKey(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	if expected, got := id, reopenedID; expected != got {
+		t.Errorf("key ids do not match, expected %s got %s", expected, got)
+	}
+
+	if !bytes.Equal(key, reopenedKey) {
+		t.Error("data keys do not match")
+	}
+}
+
+func TestItFailsReadingShreddedKeys(t *testing.T) {
+	ks, err := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"), fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to create key store, error %v", err)
+	}
+
+	ctx := context.Background()
+	id, _, err := ks.ActiveKey(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	total, err := ks.Shred(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to shred, error %v", err)
+	}
+
+	if expected, got := 1, total; expected != got {
+		t.Errorf("total shredded keys do not match, expected %d got %d", expected, got)
+	}
+
+	if _, err := ks.Key(ctx, id); !errors.Is(err, ErrKeyShredded) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+
+	newID, _, err := ks.ActiveKey(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	if newID == id {
+		t.Error("expected a new data key after shredding")
+	}
+}
+
+func TestItNeitherReadsNorRestoresKeysShreddedByAnotherStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	server, err := NewFileKeyStore(path, fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to create key store, error %v", err)
+	}
+
+	ctx := context.Background()
+	id, _, err := server.ActiveKey(ctx, "fake_bucket")
+	if err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	shredder, err := NewFileKeyStore(path, fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to open key store, error %v", err)
+	}
+	if _, err := shredder.Shred(ctx, "fake_bucket"); err != nil {
+		t.Fatalf("unable to shred, error %v", err)
+	}
+
+	if _, err := server.Key(ctx, id); !errors.Is(err, ErrKeyShredded) {
+		t.Errorf("unexpected error reading shredded key, got %v", err)
+	}
+
+	if _, _, err := server.ActiveKey(ctx, "another_bucket"); err != nil {
+		t.Fatalf("unable to get active key, error %v", err)
+	}
+
+	reopened, err := NewFileKeyStore(path, fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to reopen key store, error %v", err)
+	}
+	if _, err := reopened.Key(ctx, id); !errors.Is(err, ErrKeyShredded) {
+		t.Errorf("unexpected error reading shredded key after server write, got %v", err)
+	}
+}
+
+func TestItRefusesInvalidMasterKeys(t *testing.T) {
+	_, err := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"), []byte("short"))
+	if !errors.Is(err, ErrInvalidMasterKey) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package envelope
+
+import "os"
+
+// lockFile is a no op on windows, key store accesses are only serialized within the process
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
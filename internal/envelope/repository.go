@@ -0,0 +1,299 @@
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// envelopePrefix tags encrypted values, anything else is handled as plain text. Service validation refuses
+// client values starting by it, so they can not be taken as encrypted
+const envelopePrefix = "enc1:"
+
+// ShreddedValue replaces values whose data key has been shredded
+const ShreddedValue = "[shredded]"
+
+var errInvalidEnvelope = errors.New("invalid value envelope")
+
+type repository struct {
+	service.Repository
+	keys  KeyStore
+	scope string
+}
+
+// NewRepository decorates a repository encrypting log line values with its bucket data key.
+// Encrypted values stay on the underlying repository, so hashes and history remain verifiable,
+// shredding bucket data keys turns its values unreadable.
+func NewRepository(r service.Repository, k KeyStore) *repository {
+	return &repository{
+		Repository: r,
+		keys:       k,
+	}
+}
+
+// WithScope prefixes data key scopes, so buckets sharing name on different tenants do not share data keys
+func (r *repository) WithScope(prefix string) *repository {
+	r.scope = prefix
+	return r
+}
+
+// Add encrypts log line value and stores it
+func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
+	l, err := r.encrypt(ctx, line)
+	if err != nil {
+		return err
+	}
+
+	return r.Repository.Add(ctx, l)
+}
+
+// AddBatch encrypts all log line values and stores them in a batch
+func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	ls := make([]*service.LogLine, 0, len(lines))
+	for _, line := range lines {
+		l, err := r.encrypt(ctx, line)
+		if err != nil {
+			return err
+		}
+		ls = append(ls, l)
+	}
+
+	return r.Repository.AddBatch(ctx, ls)
+}
+
+// CreateBatch encrypts all log line values and stores them as new lines
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	ls := make([]*service.LogLine, 0, len(lines))
+	for _, line := range lines {
+		l, err := r.encrypt(ctx, line)
+		if err != nil {
+			return err
+		}
+		ls = append(ls, l)
+	}
+
+	return r.Repository.CreateBatch(ctx, ls)
+}
+
+// History returns decrypted key revisions
+func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	h, err := r.Repository.History(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptHistory(ctx, h)
+}
+
+// Histories returns decrypted keys revisions
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	hs, err := r.Repository.Histories(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*service.LogLineHistory, 0, len(hs))
+	for _, h := range hs {
+		d, err := r.decryptHistory(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, nil
+}
+
+// RevisionHistory returns decrypted key revisions with its transaction timestamp and writer
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	h, err := r.Repository.RevisionHistory(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptHistory(ctx, h)
+}
+
+// GetByKey returns decrypted log line
+func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	l, err := r.Repository.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLine(ctx, l)
+}
+
+// GetByPrefix returns decrypted log lines
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLines(ctx, ls)
+}
+
+// GetLastNLogLines returns decrypted log lines
+func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetLastNLogLines(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLines(ctx, ls)
+}
+
+// GetByBucket returns decrypted log lines
+func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByBucket(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLines(ctx, ls)
+}
+
+// GetByKeyAt returns decrypted log line as it was on transaction tx
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	l, err := r.Repository.GetByKeyAt(ctx, key, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLine(ctx, l)
+}
+
+// GetByPrefixAt returns decrypted log lines as they were on transaction tx
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByPrefixAt(ctx, prefix, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLines(ctx, ls)
+}
+
+// GetByBucketAt returns decrypted log lines as they were on transaction tx
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByBucketAt(ctx, bucket, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decryptLines(ctx, ls)
+}
+
+// ExportByBucket hands decrypted log lines to fn
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	return r.Repository.ExportByBucket(ctx, bucket, from, to, func(e *service.ExportedLogLine) error {
+		v, err := r.decrypt(ctx, e.Key, string(e.Value))
+		if err != nil {
+			return err
+		}
+		e.Value = []byte(v)
+
+		return fn(e)
+	})
+}
+
+func (r *repository) encrypt(ctx context.Context, line *service.LogLine) (*service.LogLine, error) {
+	id, key, err := r.keys.ActiveKey(ctx, r.scope+line.Bucket())
+	if err != nil {
+		return nil, fmt.Errorf("unable to get bucket %s data key, error %w", line.Bucket(), err)
+	}
+
+	a, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build data key cipher, error %w", err)
+	}
+
+	sealed, err := seal(a, line.Value(), additionalData(id, string(line.Key())))
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt key %s, error %w", string(line.Key()), err)
+	}
+
+	v := envelopePrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed)
+	return service.NewLogLineWithBucket(line.Bucket(), string(line.Key()), v, line.Time()), nil
+}
+
+func (r *repository) decryptHistory(ctx context.Context, h *service.LogLineHistory) (*service.LogLineHistory, error) {
+	for _, rv := range h.Revision {
+		v, err := r.decrypt(ctx, h.Key, string(rv.Value))
+		if err != nil {
+			return nil, err
+		}
+		rv.Value = []byte(v)
+	}
+
+	return h, nil
+}
+
+func (r *repository) decryptLines(ctx context.Context, ls []*service.LogLine) ([]*service.LogLine, error) {
+	res := make([]*service.LogLine, 0, len(ls))
+	for _, l := range ls {
+		d, err := r.decryptLine(ctx, l)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, nil
+}
+
+func (r *repository) decryptLine(ctx context.Context, l *service.LogLine) (*service.LogLine, error) {
+	v, err := r.decrypt(ctx, string(l.Key()), string(l.Value()))
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewLogLineWithBucket(l.Bucket(), string(l.Key()), v, l.Time()), nil
+}
+
+func (r *repository) decrypt(ctx context.Context, lineKey, value string) (string, error) {
+	if !strings.HasPrefix(value, envelopePrefix) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, envelopePrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("unable to decode key %s value, error %w", lineKey, errInvalidEnvelope)
+	}
+	id := parts[0]
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("unable to decode key %s value, error %w", lineKey, err)
+	}
+
+	key, err := r.keys.Key(ctx, id)
+	if errors.Is(err, ErrKeyShredded) {
+		return ShreddedValue, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to get data key %s, error %w", id, err)
+	}
+
+	a, err := newAEAD(key)
+	if err != nil {
+		return "", fmt.Errorf("unable to build data key cipher, error %w", err)
+	}
+
+	plain, err := open(a, sealed, additionalData(id, lineKey))
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt key %s, error %w", lineKey, err)
+	}
+
+	return string(plain), nil
+}
+
+// additionalData binds the ciphertext to its log line key, encrypted values cannot be moved between keys
+func additionalData(keyID, lineKey string) []byte {
+	return []byte(keyID + ":" + lineKey)
+}
@@ -0,0 +1,186 @@
+package envelope
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItStoresEncryptedValuesAndDecryptsThemOnRead(t *testing.T) {
+	fr := newFakeRepository()
+	r := NewRepository(fr, newFakeKeyStore(t))
+	ctx := context.Background()
+
+	value := "fake data value"
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "foo_0", value, time.Now())); err != nil {
+		t.Fatalf("unable to add log line, error %v", err)
+	}
+
+	if stored := string(fr.lines["foo_0"].Value()); !strings.HasPrefix(stored, envelopePrefix) || strings.Contains(stored, value) {
+		t.Fatalf("expected encrypted value, got %s", stored)
+	}
+
+	l, err := r.GetByKey(ctx, "foo_0")
+	if err != nil {
+		t.Fatalf("unable to get by key, error %v", err)
+	}
+
+	if expected, got := value, string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItReturnsShreddedValuesAfterShreddingBucketKeys(t *testing.T) {
+	fr := newFakeRepository()
+	ks := newFakeKeyStore(t)
+	r := NewRepository(fr, ks)
+	ctx := context.Background()
+
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket("fake_bucket", "foo_0", "fake value", time.Now()),
+		service.NewLogLineWithBucket("another_bucket", "foo_1", "fake value b", time.Now()),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unable to add batch, error %v", err)
+	}
+
+	if _, err := ks.Shred(ctx, "fake_bucket"); err != nil {
+		t.Fatalf("unable to shred, error %v", err)
+	}
+
+	all, err := r.GetByPrefix(ctx, "foo")
+	if err != nil {
+		t.Fatalf("unable to get by prefix, error %v", err)
+	}
+
+	values := map[string]string{}
+	for _, l := range all {
+		values[string(l.Key())] = string(l.Value())
+	}
+
+	if expected, got := ShreddedValue, values["foo_0"]; expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if expected, got := "fake value b", values["foo_1"]; expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItUsesScopedBucketDataKeys(t *testing.T) {
+	ks := newFakeKeyStore(t)
+	r := NewRepository(newFakeRepository(), ks).WithScope("acme/")
+	ctx := context.Background()
+
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "foo_0", "fake value", time.Now())); err != nil {
+		t.Fatalf("unable to add log line, error %v", err)
+	}
+
+	if total, _ := ks.Shred(ctx, "fake_bucket"); total != 0 {
+		t.Errorf("unexpected unscoped data keys, got %d", total)
+	}
+
+	if total, _ := ks.Shred(ctx, "acme/fake_bucket"); total != 1 {
+		t.Errorf("expected scoped data key, got %d", total)
+	}
+}
+
+func TestItExportsDecryptedValues(t *testing.T) {
+	r := NewRepository(newFakeRepository(), newFakeKeyStore(t))
+	ctx := context.Background()
+
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "foo_0", "fake value", time.Now())); err != nil {
+		t.Fatalf("unable to add log line, error %v", err)
+	}
+
+	values := []string{}
+	err := r.ExportByBucket(ctx, "fake_bucket", time.Time{}, time.Time{}, func(e *service.ExportedLogLine) error {
+		values = append(values, string(e.Value))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to export, error %v", err)
+	}
+
+	if expected, got := 1, len(values); expected != got {
+		t.Fatalf("total values do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := "fake value", values[0]; expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItPassesThroughPlainTextValues(t *testing.T) {
+	fr := newFakeRepository()
+	fr.lines["foo_0"] = service.NewLogLine("foo_0", "legacy plain value")
+	r := NewRepository(fr, newFakeKeyStore(t))
+
+	l, err := r.GetByKey(context.Background(), "foo_0")
+	if err != nil {
+		t.Fatalf("unable to get by key, error %v", err)
+	}
+
+	if expected, got := "legacy plain value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func newFakeKeyStore(t *testing.T) KeyStore {
+	ks, err := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"), fakeMasterKey)
+	if err != nil {
+		t.Fatalf("unable to create key store, error %v", err)
+	}
+	return ks
+}
+
+type fakeRepository struct {
+	service.Repository
+	lines map[string]*service.LogLine
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{lines: map[string]*service.LogLine{}}
+}
+
+func (f *fakeRepository) Add(ctx context.Context, line *service.LogLine) error {
+	f.lines[string(line.Key())] = line
+	return nil
+}
+
+func (f *fakeRepository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	for _, line := range lines {
+		f.lines[string(line.Key())] = line
+	}
+	return nil
+}
+
+func (f *fakeRepository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	return f.lines[key], nil
+}
+
+func (f *fakeRepository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	res := []*service.LogLine{}
+	for k, l := range f.lines {
+		if strings.HasPrefix(k, prefix) {
+			res = append(res, l)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeRepository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	for k, l := range f.lines {
+		if l.Bucket() != bucket {
+			continue
+		}
+		if err := fn(&service.ExportedLogLine{Key: k, Bucket: bucket, Value: l.Value(), CreatedAt: l.Time()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
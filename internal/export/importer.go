@@ -0,0 +1,164 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// DefaultBatchSize defines how many records are consumed between checkpoints
+const DefaultBatchSize = 100
+
+type bucketGuard interface {
+	EnsureBucket(ctx context.Context, name string) error
+}
+
+// Conflict describes an imported record whose key is already stored with a different value
+type Conflict struct {
+	Key      string
+	Stored   string
+	Imported string
+}
+
+// Report summarizes an import, Position is the total consumed records including resumed ones
+type Report struct {
+	Imported  int
+	Skipped   int
+	Conflicts []*Conflict
+	Position  int
+}
+
+// Importer re-ingests exported records, keys are rebuilt from its source and creation time
+type Importer struct {
+	repository service.Repository
+	buckets    bucketGuard
+	batchSize  int
+}
+
+// NewImporter instantiates importer storing records in batches
+func NewImporter(r service.Repository, batchSize int) *Importer {
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Importer{
+		repository: r,
+		batchSize:  batchSize,
+	}
+}
+
+// WithBucketGuard ensures record buckets exist before storing them
+func (i *Importer) WithBucketGuard(g bucketGuard) *Importer {
+	i.buckets = g
+	return i
+}
+
+// Import consumes all records after position, already stored keys are skipped and reported as conflict when its value differs.
+// Checkpoint gets called with the consumed records position once its batch is stored, so interrupted imports can be resumed from it
+func (i *Importer) Import(ctx context.Context, rd Reader, position int, checkpoint func(position int) error) (*Report, error) {
+	rep := &Report{Position: position}
+	for n := 0; n < position; n++ {
+		if _, err := rd.Read(); err != nil {
+			return rep, fmt.Errorf("unable to resume from record %d, error %w", position, err)
+		}
+	}
+
+	consumed := position
+	batch := []*service.LogLine{}
+	pending := map[string]string{}
+	flush := func() error {
+		if err := i.store(ctx, batch); err != nil {
+			return err
+		}
+		rep.Imported += len(batch)
+		rep.Position = consumed
+		batch = []*service.LogLine{}
+		pending = map[string]string{}
+
+		return checkpoint(consumed)
+	}
+
+	for {
+		rec, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rep, fmt.Errorf("unable to read record %d, error %w", consumed+1, err)
+		}
+		consumed++
+
+		line, err := recordLogLine(rec)
+		if err != nil {
+			return rep, err
+		}
+
+		key := string(line.Key())
+		stored, ok := pending[key]
+		if !ok {
+			l, err := i.repository.GetByKey(ctx, key)
+			if err != nil && !errors.Is(err, service.ErrLogLineNotFound) {
+				return rep, fmt.Errorf("unable to get key %s, error %w", key, err)
+			}
+			if err == nil {
+				stored, ok = string(l.Value()), true
+			}
+		}
+
+		switch {
+		case !ok:
+			batch = append(batch, line)
+			pending[key] = rec.Value
+		case stored == rec.Value:
+			rep.Skipped++
+		default:
+			rep.Conflicts = append(rep.Conflicts, &Conflict{Key: key, Stored: stored, Imported: rec.Value})
+		}
+
+		if consumed-rep.Position >= i.batchSize {
+			if err := flush(); err != nil {
+				return rep, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return rep, err
+	}
+
+	return rep, nil
+}
+
+func (i *Importer) store(ctx context.Context, lines []*service.LogLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if i.buckets != nil {
+		for _, l := range lines {
+			if err := i.buckets.EnsureBucket(ctx, l.Bucket()); err != nil {
+				return fmt.Errorf("unable to ensure bucket %s, error %w", l.Bucket(), err)
+			}
+		}
+	}
+
+	if err := i.repository.AddBatch(ctx, lines); err != nil {
+		return fmt.Errorf("unable to store %d log lines, error %w", len(lines), err)
+	}
+
+	return nil
+}
+
+// recordLogLine rebuilds record log line from its key source, creation time and sequence, so the same key is reproduced.
+// Legacy keys get rebuilt on the versioned encoding
+func recordLogLine(rec *Record) (*service.LogLine, error) {
+	k, err := service.ParseLogLineKey(rec.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewKeyedLogLine(k, rec.Bucket, rec.Value), nil
+}
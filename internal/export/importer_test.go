@@ -0,0 +1,148 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItImportsRecordsSkippingPresentKeysAndReportingConflicts(t *testing.T) {
+	keyA := linekey.Encode(linekey.Key{Source: "foo", Time: time.Unix(0, 1659469108710408961)})
+	keyB := linekey.Encode(linekey.Key{Source: "foo", Time: time.Unix(0, 1659469108710409242), Sequence: 1})
+	fr := newFakeRepository()
+	fr.lines[keyA] = service.NewLogLine(keyA, "fake value a")
+	fr.lines[keyB] = service.NewLogLine(keyB, "stored value b")
+
+	rd := fakeArchive(t,
+		&Record{Key: keyA, Bucket: "fake_bucket", Value: "fake value a"},
+		&Record{Key: keyB, Bucket: "fake_bucket", Value: "fake value b"},
+		&Record{Key: "bar_baz_1659469108710409500", Bucket: "fake_bucket", Value: "fake value c"},
+	)
+
+	rep, err := NewImporter(fr, 10).Import(context.Background(), rd, 0, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("unable to import, error %v", err)
+	}
+
+	if expected, got := 1, rep.Imported; expected != got {
+		t.Errorf("imported records do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := 1, rep.Skipped; expected != got {
+		t.Errorf("skipped records do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := 1, len(rep.Conflicts); expected != got {
+		t.Fatalf("conflicts do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := "stored value b", rep.Conflicts[0].Stored; expected != got {
+		t.Errorf("conflict stored value does not match, expected %s got %s", expected, got)
+	}
+
+	l, ok := fr.lines[linekey.Encode(linekey.Key{Source: "bar_baz", Time: time.Unix(0, 1659469108710409500)})]
+	if !ok {
+		t.Fatal("expected legacy key imported on versioned encoding")
+	}
+
+	if expected, got := time.Unix(0, 1659469108710409500).UTC(), l.Time(); !expected.Equal(got) {
+		t.Errorf("creation times do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItResumesImportFromCheckpointPosition(t *testing.T) {
+	records := []*Record{
+		{Key: "foo_1659469108710408961", Bucket: "fake_bucket", Value: "fake value a"},
+		{Key: "foo_1659469108710409242", Bucket: "fake_bucket", Value: "fake value b"},
+		{Key: "foo_1659469108710409500", Bucket: "fake_bucket", Value: "fake value c"},
+	}
+
+	fr := newFakeRepository()
+	fr.failAfter = 1
+	checkpoints := []int{}
+	_, err := NewImporter(fr, 1).Import(context.Background(), fakeArchive(t, records...), 0, func(p int) error {
+		checkpoints = append(checkpoints, p)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected interrupted import")
+	}
+
+	if expected, got := 1, len(checkpoints); expected != got {
+		t.Fatalf("checkpoints do not match, expected %d got %d", expected, got)
+	}
+
+	fr.failAfter = 0
+	rep, err := NewImporter(fr, 1).Import(context.Background(), fakeArchive(t, records...), checkpoints[0], func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("unable to resume import, error %v", err)
+	}
+
+	if expected, got := 2, rep.Imported; expected != got {
+		t.Errorf("imported records do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := 3, len(fr.lines); expected != got {
+		t.Errorf("stored keys do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItFailsImportingRecordsWithoutSourceKey(t *testing.T) {
+	rd := fakeArchive(t, &Record{Key: "foo", Bucket: "fake_bucket", Value: "fake value"})
+
+	_, err := NewImporter(newFakeRepository(), 10).Import(context.Background(), rd, 0, func(int) error { return nil })
+	if !errors.Is(err, service.ErrInvalidLogLineKey) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func fakeArchive(t *testing.T, records ...*Record) Reader {
+	buf := &bytes.Buffer{}
+	w, _ := NewWriter(FormatNDJSON, buf)
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("unable to write record, error %v", err)
+		}
+	}
+
+	rd, _ := NewReader(FormatNDJSON, buf)
+	return rd
+}
+
+var errFakeInterruption = errors.New("fake interruption")
+
+type fakeRepository struct {
+	service.Repository
+	lines     map[string]*service.LogLine
+	batches   int
+	failAfter int
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{lines: map[string]*service.LogLine{}}
+}
+
+func (f *fakeRepository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	if f.failAfter > 0 && f.batches >= f.failAfter {
+		return errFakeInterruption
+	}
+	f.batches++
+
+	for _, l := range lines {
+		f.lines[string(l.Key())] = l
+	}
+	return nil
+}
+
+func (f *fakeRepository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	l, ok := f.lines[key]
+	if !ok {
+		return nil, service.ErrLogLineNotFound
+	}
+	return l, nil
+}
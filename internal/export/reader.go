@@ -0,0 +1,79 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Reader reads exported records one by one, returns io.EOF once all records are read
+type Reader interface {
+	Read() (*Record, error)
+}
+
+// NewReader instantiates format reader on top of r, parquet archives are not supported
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonReader{decoder: json.NewDecoder(r)}, nil
+	case FormatCSV:
+		return &csvReader{reader: csv.NewReader(r)}, nil
+	}
+
+	return nil, fmt.Errorf("format %q, error %w", format, ErrUnknownFormat)
+}
+
+type ndjsonReader struct {
+	decoder *json.Decoder
+}
+
+func (r *ndjsonReader) Read() (*Record, error) {
+	rec := &Record{}
+	if err := r.decoder.Decode(rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+type csvReader struct {
+	reader     *csv.Reader
+	headerRead bool
+}
+
+func (r *csvReader) Read() (*Record, error) {
+	if !r.headerRead {
+		if _, err := r.reader.Read(); err != nil {
+			return nil, err
+		}
+		r.headerRead = true
+	}
+
+	row, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(row) != len(csvHeader) {
+		return nil, fmt.Errorf("unexpected csv row with %d columns, expected %d", len(row), len(csvHeader))
+	}
+
+	tx, err := strconv.ParseUint(row[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse key %s tx, error %w", row[0], err)
+	}
+
+	rv, err := strconv.ParseUint(row[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse key %s revision, error %w", row[0], err)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, row[5])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse key %s creation time, error %w", row[0], err)
+	}
+
+	return &Record{Key: row[0], Bucket: row[1], Value: row[2], Tx: tx, Revision: rv, CreatedAt: t}, nil
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// parquetRowGroupSize keeps parquet row groups small, so exports are flushed while streaming
+const parquetRowGroupSize = 8 * 1024 * 1024
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Record defines an exported log line
+type Record struct {
+	Key       string    `json:"key"`
+	Bucket    string    `json:"bucket"`
+	Value     string    `json:"value"`
+	Tx        uint64    `json:"tx"`
+	Revision  uint64    `json:"revision"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Writer writes records one by one, Close flushes pending records
+type Writer interface {
+	Write(r *Record) error
+	Close() error
+}
+
+// NewWriter instantiates format writer on top of w, closing the writer does not close w
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case FormatParquet:
+		pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRecord), 1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create parquet writer, error %w", err)
+		}
+		pw.RowGroupSize = parquetRowGroupSize
+		return &parquetWriter{writer: pw}, nil
+	}
+
+	return nil, fmt.Errorf("format %q, error %w", format, ErrUnknownFormat)
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(r *Record) error {
+	return w.encoder.Encode(r)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+var csvHeader = []string{"key", "bucket", "value", "tx", "revision", "created_at"}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(r *Record) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	return w.writer.Write([]string{
+		r.Key,
+		r.Bucket,
+		r.Value,
+		strconv.FormatUint(r.Tx, 10),
+		strconv.FormatUint(r.Revision, 10),
+		r.CreatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+type parquetRecord struct {
+	Key       string `parquet:"name=key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Bucket    string `parquet:"name=bucket, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Value     string `parquet:"name=value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Tx        int64  `parquet:"name=tx, type=INT64, convertedtype=UINT_64"`
+	Revision  int64  `parquet:"name=revision, type=INT64, convertedtype=UINT_64"`
+	CreatedAt int64  `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+}
+
+type parquetWriter struct {
+	writer *writer.ParquetWriter
+}
+
+func (w *parquetWriter) Write(r *Record) error {
+	return w.writer.Write(&parquetRecord{
+		Key:       r.Key,
+		Bucket:    r.Bucket,
+		Value:     r.Value,
+		Tx:        int64(r.Tx),
+		Revision:  int64(r.Revision),
+		CreatedAt: r.CreatedAt.UnixMicro(),
+	})
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.WriteStop()
+}
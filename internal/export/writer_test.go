@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+var fakeRecords = []*Record{
+	{Key: "foo_0", Bucket: "fake_bucket", Value: "fake value, with comma", Tx: 3, Revision: 1, CreatedAt: time.Unix(0, 1659469108710408961).UTC()},
+	{Key: "foo_1", Bucket: "fake_bucket", Value: "fake value b", Tx: 4, Revision: 2, CreatedAt: time.Unix(0, 1659469108710409242).UTC()},
+}
+
+func TestItWritesRecordsAsNewlineDelimitedJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writeAll(t, FormatNDJSON, buf)
+
+	res := []*Record{}
+	s := bufio.NewScanner(buf)
+	for s.Scan() {
+		r := &Record{}
+		if err := json.Unmarshal(s.Bytes(), r); err != nil {
+			t.Fatalf("unable to unmarshal line %s, error %v", s.Text(), err)
+		}
+		res = append(res, r)
+	}
+
+	if expected, got := len(fakeRecords), len(res); expected != got {
+		t.Fatalf("total records do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := *fakeRecords[1], *res[1]; expected != got {
+		t.Errorf("records do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItWritesRecordsAsCSVWithHeader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writeAll(t, FormatCSV, buf)
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unable to read csv, error %v", err)
+	}
+
+	if expected, got := len(fakeRecords)+1, len(rows); expected != got {
+		t.Fatalf("total rows do not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := "fake value, with comma", rows[1][2]; expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if expected, got := "2022-08-02T19:38:28.710408961Z", rows[1][5]; expected != got {
+		t.Errorf("creation times do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItWritesRecordsAsParquet(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writeAll(t, FormatParquet, buf)
+
+	f, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unable to open parquet buffer, error %v", err)
+	}
+
+	pr, err := reader.NewParquetReader(f, new(parquetRecord), 1)
+	if err != nil {
+		t.Fatalf("unable to create parquet reader, error %v", err)
+	}
+	defer pr.ReadStop()
+
+	if expected, got := int64(len(fakeRecords)), pr.GetNumRows(); expected != got {
+		t.Fatalf("total rows do not match, expected %d got %d", expected, got)
+	}
+
+	res := make([]parquetRecord, len(fakeRecords))
+	if err := pr.Read(&res); err != nil {
+		t.Fatalf("unable to read parquet rows, error %v", err)
+	}
+
+	if expected, got := fakeRecords[1].Key, res[1].Key; expected != got {
+		t.Errorf("keys do not match, expected %s got %s", expected, got)
+	}
+
+	if expected, got := fakeRecords[1].CreatedAt.UnixMicro(), res[1].CreatedAt; expected != got {
+		t.Errorf("creation times do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItReadsBackWrittenCSVRecords(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writeAll(t, FormatCSV, buf)
+
+	rd, err := NewReader(FormatCSV, buf)
+	if err != nil {
+		t.Fatalf("unable to create reader, error %v", err)
+	}
+
+	for _, expected := range fakeRecords {
+		got, err := rd.Read()
+		if err != nil {
+			t.Fatalf("unable to read record, error %v", err)
+		}
+
+		if *expected != *got {
+			t.Errorf("records do not match, expected %v got %v", expected, got)
+		}
+	}
+
+	if _, err := rd.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected end of archive, got %v", err)
+	}
+}
+
+func TestItFailsOnUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xml", &bytes.Buffer{})
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func writeAll(t *testing.T, format string, buf *bytes.Buffer) {
+	w, err := NewWriter(format, buf)
+	if err != nil {
+		t.Fatalf("unable to create writer, error %v", err)
+	}
+
+	for _, r := range fakeRecords {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("unable to write record, error %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unable to close writer, error %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const checkTimeout = time.Second * 2
+
+// Check fails while a dependency is not usable
+type Check func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   Check
+}
+
+// Health reports liveness and readiness, readiness requires all checks to pass
+type Health struct {
+	checks []check
+}
+
+// New instantiates Health without checks, so it is ready
+func New() *Health {
+	return &Health{}
+}
+
+// WithCheck adds a named readiness check
+func (h *Health) WithCheck(name string, c Check) *Health {
+	h.checks = append(h.checks, check{name: name, fn: c})
+	return h
+}
+
+// Ready runs readiness checks on order, returning first failure
+func (h *Health) Ready(ctx context.Context) error {
+	for _, c := range h.checks {
+		if err := c.fn(ctx); err != nil {
+			return fmt.Errorf("%s check failed, error %w", c.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Liveness answers while the process serves requests, dependencies are left to readiness so they do not get it restarted
+func (h *Health) Liveness(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Readiness answers service unavailable with its failure while a check fails
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	if err := h.Ready(ctx); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(err.Error() + "\n"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Handler serves /healthz and /readyz, other paths go to next
+func (h *Health) Handler(next http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.Liveness)
+	mux.HandleFunc("/readyz", h.Readiness)
+	mux.Handle("/", next)
+
+	return mux
+}
+
+// Watch updates grpc health server overall status from readiness on each interval until context is done
+func (h *Health) Watch(ctx context.Context, s *health.Server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	serving := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		st := healthpb.HealthCheckResponse_SERVING
+		cctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := h.Ready(cctx)
+		cancel()
+		if err != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if st != serving {
+			log.Printf("Health status %s, error %v", st, err)
+			serving = st
+		}
+		s.SetServingStatus("", st)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
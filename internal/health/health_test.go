@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestItAnswersReadinessFromChecks(t *testing.T) {
+	var fail error
+	h := New().WithCheck("fake", func(ctx context.Context) error { return fail })
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	srv := h.Handler(next)
+
+	for _, tc := range []struct {
+		path     string
+		fail     error
+		expected int
+	}{
+		{"/healthz", nil, http.StatusOK},
+		{"/readyz", nil, http.StatusOK},
+		{"/readyz", errors.New("fake failure"), http.StatusServiceUnavailable},
+		{"/healthz", errors.New("fake failure"), http.StatusOK},
+		{"/v1/logs", nil, http.StatusTeapot},
+	} {
+		fail = tc.fail
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if expected, got := tc.expected, rec.Code; expected != got {
+			t.Errorf("%s status codes do not match on failure %v, expected %d got %d", tc.path, tc.fail, expected, got)
+		}
+	}
+}
+
+func TestItWatchesReadinessOnGrpcHealthServer(t *testing.T) {
+	fail := make(chan error, 1)
+	fail <- errors.New("fake failure")
+	var last error
+	h := New().WithCheck("fake", func(ctx context.Context) error {
+		select {
+		case last = <-fail:
+		default:
+		}
+		return last
+	})
+
+	s := health.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Watch(ctx, s, time.Millisecond*10)
+
+	waitStatus(t, s, healthpb.HealthCheckResponse_NOT_SERVING)
+	fail <- nil
+	waitStatus(t, s, healthpb.HealthCheckResponse_SERVING)
+}
+
+func waitStatus(t *testing.T, s *health.Server, expected healthpb.HealthCheckResponse_ServingStatus) {
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		res, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		if err == nil && res.Status == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("health status not %s, got %v error %v", expected, res, err)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
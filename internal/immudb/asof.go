@@ -0,0 +1,375 @@
+package immudb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// historyPageSize limits key history pages walked looking for past revisions
+const historyPageSize = 100
+
+// keyPrefix and sortedSetPrefix tag immudb transaction entry keys, as key writes or sorted set entries
+const (
+	keyPrefix       = 0
+	sortedSetPrefix = 1
+)
+
+// TxAt returns last transaction committed at or before t, zero if no transaction was committed by then.
+// Transactions are binary searched by its header timestamp, which has seconds precision
+func (r *repository) TxAt(ctx context.Context, t time.Time) (uint64, error) {
+	ctx, end := observe(ctx, "tx_at")
+	defer end()
+
+	st, err := r.client().CurrentState(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get immudb current state, error %w", err)
+	}
+
+	var res uint64
+	lo, hi := uint64(1), st.GetTxId()
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+		tx, err := r.client().GetServiceClient().TxById(ctx, &schema.TxRequest{Tx: mid, EntriesSpec: excludeEntries})
+		if err != nil {
+			return 0, fmt.Errorf("unable to get tx %d, error %w", mid, err)
+		}
+
+		if time.Unix(tx.GetHeader().GetTs(), 0).After(t) {
+			hi = mid - 1
+			continue
+		}
+		res = mid
+		lo = mid + 1
+	}
+
+	return res, nil
+}
+
+// GetByKeyAt returns logLine value as it was on transaction tx, lines expired by then are not found
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	ctx, end := observe(ctx, "get_by_key_at")
+	defer end()
+
+	e, err := r.entryAt(ctx, key, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := r.txTime(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	expired, err := r.expired(ctx, key, at)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, fmt.Errorf("unable to get key %s error %w", key, errLogLineExpired)
+	}
+
+	return service.DecodeLogLine(key, string(e.GetValue())), nil
+}
+
+// GetByPrefixAt gets logLines with prefixed key as they were on transaction tx sorted by key. Keys are found from
+// transactions up to tx, so keys written later are left out and keys deleted afterwards are found too
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	ctx, end := observe(ctx, "get_by_prefix_at")
+	defer end()
+
+	if tx == 0 {
+		return []*service.LogLine{}, nil
+	}
+	at, err := r.txTime(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	seen := map[string]struct{}{}
+	err = r.txKeys(ctx, tx, func(k *txKey) error {
+		if k.set != "" || !strings.HasPrefix(k.key, prefix) || filterSelfSystemKey(k.key) {
+			return nil
+		}
+		if _, ok := seen[k.key]; !ok {
+			seen[k.key] = struct{}{}
+			keys = append(keys, k.key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	all, err := r.client().Scan(ctx, &schema.ScanRequest{
+		Prefix: []byte(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get keys by prefix, error %v", err)
+	}
+	current := map[string]*schema.Entry{}
+	for _, entry := range all.Entries {
+		current[string(entry.GetKey())] = entry
+	}
+
+	return r.linesAt(ctx, keys, current, tx, at)
+}
+
+// GetByBucketAt gets bucket logLines as they were on transaction tx sorted by creation time. Keys are found from
+// sorted set entries added up to tx, so lines written later are left out and lines deleted afterwards are found too
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	ctx, end := observe(ctx, "get_by_bucket_at")
+	defer end()
+
+	if tx == 0 {
+		return []*service.LogLine{}, nil
+	}
+	at, err := r.txTime(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	var cutoff time.Time
+	if r.retention != nil {
+		cutoff, _ = r.retention.Cutoff(bucket, at)
+	}
+
+	keys := []*txKey{}
+	seen := map[string]struct{}{}
+	err = r.txKeys(ctx, tx, func(k *txKey) error {
+		if k.set != bucket || (!cutoff.IsZero() && k.score < float64(cutoff.UnixNano())) {
+			return nil
+		}
+		if _, ok := seen[k.key]; ok {
+			return nil
+		}
+		if err := r.checkResultSize(bucket, len(keys)+1); err != nil {
+			return err
+		}
+		seen[k.key] = struct{}{}
+		keys = append(keys, k)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get keys by bucket, error %w", err)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].score != keys[j].score {
+			return keys[i].score < keys[j].score
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	current := map[string]*schema.Entry{}
+	err = r.scanBucket(ctx, bucket, time.Time{}, time.Time{}, func(entry *schema.ZEntry) error {
+		current[string(entry.GetKey())] = entry.GetEntry()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get keys by bucket, error %w", err)
+	}
+
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.key)
+	}
+
+	return r.linesAt(ctx, names, current, tx, at)
+}
+
+// linesAt returns keys log lines as they were on transaction tx committed at at, keys not written or already deleted
+// by then and lines expired by then are left out. Keys whose current entry was written on or before tx need no extra
+// reads, others are resolved from its history concurrently, bounded by read concurrency
+func (r *repository) linesAt(ctx context.Context, keys []string, current map[string]*schema.Entry, tx uint64, at time.Time) ([]*service.LogLine, error) {
+	expired, err := r.expiredKeys(ctx, keys, at)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*service.LogLine, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		if _, ok := expired[key]; ok {
+			continue
+		}
+		if entry, ok := current[key]; ok && entry.GetTx() <= tx {
+			lines[i] = service.DecodeLogLine(key, string(entry.GetValue()))
+			continue
+		}
+
+		release, err := r.acquireRead(ctx)
+		if err != nil {
+			errs[i] = err
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			defer release()
+			lines[i], errs[i] = r.lineAt(ctx, key, tx)
+		}(i, key)
+	}
+	wg.Wait()
+
+	logs := []*service.LogLine{}
+	for i, l := range lines {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if l != nil {
+			logs = append(logs, l)
+		}
+	}
+
+	return logs, nil
+}
+
+// txTime returns transaction tx commit time, transactions not committed yet get the last committed one time
+func (r *repository) txTime(ctx context.Context, tx uint64) (time.Time, error) {
+	st, err := r.client().CurrentState(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get immudb current state, error %w", err)
+	}
+	if tx > st.GetTxId() {
+		tx = st.GetTxId()
+	}
+
+	t, err := r.client().GetServiceClient().TxById(ctx, &schema.TxRequest{Tx: tx, EntriesSpec: excludeEntries})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get tx %d, error %w", tx, err)
+	}
+
+	return time.Unix(t.GetHeader().GetTs(), 0), nil
+}
+
+// entryDigests includes key and sorted set entries on transaction scans, without its values
+var entryDigests = &schema.EntriesSpec{
+	KvEntriesSpec:  &schema.EntryTypeSpec{Action: schema.EntryTypeAction_ONLY_DIGEST},
+	ZEntriesSpec:   &schema.EntryTypeSpec{Action: schema.EntryTypeAction_ONLY_DIGEST},
+	SqlEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+}
+
+// txKey is a key written on a transaction, sorted set entries carry its set and score
+type txKey struct {
+	key   string
+	set   string
+	score float64
+}
+
+// txKeys calls fn on keys written and sorted set entries added on transactions up to tx, transactions are scanned
+// by pages. Transactions keep keys deleted afterwards, so they are found too
+func (r *repository) txKeys(ctx context.Context, tx uint64, fn func(k *txKey) error) error {
+	req := &schema.TxScanRequest{InitialTx: 1, EntriesSpec: entryDigests}
+	for req.InitialTx <= tx {
+		req.Limit = txScanPageSize
+		if left := tx - req.InitialTx + 1; left < txScanPageSize {
+			req.Limit = uint32(left)
+		}
+		txs, err := r.client().TxScan(ctx, req)
+		if err != nil {
+			return fmt.Errorf("unable to scan transactions, error %w", err)
+		}
+		if len(txs.GetTxs()) == 0 {
+			return nil
+		}
+
+		for _, t := range txs.GetTxs() {
+			for _, e := range t.GetEntries() {
+				k, ok := decodeTxKey(e.GetKey())
+				if !ok {
+					continue
+				}
+				if err := fn(k); err != nil {
+					return err
+				}
+			}
+			req.InitialTx = t.GetHeader().GetId() + 1
+		}
+	}
+
+	return nil
+}
+
+// decodeTxKey returns key from an immudb transaction entry key, sorted set entry keys are laid out as
+// [prefix, set length, set, score, key length, key prefix, key, tx]
+func decodeTxKey(raw []byte) (*txKey, bool) {
+	if len(raw) < 2 {
+		return nil, false
+	}
+	switch raw[0] {
+	case keyPrefix:
+		return &txKey{key: string(raw[1:])}, true
+	case sortedSetPrefix:
+	default:
+		return nil, false
+	}
+
+	if len(raw) < 9 {
+		return nil, false
+	}
+	setLen := binary.BigEndian.Uint64(raw[1:])
+	keyOff := 9 + setLen + 16
+	if setLen > uint64(len(raw)) || uint64(len(raw)) < keyOff+1+8 {
+		return nil, false
+	}
+
+	return &txKey{
+		set:   string(raw[9 : 9+setLen]),
+		score: math.Float64frombits(binary.BigEndian.Uint64(raw[9+setLen:])),
+		key:   string(raw[keyOff+1 : uint64(len(raw))-8]),
+	}, true
+}
+
+// lineAt returns key log line as it was on transaction tx, nil if it was written later or deleted by then
+func (r *repository) lineAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	e, err := r.entryAt(ctx, key, tx)
+	if errors.Is(err, service.ErrLogLineNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DecodeLogLine(key, string(e.GetValue())), nil
+}
+
+// entryAt walks key history from its last revision, returning the revision set on or before transaction tx
+func (r *repository) entryAt(ctx context.Context, key string, tx uint64) (*schema.Entry, error) {
+	for offset := uint64(0); ; offset += historyPageSize {
+		h, err := r.client().History(ctx, &schema.HistoryRequest{Key: []byte(key), Desc: true, Offset: offset, Limit: historyPageSize})
+		if isKeyNotFound(err) || isStoreError(err, store.ErrOffsetOutOfRange) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to get key %s history, error %w", key, err)
+		}
+
+		for _, e := range h.GetEntries() {
+			if e.GetTx() > tx {
+				continue
+			}
+			if e.GetMetadata().GetDeleted() {
+				return nil, fmt.Errorf("key %s deleted on tx %d, error %w", key, e.GetTx(), service.ErrLogLineNotFound)
+			}
+			return e, nil
+		}
+
+		if len(h.GetEntries()) < historyPageSize {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("key %s not written on tx %d, error %w", key, tx, service.ErrLogLineNotFound)
+}
@@ -0,0 +1,147 @@
+package immudb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItReadsLogLinesAsTheyWereOnPastTransactions(t *testing.T) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	bucket := "fake_asof_bucket"
+	ts := time.Now()
+	if err := r.Add(ctx, service.NewLogLineWithBucket(bucket, "asof_0", "fake value", ts)); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	st, err := r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+	tx := st.GetTxId()
+
+	if err := r.Add(ctx, service.NewLogLineWithBucket(bucket, "asof_0", "fake value X", ts)); err != nil {
+		t.Fatalf("unexpected error updating line, error %v", err)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket(bucket, "asof_1", "fake value", ts)); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	l, err := r.GetByKeyAt(ctx, "asof_0", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if _, err := r.GetByKeyAt(ctx, "asof_1", tx); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting key written later, got %v", err)
+	}
+
+	byPrefix, err := r.GetByPrefixAt(ctx, "asof_", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	byBucket, err := r.GetByBucketAt(ctx, bucket, tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by bucket, error %v", err)
+	}
+
+	for _, ls := range [][]*service.LogLine{byPrefix, byBucket} {
+		if expected, got := 1, len(ls); expected != got {
+			t.Fatalf("lines do not match, expected %d got %d", expected, got)
+		}
+		if expected, got := "fake value", string(ls[0].Value()); expected != got {
+			t.Errorf("values do not match, expected %s got %s", expected, got)
+		}
+	}
+}
+
+func TestItReadsLogLinesDeletedAfterPastTransactions(t *testing.T) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	bucket := "fake_asof_deleted_bucket"
+	ts := time.Now()
+	if err := r.AddBatch(ctx, []*service.LogLine{
+		service.NewLogLineWithBucket(bucket, "asofdel_0", "fake value", ts),
+		service.NewLogLineWithBucket(bucket, "asofdel_1", "fake value", ts.Add(time.Nanosecond)),
+	}); err != nil {
+		t.Fatalf("unexpected error adding lines, error %v", err)
+	}
+	st, err := r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+	tx := st.GetTxId()
+
+	if err := r.DeleteLogLines(ctx, []string{"asofdel_0"}); err != nil {
+		t.Fatalf("unexpected error deleting line, error %v", err)
+	}
+
+	byPrefix, err := r.GetByPrefixAt(ctx, "asofdel_", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	byBucket, err := r.GetByBucketAt(ctx, bucket, tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by bucket, error %v", err)
+	}
+
+	for _, ls := range [][]*service.LogLine{byPrefix, byBucket} {
+		if expected, got := 2, len(ls); expected != got {
+			t.Fatalf("lines do not match, expected %d got %d", expected, got)
+		}
+		if expected, got := "asofdel_0", string(ls[0].Key()); expected != got {
+			t.Errorf("keys do not match, expected %s got %s", expected, got)
+		}
+	}
+
+	st, err = r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+	byPrefix, err = r.GetByPrefixAt(ctx, "asofdel_", st.GetTxId())
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	if expected, got := 1, len(byPrefix); expected != got {
+		t.Errorf("lines do not match after delete, expected %d got %d", expected, got)
+	}
+}
+
+func TestItResolvesTransactionsCommittedByTime(t *testing.T) {
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	st, err := r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+
+	tx, err := r.TxAt(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error resolving tx, error %v", err)
+	}
+	if expected, got := st.GetTxId(), tx; expected != got {
+		t.Errorf("transactions do not match, expected %d got %d", expected, got)
+	}
+
+	tx, err = r.TxAt(ctx, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error resolving tx, error %v", err)
+	}
+	if expected, got := uint64(0), tx; expected != got {
+		t.Errorf("transactions do not match, expected %d got %d", expected, got)
+	}
+}
@@ -0,0 +1,71 @@
+package immudb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"google.golang.org/protobuf/proto"
+)
+
+// AuditByBucket proves bucket log lines created on [from, to] against immudb current state, state is handed first.
+// Each line carries its verifiable entry, with its inclusion proof on its transaction and the dual proof from it to the state.
+// Lines written after the state is taken are left out, as they can not be proven against it
+func (r *repository) AuditByBucket(ctx context.Context, bucket string, from, to time.Time, state func(*service.AuditState) error, fn func(*service.AuditedLogLine) error) error {
+	ctx, end := observe(ctx, "audit_by_bucket")
+	defer end()
+
+	st, err := r.client().CurrentState(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get immudb current state, error %w", err)
+	}
+
+	raw, err := proto.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("unable to marshal immudb state, error %w", err)
+	}
+
+	err = state(&service.AuditState{Database: st.GetDb(), TxID: st.GetTxId(), Signed: st.GetSignature() != nil, State: raw})
+	if err != nil {
+		return fmt.Errorf("unable to hand audit state, error %w", err)
+	}
+
+	var newer int
+	err = r.scanBucket(ctx, bucket, from, to, func(entry *schema.ZEntry) error {
+		if entry.GetEntry().GetTx() > st.GetTxId() {
+			newer++
+			return nil
+		}
+
+		ve, err := r.client().GetServiceClient().VerifiableGet(ctx, &schema.VerifiableGetRequest{
+			KeyRequest:   &schema.KeyRequest{Key: entry.GetKey(), AtTx: entry.GetEntry().GetTx()},
+			ProveSinceTx: st.GetTxId(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get key %s proof, error %w", string(entry.GetKey()), err)
+		}
+
+		proof, err := proto.Marshal(ve)
+		if err != nil {
+			return fmt.Errorf("unable to marshal key %s proof, error %w", string(entry.GetKey()), err)
+		}
+
+		if err := fn(&service.AuditedLogLine{ExportedLogLine: *exportedLogLine(bucket, entry), Proof: proof}); err != nil {
+			return fmt.Errorf("unable to audit key %s, error %w", string(entry.GetKey()), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if newer > 0 {
+		log.Printf("Audit on bucket %s left out %d log lines written after tx %d", bucket, newer, st.GetTxId())
+	}
+
+	return nil
+}
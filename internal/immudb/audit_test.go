@@ -0,0 +1,87 @@
+package immudb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/audit"
+	"github.com/marcosQuesada/log-api/internal/export"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestItBuildsAuditBundlesVerifiableOffline(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	r := NewRepository(cl)
+	bucket := "fake_audit_bucket"
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket(bucket, "audit_00", "fake value", time.Now()),
+		service.NewLogLineWithBucket(bucket, "audit_01", "fake value 0", time.Now().Add(time.Nanosecond)),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("another_bucket", "audit_02", "fake value 1", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	w := audit.NewWriter(buf)
+	var audited []*audit.Line
+	state := func(s *service.AuditState) error {
+		return w.WriteState(&audit.State{Database: s.Database, TxID: s.TxID, Signed: s.Signed, State: s.State})
+	}
+	err := r.AuditByBucket(ctx, bucket, time.Time{}, time.Time{}, state, func(l *service.AuditedLogLine) error {
+		al := &audit.Line{
+			Record: export.Record{Key: l.Key, Bucket: l.Bucket, Value: string(l.Value), Tx: l.Tx, Revision: l.Revision, CreatedAt: l.CreatedAt},
+			Proof:  l.Proof,
+		}
+		audited = append(audited, al)
+		return w.WriteLine(al)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error auditing bucket, error %v", err)
+	}
+	if err := w.Close(&audit.Manifest{Bucket: bucket}); err != nil {
+		t.Fatalf("unexpected error closing bundle, error %v", err)
+	}
+
+	res, err := audit.Verify(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("unexpected error verifying bundle, error %v", err)
+	}
+
+	if expected, got := len(lines), res.Verified; expected != got {
+		t.Errorf("verified lines do not match, expected %d got %d", expected, got)
+	}
+
+	st, err := audit.ParseState(res.State)
+	if err != nil {
+		t.Fatalf("unexpected error parsing state, error %v", err)
+	}
+
+	audited[0].Value = "tampered value"
+	if err := audit.VerifyLine(st, audited[0]); !errors.Is(err, audit.ErrProofMismatch) {
+		t.Errorf("unexpected error verifying tampered line, got %v", err)
+	}
+
+	ve := &schema.VerifiableEntry{}
+	if err := proto.Unmarshal(audited[1].Proof, ve); err != nil {
+		t.Fatalf("unexpected error unmarshalling proof, error %v", err)
+	}
+	ve.Entry.Value = []byte("tampered value")
+	audited[1].Value = "tampered value"
+	if audited[1].Proof, err = proto.Marshal(ve); err != nil {
+		t.Fatalf("unexpected error marshalling proof, error %v", err)
+	}
+	if err := audit.VerifyLine(st, audited[1]); !errors.Is(err, audit.ErrInvalidProof) {
+		t.Errorf("unexpected error verifying tampered proof, got %v", err)
+	}
+}
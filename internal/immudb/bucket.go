@@ -0,0 +1,174 @@
+package immudb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	immuerrors "github.com/codenotary/immudb/pkg/client/errors"
+	"github.com/marcosQuesada/log-api/internal/retention"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// bucketKeyPrefix defines immudb key prefix to store buckets metadata
+const bucketKeyPrefix = "bucket:"
+
+// zScanPageSize defines sorted set page size, immudb refuses full pages of its 1000 results cap
+const zScanPageSize = 500
+
+// CreateBucket stores bucket metadata, fails if it already exists
+func (r *repository) CreateBucket(ctx context.Context, b *service.Bucket) error {
+	ctx, end := observe(ctx, "create_bucket")
+	defer end()
+
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("unable to marshal bucket %s, error %w", b.Name, err)
+	}
+
+	key := bucketKey(b.Name)
+	_, err = r.client().SetAll(ctx, &schema.SetRequest{
+		KVs:           []*schema.KeyValue{{Key: key, Value: raw}},
+		Preconditions: []*schema.Precondition{schema.PreconditionKeyMustNotExist(key)},
+	})
+	if err != nil && immuerrors.FromError(err) != nil && immuerrors.FromError(err).Code() == immuerrors.CodIntegrityConstraintViolation {
+		return fmt.Errorf("unable to create bucket %s, error %w", b.Name, service.ErrBucketAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create bucket %s, error %w", b.Name, err)
+	}
+
+	r.registerRetention(b)
+	return nil
+}
+
+// UpdateBucket stores a new bucket metadata revision
+func (r *repository) UpdateBucket(ctx context.Context, b *service.Bucket) error {
+	ctx, end := observe(ctx, "update_bucket")
+	defer end()
+
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("unable to marshal bucket %s, error %w", b.Name, err)
+	}
+
+	if _, err := r.client().Set(ctx, bucketKey(b.Name), raw); err != nil {
+		return fmt.Errorf("unable to update bucket %s, error %w", b.Name, err)
+	}
+
+	r.registerRetention(b)
+	return nil
+}
+
+// GetBucket returns bucket metadata
+func (r *repository) GetBucket(ctx context.Context, name string) (*service.Bucket, error) {
+	ctx, end := observe(ctx, "get_bucket")
+	defer end()
+
+	e, err := r.client().Get(ctx, bucketKey(name))
+	if err != nil && immuerrors.FromError(err) != nil && errors.Is(immuerrors.FromError(err), store.ErrKeyNotFound) {
+		return nil, fmt.Errorf("unable to get bucket %s, error %w", name, service.ErrBucketNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get bucket %s, error %w", name, err)
+	}
+
+	b := &service.Bucket{}
+	if err := json.Unmarshal(e.Value, b); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal bucket %s, error %w", name, err)
+	}
+
+	return b, nil
+}
+
+// ListBuckets returns all buckets metadata, scanned by pages
+func (r *repository) ListBuckets(ctx context.Context) ([]*service.Bucket, error) {
+	ctx, end := observe(ctx, "list_buckets")
+	defer end()
+
+	res := []*service.Bucket{}
+	req := &schema.ScanRequest{Prefix: []byte(bucketKeyPrefix), Limit: keyScanPageSize}
+	for {
+		page, err := r.client().Scan(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan buckets, error %w", err)
+		}
+
+		for _, entry := range page.GetEntries() {
+			b := &service.Bucket{}
+			if err := json.Unmarshal(entry.Value, b); err != nil {
+				return nil, fmt.Errorf("unable to unmarshal bucket %s, error %w", string(entry.Key), err)
+			}
+			res = append(res, b)
+		}
+
+		if len(page.GetEntries()) < int(req.Limit) {
+			return res, nil
+		}
+		req.SeekKey = page.GetEntries()[len(page.GetEntries())-1].GetKey()
+	}
+}
+
+// CountBucketLines returns total non expired bucket log lines
+func (r *repository) CountBucketLines(ctx context.Context, name string) (uint64, error) {
+	ctx, end := observe(ctx, "count_bucket_lines")
+	defer end()
+
+	req := &schema.ZScanRequest{Set: []byte(name)}
+	if r.retention != nil {
+		if c, ok := r.retention.Cutoff(name, time.Now()); ok {
+			req.MinScore = &schema.Score{Score: float64(c.UnixNano())}
+		}
+	}
+
+	var total uint64
+	err := r.zScan(ctx, req, func(*schema.ZEntry) error {
+		total++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// loadBucketsRetention registers all buckets retention as retention policies
+func (r *repository) loadBucketsRetention(ctx context.Context) error {
+	if r.retention == nil {
+		return nil
+	}
+
+	all, err := r.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range all {
+		r.registerRetention(b)
+	}
+
+	return nil
+}
+
+func (r *repository) registerRetention(b *service.Bucket) {
+	if r.retention == nil || b.Retention == retention.Forever {
+		return
+	}
+
+	r.retention.Set(b.Name, b.Retention)
+}
+
+func bucketKey(name string) []byte {
+	return []byte(bucketKeyPrefix + name)
+}
+
+// isBucketKey returns true on bucket metadata keys
+func isBucketKey(key string) bool {
+	return strings.HasPrefix(key, bucketKeyPrefix)
+}
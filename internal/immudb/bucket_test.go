@@ -0,0 +1,190 @@
+package immudb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/marcosQuesada/log-api/internal/retention"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItCreatesBucketAndFailsOnDuplicatedCreation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	b := &service.Bucket{Name: "fake_created_bucket", Owner: "fake_owner", Retention: time.Hour, CreatedAt: time.Now()}
+	if err := r.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+
+	if err := r.CreateBucket(ctx, b); !errors.Is(err, service.ErrBucketAlreadyExists) {
+		t.Fatalf("unexpected error type, got %v", err)
+	}
+
+	res, err := r.GetBucket(ctx, b.Name)
+	if err != nil {
+		t.Fatalf("unexpected error getting bucket, error %v", err)
+	}
+
+	if expected, got := b.Owner, res.Owner; expected != got {
+		t.Errorf("owner does not match, expected %s got %s", expected, got)
+	}
+
+	if expected, got := b.Retention, res.Retention; expected != got {
+		t.Errorf("retention does not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItRegistersCreatedBucketRetentionPolicy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := retention.NewPolicies()
+	r := NewRepository(cl).WithRetention(p, false)
+	b := &service.Bucket{Name: "fake_retained_bucket", Retention: time.Hour * 24, CreatedAt: time.Now()}
+	if err := r.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+
+	if expected, got := b.Retention, p.MaxAge(b.Name); expected != got {
+		t.Errorf("retention does not match, expected %v got %v", expected, got)
+	}
+
+	reloaded := retention.NewPolicies()
+	if err := NewRepository(cl).WithRetention(reloaded, false).Initialize(ctx); err != nil {
+		t.Fatalf("unexpected error initializing repository, error %v", err)
+	}
+
+	if expected, got := b.Retention, reloaded.MaxAge(b.Name); expected != got {
+		t.Errorf("reloaded retention does not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItFailsGettingNonExistentBucket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	if _, err := r.GetBucket(ctx, "fake_non_existent_bucket"); !errors.Is(err, service.ErrBucketNotFound) {
+		t.Fatalf("unexpected error type, got %v", err)
+	}
+}
+
+func TestItListsBucketsUpdatedMetadataWithoutLeakingIntoLogLines(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	b := &service.Bucket{Name: "fake_listed_bucket", CreatedAt: time.Now()}
+	if err := r.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+
+	b.Archived = true
+	if err := r.UpdateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error updating bucket, error %v", err)
+	}
+
+	all, err := r.ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing buckets, error %v", err)
+	}
+
+	found := false
+	for _, bucket := range all {
+		if bucket.Name == b.Name {
+			found = bucket.Archived
+		}
+	}
+	if !found {
+		t.Errorf("expected archived bucket %s on bucket list", b.Name)
+	}
+
+	lines, err := r.GetByPrefix(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+
+	for _, line := range lines {
+		if isBucketKey(string(line.Key())) {
+			t.Errorf("unexpected bucket key %s as log line", string(line.Key()))
+		}
+	}
+}
+
+func TestItListsBucketsOverScanResultsLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := [][]byte{}
+	kvs := []*schema.KeyValue{}
+	for i := 0; i < 1001; i++ {
+		b := &service.Bucket{Name: fmt.Sprintf("fake_many_bucket_%04d", i), CreatedAt: time.Now()}
+		raw, _ := json.Marshal(b)
+		keys = append(keys, bucketKey(b.Name))
+		kvs = append(kvs, &schema.KeyValue{Key: bucketKey(b.Name), Value: raw})
+		if len(kvs) == zScanPageSize || i == 1000 {
+			if _, err := cl.SetAll(ctx, &schema.SetRequest{KVs: kvs}); err != nil {
+				t.Fatalf("unexpected error storing buckets, error %v", err)
+			}
+			kvs = []*schema.KeyValue{}
+		}
+	}
+	defer func() {
+		for i := 0; i < len(keys); i += zScanPageSize {
+			end := i + zScanPageSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			_, _ = cl.Delete(context.Background(), &schema.DeleteKeysRequest{Keys: keys[i:end]})
+		}
+	}()
+
+	all, err := NewRepository(cl).ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing buckets, error %v", err)
+	}
+
+	total := 0
+	for _, b := range all {
+		if strings.HasPrefix(b.Name, "fake_many_bucket_") {
+			total++
+		}
+	}
+	if expected, got := 1001, total; expected != got {
+		t.Errorf("listed buckets do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItCountsBucketLogLines(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	bucket := "fake_counted_bucket"
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket(bucket, "count_00", "fake value", time.Now()),
+		service.NewLogLineWithBucket(bucket, "count_01", "fake value 0", time.Now().Add(time.Nanosecond)),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	total, err := r.CountBucketLines(ctx, bucket)
+	if err != nil {
+		t.Fatalf("unexpected error counting bucket lines, error %v", err)
+	}
+
+	if expected, got := uint64(2), total; expected != got {
+		t.Errorf("total does not match, expected %d got %d", expected, got)
+	}
+}
@@ -0,0 +1,12 @@
+package immudb
+
+import (
+	"testing"
+
+	"github.com/marcosQuesada/log-api/internal/repotest"
+)
+
+func TestItPassesRepositoryConformance(t *testing.T) {
+	defer reset()
+	repotest.Run(t, NewRepository(cl))
+}
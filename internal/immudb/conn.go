@@ -0,0 +1,339 @@
+package immudb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultKeepAliveInterval = time.Second * 20
+	defaultMinBackoff        = time.Millisecond * 100
+	defaultMaxBackoff        = time.Second * 10
+	closeSessionTimeout      = time.Second * 3
+)
+
+// sessionLostMessages matches immudb errors on unknown sessions, as sessions are gone after immudb restarts or expires them
+var sessionLostMessages = []string{"no session found", "session not found"}
+
+var (
+	ErrConnClosed   = errors.New("immudb connection closed")
+	ErrConnNotReady = errors.New("immudb session not ready")
+)
+
+// ConnState reports immudb session state
+type ConnState int
+
+const (
+	StateConnecting ConnState = iota
+	StateReady
+	StateReconnecting
+	StateClosed
+)
+
+var connStates = map[ConnState]string{
+	StateConnecting:   "connecting",
+	StateReady:        "ready",
+	StateReconnecting: "reconnecting",
+	StateClosed:       "closed",
+}
+
+func (s ConnState) String() string {
+	return connStates[s]
+}
+
+// clientSource returns immudb client used on each request
+type clientSource interface {
+	Client() client.ImmuClient
+}
+
+// staticClient serves the same client on each request, its session is not reopened
+type staticClient struct {
+	client.ImmuClient
+}
+
+func (s staticClient) Client() client.ImmuClient {
+	return s.ImmuClient
+}
+
+// Conn keeps an immudb session open. Each session is kept alive and checked on an interval,
+// once lost, as on immudb restarts, a new session is opened with exponential backoff and swapped in
+type Conn struct {
+	options           *client.Options
+	keepAliveInterval time.Duration
+	minBackoff        time.Duration
+	maxBackoff        time.Duration
+
+	mutex  sync.RWMutex
+	client client.ImmuClient
+	state  ConnState
+	gen    uint64
+
+	lost chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewConn instantiates immudb connection, options hold session credentials and database
+func NewConn(o *client.Options) *Conn {
+	return &Conn{
+		options:           o,
+		keepAliveInterval: defaultKeepAliveInterval,
+		minBackoff:        defaultMinBackoff,
+		maxBackoff:        defaultMaxBackoff,
+		state:             StateConnecting,
+		lost:              make(chan struct{}, 1),
+		done:              make(chan struct{}),
+	}
+}
+
+// WithKeepAlive sets session keep alive and check interval
+func (c *Conn) WithKeepAlive(interval time.Duration) *Conn {
+	c.keepAliveInterval = interval
+	return c
+}
+
+// WithBackoff sets reopen retries backoff, it doubles on each failure up to max
+func (c *Conn) WithBackoff(min, max time.Duration) *Conn {
+	c.minBackoff = min
+	c.maxBackoff = max
+	return c
+}
+
+// Open opens the first session, failures are returned so unknown databases get refused.
+// Sessions outlive ctx, they are released on Close
+func (c *Conn) Open(ctx context.Context) error {
+	cl, err := c.open(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.client = cl
+	c.state = StateReady
+	c.mutex.Unlock()
+
+	go c.watch()
+
+	return nil
+}
+
+// Client returns current session client
+func (c *Conn) Client() client.ImmuClient {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.client
+}
+
+// State returns current session state
+func (c *Conn) State() ConnState {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.state
+}
+
+// Ready fails unless current session is ready
+func (c *Conn) Ready(ctx context.Context) error {
+	if s := c.State(); s != StateReady {
+		return fmt.Errorf("immudb session on %s %s, error %w", c.options.Database, s, ErrConnNotReady)
+	}
+
+	return nil
+}
+
+// Close stops reopening sessions and closes current one
+func (c *Conn) Close(ctx context.Context) error {
+	c.once.Do(func() { close(c.done) })
+
+	c.mutex.Lock()
+	cl := c.client
+	c.client = nil
+	c.state = StateClosed
+	c.mutex.Unlock()
+
+	if cl == nil {
+		return nil
+	}
+	if err := cl.CloseSession(ctx); err != nil {
+		return fmt.Errorf("unable to close immudb session on %s, error %w", c.options.Database, err)
+	}
+
+	return nil
+}
+
+// watch keeps current session alive and reopens it once lost
+func (c *Conn) watch() {
+	ticker := time.NewTicker(c.keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.lost:
+		case <-ticker.C:
+			if err := c.keepAlive(); err == nil || !c.markLost(c.generation()) {
+				continue
+			}
+		}
+
+		c.reconnect()
+	}
+}
+
+// reconnect opens sessions with backoff until one succeeds or connection gets closed
+func (c *Conn) reconnect() {
+	gen := c.generation() + 1
+	backoff := c.minBackoff
+	for {
+		cl, err := c.open(context.Background(), gen)
+		if err == nil {
+			c.swap(cl, gen)
+			return
+		}
+
+		log.Printf("Unable to reopen immudb session on %s, retrying in %v, error %v", c.options.Database, backoff, err)
+		select {
+		case <-c.done:
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// swap replaces lost session client, closed connections release it instead
+func (c *Conn) swap(cl client.ImmuClient, gen uint64) {
+	c.mutex.Lock()
+	if c.state == StateClosed {
+		c.mutex.Unlock()
+		closeSession(cl)
+		return
+	}
+	old := c.client
+	c.client = cl
+	c.gen = gen
+	c.state = StateReady
+	c.mutex.Unlock()
+
+	// drop lost signals from calls on the old session
+	select {
+	case <-c.lost:
+	default:
+	}
+
+	closeSession(old)
+	log.Printf("Reopened immudb session on %s", c.options.Database)
+}
+
+// open opens a session on a new client, its calls report session losses tagged by generation
+func (c *Conn) open(ctx context.Context, gen uint64) (client.ImmuClient, error) {
+	o := *c.options
+	o.DialOptions = append(append([]grpc.DialOption{}, c.options.DialOptions...), grpc.WithChainUnaryInterceptor(c.interceptor(gen)))
+
+	cl := client.NewClient().WithOptions(&o)
+	if err := cl.OpenSession(context.Background(), []byte(o.Username), []byte(o.Password), o.Database); err != nil {
+		if cl.IsConnected() {
+			_ = cl.Disconnect()
+		}
+		return nil, fmt.Errorf("failed to OpenSession on Immudb server, error %w", err)
+	}
+
+	if _, err := cl.UseDatabase(ctx, &schema.Database{DatabaseName: o.Database}); err != nil {
+		closeSession(cl)
+		return nil, fmt.Errorf("failed to use database %s on Immudb server, error %w", o.Database, err)
+	}
+
+	return cl, nil
+}
+
+// interceptor marks generation session as lost on session loss errors
+func (c *Conn) interceptor(gen uint64) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil && isSessionLost(err) && c.markLost(gen) {
+			select {
+			case c.lost <- struct{}{}:
+			default:
+			}
+		}
+
+		return err
+	}
+}
+
+// markLost moves ready generation session to reconnecting, false if it was already reported or replaced
+func (c *Conn) markLost(gen uint64) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.gen != gen || c.state != StateReady {
+		return false
+	}
+	c.state = StateReconnecting
+	log.Printf("Lost immudb session on %s", c.options.Database)
+
+	return true
+}
+
+func (c *Conn) keepAlive() error {
+	cl := c.Client()
+	if cl == nil {
+		return ErrConnClosed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.keepAliveInterval)
+	defer cancel()
+
+	_, err := cl.GetServiceClient().KeepAlive(ctx, &empty.Empty{})
+	return err
+}
+
+func (c *Conn) generation() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.gen
+}
+
+func closeSession(cl client.ImmuClient) {
+	if cl == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeSessionTimeout)
+	defer cancel()
+	_ = cl.CloseSession(ctx)
+}
+
+// isSessionLost matches unreachable immudb and unknown session errors
+func isSessionLost(err error) bool {
+	if status.Code(err) == codes.Unavailable {
+		return true
+	}
+
+	msg := err.Error()
+	for _, m := range sessionLostMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
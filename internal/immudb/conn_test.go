@@ -0,0 +1,97 @@
+package immudb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/marcosQuesada/log-api/internal/embedded"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItReopensSessionsOnceImmudbRestarts(t *testing.T) {
+	dir := t.TempDir()
+	eo := &embedded.Options{Dir: filepath.Join(dir, "data"), Address: "127.0.0.1", Port: 0, Synced: true, AdminPassword: "fake-password"}
+	s, err := embedded.Start(eo)
+	if err != nil {
+		t.Fatalf("unexpected error starting embedded immudb, error %v", err)
+	}
+	defer func() { _ = s.Stop() }()
+	eo.Port = s.Addr().(*net.TCPAddr).Port
+
+	o := client.DefaultOptions()
+	o.Address = eo.Address
+	o.Port = eo.Port
+	o.Username = "immudb"
+	o.Password = eo.AdminPassword
+	o.Database = "defaultdb"
+	o.Dir = dir
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	conn := NewConn(o).WithKeepAlive(time.Millisecond*100).WithBackoff(time.Millisecond*50, time.Millisecond*500)
+	if err := conn.Open(ctx); err != nil {
+		t.Fatalf("unexpected error opening connection, error %v", err)
+	}
+	defer conn.Close(ctx)
+
+	r := NewConnRepository(conn)
+	if err := r.Initialize(ctx); err != nil {
+		t.Fatalf("unable to initialize repository error %v", err)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "fake_key_0", "fake_value_0", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding log line, error %v", err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unexpected error stopping embedded immudb, error %v", err)
+	}
+	<-s.Done()
+
+	if _, err := r.GetByKey(ctx, "fake_key_0"); err == nil {
+		t.Fatal("expected error reading from stopped immudb")
+	}
+	waitState(t, conn, StateReconnecting)
+	if err := conn.Ready(ctx); !errors.Is(err, ErrConnNotReady) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+
+	s, err = embedded.Start(eo)
+	if err != nil {
+		t.Fatalf("unexpected error restarting embedded immudb, error %v", err)
+	}
+	waitState(t, conn, StateReady)
+
+	l, err := r.GetByKey(ctx, "fake_key_0")
+	if err != nil {
+		t.Fatalf("unexpected error reading after restart, error %v", err)
+	}
+	if expected, got := "fake_value_0", l.Value(); expected != string(got) {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "fake_key_1", "fake_value_1", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding log line after restart, error %v", err)
+	}
+
+	if err := conn.Close(ctx); err != nil {
+		t.Fatalf("unexpected error closing connection, error %v", err)
+	}
+	if expected, got := StateClosed, conn.State(); expected != got {
+		t.Errorf("states do not match, expected %s got %s", expected, got)
+	}
+}
+
+func waitState(t *testing.T, conn *Conn, expected ConnState) {
+	deadline := time.Now().Add(time.Second * 15)
+	for conn.State() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("connection state not %s, got %s", expected, conn.State())
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+}
@@ -12,13 +12,28 @@ func initBinaryCounter() []byte {
 }
 
 func incBinaryCounter(raw []byte) []byte {
+	return addBinaryCounter(raw, 1)
+}
+
+func addBinaryCounter(raw []byte, n uint64) []byte {
 	var size = binary.BigEndian.Uint64(raw)
-	size++
+	size += n
 	var sizeValue = make([]byte, 8)
 	binary.BigEndian.PutUint64(sizeValue, size)
 	return sizeValue
 }
 
+// subBinaryCounter decrements counter by n, down to zero
+func subBinaryCounter(raw []byte, n uint64) []byte {
+	size := binary.BigEndian.Uint64(raw)
+	if n > size {
+		n = size
+	}
+	var sizeValue = make([]byte, 8)
+	binary.BigEndian.PutUint64(sizeValue, size-n)
+	return sizeValue
+}
+
 func binaryCounter(raw []byte) uint64 {
 	return binary.BigEndian.Uint64(raw)
 }
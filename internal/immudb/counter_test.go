@@ -22,6 +22,15 @@ func TestItIncrementsBinaryLogLinesCounter(t *testing.T) {
 	}
 }
 
+func TestItAddsBinaryLogLinesCounter(t *testing.T) {
+	raw := initBinaryCounter()
+	raw = addBinaryCounter(raw, 3)
+
+	if expected, got := uint64(3), binaryCounter(raw); expected != got {
+		t.Fatalf("Values do not match, expected %d got %d", expected, got)
+	}
+}
+
 func TestItCleansBinaryStringWithEmptyRunes(t *testing.T) {
 	raw := []byte{0, 102, 111, 111, 95, 120}
 
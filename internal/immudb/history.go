@@ -0,0 +1,222 @@
+package immudb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	immuerrors "github.com/codenotary/immudb/pkg/client/errors"
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// writerKeyPrefix prefixes keys recording log line writers, each log line write sets its writer key
+// on the same transaction, so writer key revisions share transaction with log line revisions
+const writerKeyPrefix = "writer:"
+
+// historiesKeysPageSize bounds keys read on each Histories GetAll request
+const historiesKeysPageSize = 500
+
+// historiesMaxScannedTxs bounds transactions scanned by Histories, keys with revisions older than them
+// get its history on its own request
+const historiesMaxScannedTxs = 1000
+
+// rawKeyValues includes transaction key values as stored, so deleted key revisions are scanned too.
+// Raw keys and values carry its immudb prefix byte
+var rawKeyValues = &schema.EntriesSpec{
+	KvEntriesSpec:  &schema.EntryTypeSpec{Action: schema.EntryTypeAction_RAW_VALUE},
+	ZEntriesSpec:   &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+	SqlEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+}
+
+// Histories returns keys revisions in keys order. Keys revision counts are read on GetAll requests, then transactions
+// are scanned backwards from the last written key collecting its revisions, so many histories do not take a History
+// request per key. Scan is bounded, keys whose first revisions are older get them from its own History request
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	ctx, end := observe(ctx, "histories")
+	defer end()
+
+	histories := map[string]*service.LogLineHistory{}
+	remaining := map[string]uint64{}
+	var initialTx uint64
+	for i := 0; i < len(keys); i += historiesKeysPageSize {
+		page := keys[i:]
+		if len(page) > historiesKeysPageSize {
+			page = page[:historiesKeysPageSize]
+		}
+		raw := make([][]byte, 0, len(page))
+		for _, k := range page {
+			raw = append(raw, []byte(k))
+		}
+
+		entries, err := r.client().GetAll(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %d keys, error %w", len(raw), err)
+		}
+		for _, e := range entries.GetEntries() {
+			key := string(e.GetKey())
+			histories[key] = &service.LogLineHistory{Key: key, Revision: make([]*service.LogLineRevision, e.GetRevision())}
+			remaining[key] = e.GetRevision()
+			if e.GetTx() > initialTx {
+				initialTx = e.GetTx()
+			}
+		}
+	}
+
+	for _, k := range keys {
+		if _, ok := histories[k]; !ok {
+			return nil, fmt.Errorf("unable to Get key %s history, error %w", k, service.ErrLogLineNotFound)
+		}
+	}
+
+	left := len(remaining)
+	for scanned := 0; initialTx > 0 && left > 0 && scanned < historiesMaxScannedTxs; scanned += txScanPageSize {
+		txs, err := r.client().TxScan(ctx, &schema.TxScanRequest{
+			InitialTx:   initialTx,
+			Limit:       txScanPageSize,
+			Desc:        true,
+			EntriesSpec: rawKeyValues,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan transactions, error %w", err)
+		}
+		if len(txs.GetTxs()) == 0 {
+			break
+		}
+
+		for _, tx := range txs.GetTxs() {
+			for _, entry := range tx.GetEntries() {
+				if len(entry.GetKey()) < 1 {
+					continue
+				}
+				key := string(entry.GetKey()[1:])
+				n := remaining[key]
+				if n == 0 {
+					continue
+				}
+
+				var value []byte
+				if len(entry.GetValue()) > 0 {
+					value = entry.GetValue()[1:]
+				}
+				histories[key].Revision[n-1] = &service.LogLineRevision{
+					Value:    value,
+					Tx:       tx.GetHeader().GetId(),
+					Revision: n,
+				}
+				remaining[key] = n - 1
+				if n == 1 {
+					left--
+				}
+			}
+			initialTx = tx.GetHeader().GetId() - 1
+		}
+	}
+
+	for key, n := range remaining {
+		if n == 0 {
+			continue
+		}
+		h, err := r.History(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		histories[key] = h
+	}
+
+	res := make([]*service.LogLineHistory, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, histories[k])
+	}
+
+	return res, nil
+}
+
+// RevisionHistory returns key revisions with its transaction timestamp and writer principal
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	ctx, end := observe(ctx, "revision_history")
+	defer end()
+
+	h, err := r.History(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	writers, err := r.writers(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	committed := map[uint64]time.Time{}
+	for _, rv := range h.Revision {
+		ts, ok := committed[rv.Tx]
+		if !ok {
+			tx, err := r.client().GetServiceClient().TxById(ctx, &schema.TxRequest{Tx: rv.Tx, EntriesSpec: excludeEntries})
+			if err != nil {
+				return nil, fmt.Errorf("unable to get tx %d, error %w", rv.Tx, err)
+			}
+			ts = time.Unix(tx.GetHeader().GetTs(), 0).UTC()
+			committed[rv.Tx] = ts
+		}
+
+		rv.CommittedAt = ts
+		rv.Principal = writers[rv.Tx]
+	}
+
+	return h, nil
+}
+
+// writers returns log line writer principals by transaction, lines written without principal have no writer
+func (r *repository) writers(ctx context.Context, key string) (map[uint64]string, error) {
+	res := map[uint64]string{}
+	h, err := r.client().History(ctx, &schema.HistoryRequest{Key: writerKey(key)})
+	if isKeyNotFound(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key %s writers, error %w", key, err)
+	}
+
+	for _, e := range h.GetEntries() {
+		res[e.GetTx()] = string(e.GetValue())
+	}
+
+	return res, nil
+}
+
+// writerKeyValue returns log line writer entry from context principal, nil without principal
+func writerKeyValue(ctx context.Context, line *service.LogLine) *schema.KeyValue {
+	p, ok := principal.FromContext(ctx)
+	if !ok || p.ID == "" {
+		return nil
+	}
+
+	return &schema.KeyValue{Key: writerKey(string(line.Key())), Value: []byte(p.ID)}
+}
+
+func writerKey(key string) []byte {
+	return []byte(writerKeyPrefix + key)
+}
+
+// isWriterKey returns true on log line writer keys
+func isWriterKey(key string) bool {
+	return strings.HasPrefix(key, writerKeyPrefix)
+}
+
+func isKeyNotFound(err error) bool {
+	return isStoreError(err, store.ErrKeyNotFound)
+}
+
+// isStoreError matches immudb client errors with store errors, they only keep its message
+func isStoreError(err error, target error) bool {
+	return err != nil && immuerrors.FromError(err) != nil && errors.Is(immuerrors.FromError(err), target)
+}
+
+// isConstraintViolation matches failed write preconditions, stored keys and concurrent counter updates alike
+func isConstraintViolation(err error) bool {
+	return err != nil && immuerrors.FromError(err) != nil && immuerrors.FromError(err).Code() == immuerrors.CodIntegrityConstraintViolation
+}
@@ -0,0 +1,103 @@
+package immudb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItGetsRevisionHistoryWithWriterPrincipals(t *testing.T) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	key := "writer_test_0"
+	line := func(v string) *service.LogLine {
+		return service.NewLogLineWithBucket("fake_bucket", key, v, time.Now())
+	}
+	if err := r.Add(principal.NewContext(ctx, &principal.Principal{ID: "user-1"}), line("fake value")); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	if err := r.Add(ctx, line("fake value X")); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	if err := r.AddBatch(principal.NewContext(ctx, &principal.Principal{ID: "user-2"}), []*service.LogLine{line("fake value XX")}); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	h, err := r.RevisionHistory(ctx, key)
+	if err != nil {
+		t.Fatalf("unexpected error getting revision history, error %v", err)
+	}
+
+	if expected, got := 3, len(h.Revision); expected != got {
+		t.Fatalf("unexpected total Revisions, expected %d got %d", expected, got)
+	}
+
+	for i, expected := range []string{"user-1", "", "user-2"} {
+		if got := h.Revision[i].Principal; expected != got {
+			t.Errorf("revision %d principals do not match, expected %q got %q", i, expected, got)
+		}
+		if h.Revision[i].CommittedAt.IsZero() {
+			t.Errorf("revision %d without commit time", i)
+		}
+	}
+
+	all, err := r.GetByPrefix(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	for _, l := range all {
+		if isWriterKey(string(l.Key())) {
+			t.Errorf("writer key %s leaked into log lines", l.Key())
+		}
+	}
+}
+
+func TestItFailsGettingRevisionHistoryOfNonExistentKeys(t *testing.T) {
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := r.RevisionHistory(ctx, "non_existent_key"); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestItGetsHistoriesOfKeysWrittenBeforeScannedTransactions(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	old := service.NewSourceLogLine("histories", "fake_histories_bucket", "fake value", time.Now())
+	if err := r.Add(ctx, old); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	for i := 0; i < historiesMaxScannedTxs; i++ {
+		if _, err := cl.Set(ctx, []byte(fmt.Sprintf("fake_histories_filler_%04d", i)), []byte("fake filler")); err != nil {
+			t.Fatalf("unexpected error setting filler key, error %v", err)
+		}
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_histories_bucket", string(old.Key()), "fake value X", time.Now())); err != nil {
+		t.Fatalf("unexpected error updating line, error %v", err)
+	}
+
+	hs, err := r.Histories(ctx, []string{string(old.Key())})
+	if err != nil {
+		t.Fatalf("unexpected error getting histories, error %v", err)
+	}
+
+	if expected, got := 2, len(hs[0].Revision); expected != got {
+		t.Fatalf("revisions do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := "fake value", string(hs[0].Revision[0].Value); expected != got {
+		t.Errorf("first revision values do not match, expected %s got %s", expected, got)
+	}
+}
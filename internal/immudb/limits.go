@@ -0,0 +1,42 @@
+package immudb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// WithReadLimits bounds bucket reads, concurrency limits immudb calls in flight from bucket reads and
+// maxResults the lines a single bucket read returns, bigger buckets must be exported. Zero values leave them unbounded
+func (r *repository) WithReadLimits(concurrency, maxResults int) *repository {
+	r.reads = nil
+	if concurrency > 0 {
+		r.reads = make(chan struct{}, concurrency)
+	}
+	r.maxResults = maxResults
+	return r
+}
+
+// acquireRead waits for a free read slot, returned func releases it
+func (r *repository) acquireRead(ctx context.Context) (func(), error) {
+	if r.reads == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case r.reads <- struct{}{}:
+		return func() { <-r.reads }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("unable to acquire read slot, error %w", ctx.Err())
+	}
+}
+
+// checkResultSize refuses results bigger than max result size
+func (r *repository) checkResultSize(bucket string, size int) error {
+	if r.maxResults > 0 && size > r.maxResults {
+		return fmt.Errorf("bucket %s exceeds %d lines, error %w", bucket, r.maxResults, service.ErrResultTooLarge)
+	}
+
+	return nil
+}
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/client"
 	immuerrors "github.com/codenotary/immudb/pkg/client/errors"
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/metrics"
+	"github.com/marcosQuesada/log-api/internal/retention"
 	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/tracing"
 )
 
 // logSizeKeyPlaceHolder defines immudb key to store logLines count
@@ -18,17 +23,62 @@ var logSizeKeyPlaceHolder = []byte("log_size")
 
 var errCounterNotInitialized = errors.New("log line size counter not initialized")
 
+// errLogLineExpired is a not found log line, hidden by its bucket retention until it gets pruned
+var errLogLineExpired = fmt.Errorf("log line expired, %w", service.ErrLogLineNotFound)
+
+// txScanPageSize limits transactions read on each transaction scan
+const txScanPageSize = 100
+
+// counterRetries bounds writes retried when total log lines counter gets updated concurrently
+const counterRetries = 8
+
+// keyScanPageSize bounds keys read on each ScanKeys request
+const keyScanPageSize = 500
+
+// resolveKeyValues includes transaction key values on scans, sorted set and sql entries are left out
+var resolveKeyValues = &schema.EntriesSpec{
+	KvEntriesSpec:  &schema.EntryTypeSpec{Action: schema.EntryTypeAction_RESOLVE},
+	ZEntriesSpec:   &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+	SqlEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+}
+
 type repository struct {
-	client client.ImmuClient
+	source clientSource
+
+	retention          *retention.Policies
+	expirationMetadata bool
+
+	reads      chan struct{}
+	maxResults int
 }
 
 // NewRepository instantiates new Immudb repository
 func NewRepository(c client.ImmuClient) *repository {
-	return &repository{client: c}
+	return &repository{source: staticClient{c}}
 }
 
-// Initialize ensures total number of log lines Key initialization
+// NewConnRepository instantiates Immudb repository on conn, so it follows reopened sessions
+func NewConnRepository(c *Conn) *repository {
+	return &repository{source: c}
+}
+
+// WithRetention enables bucket retention policies, expired log lines are hidden from reads.
+// Expiration metadata makes immudb expire keys by itself on new writes
+func (r *repository) WithRetention(p *retention.Policies, expirationMetadata bool) *repository {
+	r.retention = p
+	r.expirationMetadata = expirationMetadata
+	return r
+}
+
+// Initialize ensures total number of log lines Key initialization and loads buckets retention
 func (r *repository) Initialize(ctx context.Context) error {
+	ctx, end := observe(ctx, "initialize")
+	defer end()
+
+	if err := r.loadBucketsRetention(ctx); err != nil {
+		return fmt.Errorf("unable to load buckets retention, error %w", err)
+	}
+
 	_, err := r.Count(ctx)
 	if err == nil {
 		return nil
@@ -38,7 +88,7 @@ func (r *repository) Initialize(ctx context.Context) error {
 	}
 
 	id := initBinaryCounter()
-	if _, err := r.client.Set(context.Background(), logSizeKeyPlaceHolder, id); err != nil {
+	if _, err := r.client().Set(tracing.Detach(ctx), logSizeKeyPlaceHolder, id); err != nil {
 		return fmt.Errorf("unable to initialize log lines size key %s error %w", logSizeKeyPlaceHolder, err)
 	}
 
@@ -48,7 +98,10 @@ func (r *repository) Initialize(ctx context.Context) error {
 // Add LogLine to repository, if it's a new line it will increment total Log Lines inside the transaction.
 // if key already exists it just updates its value
 func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
-	keySize, err := r.client.Get(context.Background(), logSizeKeyPlaceHolder)
+	ctx, end := observe(ctx, "add")
+	defer end()
+
+	keySize, err := r.client().Get(tracing.Detach(ctx), logSizeKeyPlaceHolder)
 	if err != nil {
 		return fmt.Errorf("unable to get log line index %w", err)
 	}
@@ -58,11 +111,8 @@ func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
 	}
 
 	sizeValue := incBinaryCounter(keySize.Value)
-	_, err = r.client.SetAll(ctx, &schema.SetRequest{
-		KVs: []*schema.KeyValue{
-			{Key: line.Key(), Value: line.Value()},
-			{Key: logSizeKeyPlaceHolder, Value: sizeValue},
-		},
+	_, err = r.client().SetAll(ctx, &schema.SetRequest{
+		KVs: append(r.lineKeyValues(ctx, line), &schema.KeyValue{Key: logSizeKeyPlaceHolder, Value: sizeValue}),
 		Preconditions: []*schema.Precondition{
 			schema.PreconditionKeyMustNotExist(line.Key()),
 			schema.PreconditionKeyNotModifiedAfterTX(logSizeKeyPlaceHolder, keySize.Tx),
@@ -70,7 +120,9 @@ func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
 	})
 
 	if err != nil && immuerrors.FromError(err) != nil && immuerrors.FromError(err).Code() == immuerrors.CodIntegrityConstraintViolation {
-		if _, err := r.client.Set(context.Background(), line.Key(), line.Value()); err != nil {
+		metrics.PreconditionConflicts.WithLabelValues("add").Inc()
+		metrics.Fallbacks.WithLabelValues("add", "update").Inc()
+		if _, err := r.client().SetAll(tracing.Detach(ctx), &schema.SetRequest{KVs: r.lineKeyValues(ctx, line)}); err != nil {
 			return fmt.Errorf("unable to Update key %s error %w", line.Key(), err)
 		}
 
@@ -80,7 +132,7 @@ func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
 	if err != nil {
 		return fmt.Errorf("unable to LogLine key %s, error %w", line.Key(), err)
 	}
-	if err := r.addZset(ctx, line.Bucket(), string(line.Key()), line.Time().UnixNano()); err != nil {
+	if err := r.addLineZset(ctx, line); err != nil {
 		return fmt.Errorf("unexpected error adding zset on key %s error %v", string(line.Key()), err)
 	}
 	return nil
@@ -89,14 +141,17 @@ func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
 // AddBatch adds a batch of logLines in a unique transaction. Applies same logic from Add LogLines, if all keys are new it will increment total log lines too
 // If any of the logLines already exists precondition will fail and to maintain consistency we will process entries one by one as Add does
 func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	ctx, end := observe(ctx, "add_batch")
+	defer end()
+
 	kv := []*schema.KeyValue{}
 	pre := []*schema.Precondition{}
 	for _, line := range lines {
-		kv = append(kv, &schema.KeyValue{Key: line.Key(), Value: line.Value()})
+		kv = append(kv, r.lineKeyValues(ctx, line)...)
 		pre = append(pre, schema.PreconditionKeyMustNotExist(line.Key()))
 	}
 
-	keySize, err := r.client.Get(context.Background(), logSizeKeyPlaceHolder)
+	keySize, err := r.client().Get(tracing.Detach(ctx), logSizeKeyPlaceHolder)
 	if err != nil {
 		return fmt.Errorf("unable to get log line index %w", err)
 	}
@@ -105,13 +160,15 @@ func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) err
 		return errors.New("unexpected error key Size is Nil")
 	}
 
-	sizeValue := incBinaryCounter(keySize.Value)
+	sizeValue := addBinaryCounter(keySize.Value, uint64(len(lines)))
 	kv = append(kv, &schema.KeyValue{Key: logSizeKeyPlaceHolder, Value: sizeValue})
 	pre = append(pre, schema.PreconditionKeyNotModifiedAfterTX(logSizeKeyPlaceHolder, keySize.Tx))
-	_, err = r.client.SetAll(ctx, &schema.SetRequest{KVs: kv, Preconditions: pre})
+	_, err = r.client().SetAll(ctx, &schema.SetRequest{KVs: kv, Preconditions: pre})
 
 	// On Batch insertion premises failure, try to store lines one by one
 	if err != nil && immuerrors.FromError(err) != nil && immuerrors.FromError(err).Code() == immuerrors.CodIntegrityConstraintViolation {
+		metrics.PreconditionConflicts.WithLabelValues("add_batch").Inc()
+		metrics.Fallbacks.WithLabelValues("add_batch", "one_by_one").Inc()
 		for _, line := range lines {
 			_ = r.Add(ctx, line)
 		}
@@ -120,7 +177,7 @@ func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) err
 	}
 
 	for _, line := range lines {
-		if err := r.addZset(ctx, line.Bucket(), string(line.Key()), line.Time().UnixNano()); err != nil {
+		if err := r.addLineZset(ctx, line); err != nil {
 			return fmt.Errorf("unexpected error adding zset on key %s error %v", string(line.Key()), err)
 		}
 	}
@@ -132,11 +189,71 @@ func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) err
 	return nil
 }
 
+// CreateBatch stores new logLines in a unique transaction incrementing total log lines, lines are never overwritten.
+// If any key is already stored none is, service.ErrLogLineExists is returned. Concurrent counter updates are retried
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	ctx, end := observe(ctx, "create_batch")
+	defer end()
+
+	kv := []*schema.KeyValue{}
+	pre := []*schema.Precondition{}
+	for _, line := range lines {
+		kv = append(kv, r.lineKeyValues(ctx, line)...)
+		pre = append(pre, schema.PreconditionKeyMustNotExist(line.Key()))
+	}
+
+	keySize, err := r.client().Get(tracing.Detach(ctx), logSizeKeyPlaceHolder)
+	if err != nil {
+		return fmt.Errorf("unable to get log line index %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		_, err = r.client().SetAll(ctx, &schema.SetRequest{
+			KVs:           append(kv, &schema.KeyValue{Key: logSizeKeyPlaceHolder, Value: addBinaryCounter(keySize.Value, uint64(len(lines)))}),
+			Preconditions: append(pre, schema.PreconditionKeyNotModifiedAfterTX(logSizeKeyPlaceHolder, keySize.Tx)),
+		})
+		if !isConstraintViolation(err) {
+			break
+		}
+		metrics.PreconditionConflicts.WithLabelValues("create_batch").Inc()
+
+		// counter untouched since it was read, so the violated precondition is a stored key
+		current, cerr := r.client().Get(tracing.Detach(ctx), logSizeKeyPlaceHolder)
+		if cerr != nil {
+			return fmt.Errorf("unable to get log line index %w", cerr)
+		}
+		if current.Tx == keySize.Tx {
+			return fmt.Errorf("unable to create %d log lines, error %w", len(lines), service.ErrLogLineExists)
+		}
+		if attempt == counterRetries {
+			return fmt.Errorf("unable to create %d log lines, log lines size kept changing, error %w", len(lines), err)
+		}
+		keySize = current
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create %d log lines, error %w", len(lines), err)
+	}
+
+	for _, line := range lines {
+		if err := r.addLineZset(ctx, line); err != nil {
+			return fmt.Errorf("unexpected error adding zset on key %s error %v", string(line.Key()), err)
+		}
+	}
+
+	return nil
+}
+
 // History returns all revisions from a key
 func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	ctx, end := observe(ctx, "history")
+	defer end()
+
 	key = cleanKey([]byte(key))
 
-	h, err := r.client.History(ctx, &schema.HistoryRequest{Key: []byte(key)})
+	h, err := r.client().History(ctx, &schema.HistoryRequest{Key: []byte(key)})
+	if isKeyNotFound(err) {
+		return nil, fmt.Errorf("unable to Get key %s history, error %w", key, service.ErrLogLineNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to Get key %s history, error %w", key, err)
 	}
@@ -159,7 +276,10 @@ func (r *repository) History(ctx context.Context, key string) (*service.LogLineH
 
 // Count returns total log lines, it's reading from total log lines key
 func (r *repository) Count(ctx context.Context) (uint64, error) {
-	raw, err := r.client.Get(ctx, logSizeKeyPlaceHolder)
+	ctx, end := observe(ctx, "count")
+	defer end()
+
+	raw, err := r.client().Get(ctx, logSizeKeyPlaceHolder)
 	if err != nil && immuerrors.FromError(err) != nil {
 		if errors.Is(immuerrors.FromError(err), store.ErrKeyNotFound) {
 			return 0, errCounterNotInitialized
@@ -174,88 +294,512 @@ func (r *repository) Count(ctx context.Context) (uint64, error) {
 
 // GetByKey returns logLine by Key
 func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
-	l, err := r.client.Get(ctx, []byte(key))
+	ctx, end := observe(ctx, "get_by_key")
+	defer end()
+
+	l, err := r.client().Get(ctx, []byte(key))
+	if err != nil && immuerrors.FromError(err) != nil && errors.Is(immuerrors.FromError(err), store.ErrKeyNotFound) {
+		return nil, fmt.Errorf("unable to get key %s error %w", key, service.ErrLogLineNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to get key %s error %v", key, err)
 	}
 
-	return service.NewLogLine(string(l.Key), string(l.Value)), nil
+	expired, err := r.expired(ctx, key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, fmt.Errorf("unable to get key %s error %w", key, errLogLineExpired)
+	}
+
+	return service.DecodeLogLine(string(l.Key), string(l.Value)), nil
 }
 
 // GetByPrefix gets logLines with prefixed key
 func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
-	all, err := r.client.Scan(ctx, &schema.ScanRequest{
+	ctx, end := observe(ctx, "get_by_prefix")
+	defer end()
+
+	all, err := r.client().Scan(ctx, &schema.ScanRequest{
 		Prefix: []byte(prefix),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to get keys by prefix, error %v", err)
 	}
 
+	expired, err := r.expiredKeys(ctx, entryKeys(all.Entries), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	logs := []*service.LogLine{}
 	for _, entry := range all.Entries {
-		logs = append(logs, service.NewLogLine(string(entry.Key), string(entry.Value)))
+		if _, ok := expired[string(entry.Key)]; ok || filterSelfSystemKey(string(entry.Key)) {
+			continue
+		}
+		logs = append(logs, service.DecodeLogLine(string(entry.Key), string(entry.Value)))
 	}
 
 	return logs, nil
 }
 
-// GetByBucket gets logLines with bucket
+// ScanKeys calls fn on every log line key by pages, system keys are skipped. Lines are found by its key,
+// so lines without bucket sorted set entries are found too
+func (r *repository) ScanKeys(ctx context.Context, fn func(key string) error) error {
+	ctx, end := observe(ctx, "scan_keys")
+	defer end()
+
+	req := &schema.ScanRequest{Limit: keyScanPageSize}
+	for {
+		page, err := r.client().Scan(ctx, req)
+		if err != nil {
+			return fmt.Errorf("unable to scan keys, error %w", err)
+		}
+
+		for _, entry := range page.GetEntries() {
+			key := string(entry.GetKey())
+			if filterSelfSystemKey(key) {
+				continue
+			}
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+
+		if len(page.GetEntries()) < int(req.Limit) {
+			return nil
+		}
+		req.SeekKey = page.GetEntries()[len(page.GetEntries())-1].GetKey()
+	}
+}
+
+// sortedSetEntries includes just sorted set entries on transaction scans
+var sortedSetEntries = &schema.EntriesSpec{
+	KvEntriesSpec:  &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+	ZEntriesSpec:   &schema.EntryTypeSpec{Action: schema.EntryTypeAction_RESOLVE},
+	SqlEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+}
+
+// ScanKeyBuckets calls fn on every key added to a bucket sorted set with its bucket, sorted set entries are read
+// from transactions by pages, so buckets without metadata are found too
+func (r *repository) ScanKeyBuckets(ctx context.Context, fn func(key, bucket string) error) error {
+	ctx, end := observe(ctx, "scan_key_buckets")
+	defer end()
+
+	req := &schema.TxScanRequest{InitialTx: 1, Limit: txScanPageSize, EntriesSpec: sortedSetEntries}
+	for {
+		txs, err := r.client().TxScan(ctx, req)
+		if err != nil {
+			return fmt.Errorf("unable to scan transactions, error %w", err)
+		}
+
+		for _, tx := range txs.GetTxs() {
+			for _, z := range tx.GetZEntries() {
+				if err := fn(string(z.GetKey()), string(z.GetSet())); err != nil {
+					return err
+				}
+			}
+			req.InitialTx = tx.GetHeader().GetId() + 1
+		}
+
+		if len(txs.GetTxs()) < int(req.Limit) {
+			return nil
+		}
+	}
+}
+
+// GetByBucket gets bucket logLines, sorted set entries are scanned by pages with its values resolved by immudb,
+// so a bucket takes a read per page instead of one per line
 func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
-	all, err := r.client.ZScan(ctx, &schema.ZScanRequest{Set: []byte(bucket)})
+	ctx, end := observe(ctx, "get_by_bucket")
+	defer end()
+
+	logs := []*service.LogLine{}
+	err := r.scanBucket(ctx, bucket, time.Time{}, time.Time{}, func(entry *schema.ZEntry) error {
+		if err := r.checkResultSize(bucket, len(logs)+1); err != nil {
+			return err
+		}
+		logs = append(logs, service.DecodeLogLine(string(entry.GetKey()), string(entry.GetEntry().GetValue())))
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("unable to get keys by bucket, error %v", err)
+		return nil, fmt.Errorf("unable to get keys by bucket, error %w", err)
 	}
 
-	logs := []*service.LogLine{}
-	for _, entry := range all.Entries {
-		ln, err := r.client.Get(ctx, entry.GetKey())
+	return logs, nil
+}
+
+// ExportByBucket walks bucket log lines created on [from, to] by pages, zero times leave the range open.
+// Sorted set entries are resolved by immudb, so each line is handed to fn without extra reads.
+// Creation time comes from its float64 score, so it keeps microsecond precision
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	ctx, end := observe(ctx, "export_by_bucket")
+	defer end()
+
+	return r.scanBucket(ctx, bucket, from, to, func(entry *schema.ZEntry) error {
+		err := fn(exportedLogLine(bucket, entry))
 		if err != nil {
-			return nil, fmt.Errorf("unable to get keys by bucket, error %v", err)
+			return fmt.Errorf("unable to export key %s, error %w", string(entry.GetKey()), err)
+		}
+		return nil
+	})
+}
+
+// scanBucket walks non expired bucket sorted set entries with score on [from, to] by pages
+func (r *repository) scanBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*schema.ZEntry) error) error {
+	if r.retention != nil {
+		if c, ok := r.retention.Cutoff(bucket, time.Now()); ok && c.After(from) {
+			from = c
 		}
-		logs = append(logs, service.NewLogLine(string(entry.Key), string(ln.Value)))
 	}
 
-	return logs, nil
+	req := &schema.ZScanRequest{Set: []byte(bucket)}
+	if !from.IsZero() {
+		req.MinScore = &schema.Score{Score: float64(from.UnixNano())}
+	}
+	if !to.IsZero() {
+		req.MaxScore = &schema.Score{Score: float64(to.UnixNano())}
+	}
+
+	return r.zScan(ctx, req, fn)
 }
 
-// GetLastNLogLines gets logLines from last N transactions
+// zScan walks sorted set entries matching req by pages, seeking each page after the previous page last entry
+func (r *repository) zScan(ctx context.Context, req *schema.ZScanRequest, fn func(*schema.ZEntry) error) error {
+	req.Limit = zScanPageSize
+	for {
+		release, err := r.acquireRead(ctx)
+		if err != nil {
+			return err
+		}
+		page, err := r.client().ZScan(ctx, req)
+		release()
+		if err != nil {
+			return fmt.Errorf("unable to scan bucket %s, error %w", req.Set, err)
+		}
+
+		for _, entry := range page.Entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Entries) < zScanPageSize {
+			return nil
+		}
+
+		last := page.Entries[len(page.Entries)-1]
+		req.SeekKey = last.Key
+		req.SeekScore = last.Score
+		req.SeekAtTx = last.AtTx
+		req.InclusiveSeek = false
+	}
+}
+
+// GetLastNLogLines gets last N written logLines, newest first. Transactions are scanned backwards by pages
+// with its key values resolved, so lines are decoded from them without extra reads. Updated lines are returned once
+// with its last value, deleted ones are skipped
 func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
-	st, err := r.client.CurrentState(ctx)
+	ctx, end := observe(ctx, "get_last_n_log_lines")
+	defer end()
+
+	logs := []*service.LogLine{}
+	if n <= 0 {
+		return logs, nil
+	}
+
+	st, err := r.client().CurrentState(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get immudb current state, error %w", err)
 	}
 
-	txs, err := r.client.TxScan(ctx, &schema.TxScanRequest{
-		InitialTx: st.TxId,
-		Limit:     uint32(n),
-		Desc:      true,
-	})
+	now := time.Now()
+	seen := map[string]struct{}{}
+	for initialTx := st.GetTxId(); initialTx > 0 && len(logs) < n; {
+		txs, err := r.client().TxScan(ctx, &schema.TxScanRequest{
+			InitialTx:   initialTx,
+			Limit:       txScanPageSize,
+			Desc:        true,
+			EntriesSpec: resolveKeyValues,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan transactions, error %w", err)
+		}
+		if len(txs.GetTxs()) == 0 {
+			break
+		}
+
+		// page last values are checked for expiry at once
+		page := []*schema.KeyValue{}
+		for _, tx := range txs.GetTxs() {
+			for _, entry := range tx.GetKvEntries() {
+				key := string(entry.GetKey())
+				if _, ok := seen[key]; ok || filterSelfSystemKey(key) {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				if !entry.GetMetadata().GetDeleted() {
+					page = append(page, &schema.KeyValue{Key: entry.GetKey(), Value: entry.GetValue()})
+				}
+			}
+			initialTx = tx.GetHeader().GetId() - 1
+		}
+
+		keys := make([]string, 0, len(page))
+		for _, kv := range page {
+			keys = append(keys, string(kv.GetKey()))
+		}
+		expired, err := r.expiredKeys(ctx, keys, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range page {
+			if _, ok := expired[string(kv.GetKey())]; ok {
+				continue
+			}
+			logs = append(logs, service.DecodeLogLine(string(kv.GetKey()), string(kv.GetValue())))
+			if len(logs) == n {
+				return logs, nil
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+// ApplyRetention finds expired log lines from buckets with retention policy, on dry run they are just reported,
+// otherwise they get logically deleted, so they are pruned from bucket sorted sets too
+func (r *repository) ApplyRetention(ctx context.Context, dryRun bool) ([]*retention.Expired, error) {
+	ctx, end := observe(ctx, "apply_retention")
+	defer end()
+
+	if r.retention == nil {
+		return nil, nil
+	}
+
+	res := []*retention.Expired{}
+	now := time.Now()
+	for _, bucket := range r.retention.Buckets() {
+		c, _ := r.retention.Cutoff(bucket, now)
+		keys, err := r.bucketKeysBefore(ctx, bucket, c)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &retention.Expired{Bucket: bucket, Cutoff: c}
+		for _, k := range keys {
+			e.Keys = append(e.Keys, string(k))
+		}
+		res = append(res, e)
+
+		if dryRun || len(e.Keys) == 0 {
+			continue
+		}
+
+		// deletes are split by pages, immudb bounds entries per transaction
+		for i := 0; i < len(e.Keys); i += zScanPageSize {
+			end := i + zScanPageSize
+			if end > len(e.Keys) {
+				end = len(e.Keys)
+			}
+			if err := r.DeleteLogLines(ctx, e.Keys[i:end]); err != nil {
+				return nil, fmt.Errorf("unable to delete bucket %s expired keys, error %w", bucket, err)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteLogLines logically deletes log lines, pruning them from bucket sorted sets while their history remains.
+// Total log lines counter is decremented afterwards, retried on concurrent counter updates
+func (r *repository) DeleteLogLines(ctx context.Context, keys []string) error {
+	ctx, end := observe(ctx, "delete_log_lines")
+	defer end()
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	raw := [][]byte{}
+	for _, k := range keys {
+		raw = append(raw, []byte(k))
+	}
+	// deletes read its keys, concurrent commits make them fail on read conflicts
+	for attempt := 0; ; attempt++ {
+		_, err := r.client().Delete(ctx, &schema.DeleteKeysRequest{Keys: raw})
+		if isStoreError(err, store.ErrTxReadConflict) && attempt < counterRetries {
+			metrics.PreconditionConflicts.WithLabelValues("delete_log_lines").Inc()
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to delete %d keys, error %w", len(keys), err)
+		}
+		break
+	}
+
+	// counter gets updated concurrently by log line writes, decrement is retried on the counter last value
+	for attempt := 0; ; attempt++ {
+		keySize, err := r.client().Get(tracing.Detach(ctx), logSizeKeyPlaceHolder)
+		if err != nil {
+			return fmt.Errorf("unable to get log line index %w", err)
+		}
+
+		_, err = r.client().SetAll(ctx, &schema.SetRequest{
+			KVs:           []*schema.KeyValue{{Key: logSizeKeyPlaceHolder, Value: subBinaryCounter(keySize.Value, uint64(len(keys)))}},
+			Preconditions: []*schema.Precondition{schema.PreconditionKeyNotModifiedAfterTX(logSizeKeyPlaceHolder, keySize.Tx)},
+		})
+		if isConstraintViolation(err) && attempt < counterRetries {
+			metrics.PreconditionConflicts.WithLabelValues("delete_log_lines").Inc()
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to decrement log lines size, error %w", err)
+		}
+
+		return nil
+	}
+}
+
+// expired returns true when key creation time is on or before the retention cutoff of a bucket holding it
+func (r *repository) expired(ctx context.Context, key string, now time.Time) (bool, error) {
+	expired, err := r.expiredKeys(ctx, []string{key}, now)
 	if err != nil {
-		return nil, fmt.Errorf("unable to scan transactions, error %w", err)
+		return false, err
 	}
+	_, ok := expired[key]
 
-	logs := []*service.LogLine{}
-	for _, tx := range txs.GetTxs() {
-		for _, entry := range tx.Entries {
-			key := cleanKey(entry.GetKey()) // @TODO: Dirty Key Values here! WHY?
-			if filterSelfSystemKey(key) {
+	return ok, nil
+}
+
+// expiredKeys returns keys whose creation time is on or before the retention cutoff of a bucket holding them.
+// Each bucket gets scanned once, on the creation time range of keys older than its cutoff, so recent keys need
+// no reads. Keys not carrying its creation time are never expired here, ApplyRetention prunes them
+func (r *repository) expiredKeys(ctx context.Context, keys []string, now time.Time) (map[string]struct{}, error) {
+	res := map[string]struct{}{}
+	if r.retention == nil {
+		return res, nil
+	}
+
+	times := map[string]int64{}
+	for _, key := range keys {
+		if k, err := linekey.Decode(key); err == nil {
+			times[key] = k.Time.UnixNano()
+		}
+	}
+
+	for _, bucket := range r.retention.Buckets() {
+		c, ok := r.retention.Cutoff(bucket, now)
+		if !ok {
+			continue
+		}
+
+		candidates := map[string]struct{}{}
+		var from, to int64
+		for key, ts := range times {
+			if _, ok := res[key]; ok || ts > c.UnixNano() {
 				continue
 			}
+			if len(candidates) == 0 || ts < from {
+				from = ts
+			}
+			if len(candidates) == 0 || ts > to {
+				to = ts
+			}
+			candidates[key] = struct{}{}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
 
-			item, err := r.client.Get(ctx, []byte(key))
-			if err != nil {
-				return nil, fmt.Errorf("unable to get client, key %s error %v", key, err)
+		req := &schema.ZScanRequest{
+			Set:      []byte(bucket),
+			MinScore: &schema.Score{Score: float64(from)},
+			MaxScore: &schema.Score{Score: float64(to)},
+		}
+		err := r.zScan(ctx, req, func(entry *schema.ZEntry) error {
+			if _, ok := candidates[string(entry.GetKey())]; ok {
+				res[string(entry.GetKey())] = struct{}{}
 			}
-			logs = append(logs, service.NewLogLine(string(item.Key), string(item.Value)))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan bucket %s expired keys, error %w", bucket, err)
 		}
 	}
 
-	return logs, nil
+	return res, nil
+}
+
+// entryKeys returns scanned entries keys
+func entryKeys(entries []*schema.Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, string(entry.GetKey()))
+	}
+
+	return keys
+}
+
+// bucketKeysBefore returns bucket keys created on or before t, its sorted set is scanned by pages
+func (r *repository) bucketKeysBefore(ctx context.Context, bucket string, t time.Time) ([][]byte, error) {
+	keys := [][]byte{}
+	req := &schema.ZScanRequest{Set: []byte(bucket), MaxScore: &schema.Score{Score: float64(t.UnixNano())}}
+	err := r.zScan(ctx, req, func(entry *schema.ZEntry) error {
+		keys = append(keys, entry.GetKey())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan bucket %s expired keys, error %w", bucket, err)
+	}
+
+	return keys, nil
+}
+
+// keyValue builds log line immudb entry, adding expiration metadata if enabled
+func (r *repository) keyValue(line *service.LogLine) *schema.KeyValue {
+	kv := &schema.KeyValue{Key: line.Key(), Value: line.Value()}
+	if r.retention == nil || !r.expirationMetadata {
+		return kv
+	}
+
+	if at, ok := r.retention.ExpiresAt(line.Bucket(), line.Time()); ok {
+		kv.Metadata = &schema.KVMetadata{Expiration: &schema.Expiration{ExpiresAt: at.Unix()}}
+	}
+
+	return kv
+}
+
+// lineKeyValues returns log line entries, its value and its writer if request has principal
+func (r *repository) lineKeyValues(ctx context.Context, line *service.LogLine) []*schema.KeyValue {
+	kv := []*schema.KeyValue{r.keyValue(line)}
+	if w := writerKeyValue(ctx, line); w != nil {
+		kv = append(kv, w)
+	}
+
+	return kv
+}
+
+// addLineZset adds line to its bucket sorted set, lines already expired by immudb can not be referenced
+func (r *repository) addLineZset(ctx context.Context, line *service.LogLine) error {
+	// lines without bucket, as legacy lines rewritten by key migrations, have no sorted set
+	if line.Bucket() == "" {
+		return nil
+	}
+	if r.retention != nil && r.expirationMetadata {
+		if at, ok := r.retention.ExpiresAt(line.Bucket(), line.Time()); ok && !at.After(time.Now()) {
+			return nil
+		}
+	}
+
+	return r.addZset(ctx, line.Bucket(), string(line.Key()), line.Time().UnixNano())
 }
 
 func (r *repository) addZset(ctx context.Context, bucket string, key string, score int64) error {
 	log.Printf("Add Zset on Key %s bucket %s \n", key, bucket)
-	_, err := r.client.ZAdd(ctx, []byte(bucket), float64(score), []byte(key))
+	_, err := r.client().ZAdd(ctx, []byte(bucket), float64(score), []byte(key))
 	if err != nil {
 		return fmt.Errorf("unexpected error adding zset entry, error %v", err)
 	}
@@ -263,7 +807,34 @@ func (r *repository) addZset(ctx context.Context, bucket string, key string, sco
 	return nil
 }
 
-// filterSelfSystemKey returns true on our logLines counter key
+func exportedLogLine(bucket string, entry *schema.ZEntry) *service.ExportedLogLine {
+	return &service.ExportedLogLine{
+		Key:       string(entry.GetKey()),
+		Bucket:    bucket,
+		Value:     entry.GetEntry().GetValue(),
+		Tx:        entry.GetEntry().GetTx(),
+		Revision:  entry.GetEntry().GetRevision(),
+		CreatedAt: time.Unix(0, int64(entry.GetScore())).Round(time.Microsecond).UTC(),
+	}
+}
+
+// filterSelfSystemKey returns true on our logLines counter, bucket metadata and writer keys
 func filterSelfSystemKey(key string) bool {
-	return key == string(logSizeKeyPlaceHolder)
+	return key == string(logSizeKeyPlaceHolder) || isBucketKey(key) || isWriterKey(key)
+}
+
+// client returns immudb client, connection repositories get the current session one
+func (r *repository) client() client.ImmuClient {
+	return r.source.Client()
+}
+
+// observe starts operation span, its end observes operation latency too
+func observe(ctx context.Context, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "immudb.repository/"+operation)
+
+	return ctx, func() {
+		span.End()
+		metrics.ObserveRepository(operation, start)
+	}
 }
@@ -3,10 +3,14 @@ package immudb
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,7 +18,11 @@ import (
 	"github.com/codenotary/immudb/pkg/client"
 	"github.com/codenotary/immudb/pkg/server"
 	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/metrics"
+	"github.com/marcosQuesada/log-api/internal/retention"
 	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"google.golang.org/grpc"
 )
 
@@ -24,6 +32,9 @@ var (
 	immudbServer staticServer
 	options      *server.Options
 	listener     net.Listener
+
+	// rpcs counts immudb client calls, benchmarks report them by operation
+	rpcs uint64
 )
 
 type staticServer interface {
@@ -75,6 +86,26 @@ func TestItAddTwoLogLineAsNewAndIncrementsTotalLogLinesCounter(t *testing.T) {
 	}
 }
 
+func TestItCountsPreconditionConflictsAndFallbacksOnExistingKeys(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conflicts := testutil.ToFloat64(metrics.PreconditionConflicts.WithLabelValues("add_batch"))
+	fallbacks := testutil.ToFloat64(metrics.Fallbacks.WithLabelValues("add", "update"))
+
+	r := NewRepository(cl)
+	_ = r.Add(ctx, service.NewLogLine("foo_0", "fake value"))
+	_ = r.AddBatch(ctx, []*service.LogLine{service.NewLogLine("foo_0", "fake value B"), service.NewLogLine("foo_1", "fake value")})
+
+	if expected, got := conflicts+1, testutil.ToFloat64(metrics.PreconditionConflicts.WithLabelValues("add_batch")); expected != got {
+		t.Errorf("conflicts do not match, expected %v got %v", expected, got)
+	}
+	if expected, got := fallbacks+1, testutil.ToFloat64(metrics.Fallbacks.WithLabelValues("add", "update")); expected != got {
+		t.Errorf("fallbacks do not match, expected %v got %v", expected, got)
+	}
+}
+
 func TestItGetHistoryFromMultipleUpdatedLogLine(t *testing.T) {
 	defer reset()
 	r := NewRepository(cl)
@@ -187,7 +218,48 @@ func TestItGetsLastNInsertedLogLines(t *testing.T) {
 		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
 	}
 
-	// @TODO: Validate result composition
+	for i, expected := range []string{keyC, keyB, key} {
+		if got := string(all[i].Key()); expected != got {
+			t.Errorf("line %d keys do not match, expected %s got %s", i, expected, got)
+		}
+	}
+}
+
+func TestItGetsLastNLogLinesCountingLinesOnceWithItsLastValue(t *testing.T) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket("fake_bucket", "last_0", "fake value", time.Now()),
+		service.NewLogLineWithBucket("fake_bucket", "last_1", "fake value", time.Now()),
+		service.NewLogLineWithBucket("fake_bucket", "last_2", "fake value", time.Now()),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "last_0", "fake value X", time.Now())); err != nil {
+		t.Fatalf("unexpected error updating line, error %v", err)
+	}
+
+	all, err := r.GetLastNLogLines(ctx, 2)
+	if err != nil {
+		t.Fatalf("unable to get last N logs, error %v", err)
+	}
+
+	if expected, got := 2, len(all); expected != got {
+		t.Fatalf("lines do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := "last_0", string(all[0].Key()); expected != got {
+		t.Errorf("keys do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := "fake value X", string(all[0].Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if got := string(all[1].Key()); got == "last_0" {
+		t.Errorf("updated line %s returned twice", got)
+	}
 }
 
 func TestItInsertsMultipleLogLinesInBatch(t *testing.T) {
@@ -217,6 +289,31 @@ func TestItInsertsMultipleLogLinesInBatch(t *testing.T) {
 
 }
 
+func TestItIncrementsTotalLogLinesCounterWithBatchSize(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket("fake_bucket", "cnt_00", "fake value", time.Now()),
+		service.NewLogLineWithBucket("fake_bucket", "cnt_01", "fake value 0", time.Now().Add(time.Nanosecond)),
+		service.NewLogLineWithBucket("fake_bucket", "cnt_02", "fake value 1", time.Now().Add(time.Nanosecond*2)),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	v, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unable to get repository size, error %v", err)
+	}
+
+	if expected, got := uint64(len(lines)), v; expected != got {
+		t.Fatalf("values do not match, expected %d got %d", expected, got)
+	}
+}
+
 func TestItInsertsOnZsetOnBatchAdditionDevelopmentTest(t *testing.T) { // @TODO: consolidate on next iteration
 	defer reset()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -242,6 +339,517 @@ func TestItInsertsOnZsetOnBatchAdditionDevelopmentTest(t *testing.T) { // @TODO:
 	}
 }
 
+func TestItHidesExpiredBucketLogLinesFromReads(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bucket := "fake_retention_bucket"
+	p := retention.NewPolicies()
+	p.Set(bucket, time.Hour)
+	r := NewRepository(cl).WithRetention(p, false)
+	expired := service.NewSourceLogLine("ret", bucket, "fake expired value", time.Now().Add(-time.Hour*2))
+	lines := []*service.LogLine{
+		expired,
+		service.NewSourceLogLine("ret", bucket, "fake value", time.Now()),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	res, err := r.GetByBucket(ctx, bucket)
+	if err != nil {
+		t.Fatalf("unexpected error getting entries by bucket, error %v", err)
+	}
+
+	if expected, got := 1, len(res); expected != got {
+		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
+	}
+
+	all, err := r.GetByPrefix(ctx, linekey.SourcePrefix("ret"))
+	if err != nil {
+		t.Fatalf("unexpected error getting entries by prefix, error %v", err)
+	}
+
+	if expected, got := 1, len(all); expected != got {
+		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
+	}
+
+	_, err = r.GetByKey(ctx, string(expired.Key()))
+	if !errors.Is(err, errLogLineExpired) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+	if !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("expected expired log line not found, got %v", err)
+	}
+}
+
+func TestItReportsExpiredLogLinesOnDryRunAndPrunesThemOnApply(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bucket := "fake_prune_bucket"
+	p := retention.NewPolicies()
+	p.Set(bucket, time.Hour)
+	r := NewRepository(cl).WithRetention(p, false)
+	expired := service.NewSourceLogLine("prune", bucket, "fake expired value", time.Now().Add(-time.Hour*2))
+	if err := r.Add(ctx, expired); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	res, err := r.ApplyRetention(ctx, true)
+	if err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+
+	if expected, got := 1, len(res[0].Keys); expected != got {
+		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
+	}
+
+	if _, err := r.ApplyRetention(ctx, false); err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+
+	total, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting, error %v", err)
+	}
+
+	if expected, got := uint64(0), total; expected != got {
+		t.Errorf("total log lines do not match after pruning, expected %d got %d", expected, got)
+	}
+
+	res, err = r.ApplyRetention(ctx, true)
+	if err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+
+	if expected, got := 0, len(res[0].Keys); expected != got {
+		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
+	}
+
+	h, err := r.History(ctx, string(expired.Key()))
+	if err != nil {
+		t.Fatalf("unexpected error getting history, error %v", err)
+	}
+
+	if expected, got := 2, len(h.Revision); expected != got {
+		t.Errorf("pruned line history expected to remain, expected %d revisions got %d", expected, got)
+	}
+}
+
+func TestItAppliesRetentionOnBucketsOverScanResultsLimit(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bucket := "fake_large_prune_bucket"
+	p := retention.NewPolicies()
+	p.Set(bucket, time.Hour)
+	r := NewRepository(cl).WithRetention(p, false)
+
+	created := time.Now().Add(-time.Hour * 2)
+	for b := 0; b < 4; b++ {
+		lines := []*service.LogLine{}
+		for i := 0; i < 300; i++ {
+			lines = append(lines, service.NewSourceLogLine("large", bucket, "fake expired value", created.Add(time.Duration(b*300+i)*time.Microsecond)))
+		}
+		if err := r.AddBatch(ctx, lines); err != nil {
+			t.Fatalf("unexpected error adding batch, error %v", err)
+		}
+	}
+
+	all, err := r.GetLastNLogLines(ctx, 10)
+	if err != nil {
+		t.Fatalf("unexpected error getting last log lines, error %v", err)
+	}
+	for _, l := range all {
+		if strings.HasPrefix(string(l.Key()), linekey.SourcePrefix("large")) {
+			t.Errorf("unexpected expired line %s", l.Key())
+		}
+	}
+
+	res, err := r.ApplyRetention(ctx, true)
+	if err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+	if expected, got := 1200, len(res[0].Keys); expected != got {
+		t.Fatalf("expired keys do not match, expected %d got %d", expected, got)
+	}
+
+	if _, err := r.ApplyRetention(ctx, false); err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+	res, err = r.ApplyRetention(ctx, true)
+	if err != nil {
+		t.Fatalf("unexpected error applying retention, error %v", err)
+	}
+	if expected, got := 0, len(res[0].Keys); expected != got {
+		t.Errorf("expired keys left do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItScansLogLineKeysSkippingSystemKeys(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	if err := r.CreateBucket(ctx, &service.Bucket{Name: "fake_scan_bucket"}); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_scan_bucket", "scan_1659469226165084420", "fake value", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	keys := []string{}
+	if err := r.ScanKeys(ctx, func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error scanning keys, error %v", err)
+	}
+
+	if expected, got := []string{"scan_1659469226165084420"}, keys; len(expected) != len(got) || expected[0] != got[0] {
+		t.Errorf("keys do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItScansKeyBucketsOnBucketsWithoutMetadata(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_unlisted_bucket", "unlisted_1659469226165084420", "fake value", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	buckets := map[string]string{}
+	if err := r.ScanKeyBuckets(ctx, func(key, bucket string) error {
+		buckets[key] = bucket
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error scanning key buckets, error %v", err)
+	}
+
+	if expected, got := "fake_unlisted_bucket", buckets["unlisted_1659469226165084420"]; expected != got {
+		t.Errorf("buckets do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItDeletesLogLinesDecrementingTotalLogLinesCounter(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	bucket := "fake_delete_bucket"
+	if err := r.AddBatch(ctx, []*service.LogLine{
+		service.NewLogLineWithBucket(bucket, "del_00", "fake value", time.Now()),
+		service.NewLogLineWithBucket(bucket, "del_01", "fake value", time.Now().Add(time.Nanosecond)),
+	}); err != nil {
+		t.Fatalf("unexpected error adding lines, error %v", err)
+	}
+
+	before, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting, error %v", err)
+	}
+
+	if err := r.DeleteLogLines(ctx, []string{"del_00"}); err != nil {
+		t.Fatalf("unexpected error deleting lines, error %v", err)
+	}
+
+	after, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting, error %v", err)
+	}
+	if expected, got := before-1, after; expected != got {
+		t.Errorf("total log lines do not match, expected %d got %d", expected, got)
+	}
+
+	if _, err := r.GetByKey(ctx, "del_00"); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting deleted key, got %v", err)
+	}
+
+	all, err := r.GetByBucket(ctx, bucket)
+	if err != nil {
+		t.Fatalf("unexpected error getting bucket, error %v", err)
+	}
+	if expected, got := 1, len(all); expected != got {
+		t.Errorf("bucket lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItKeepsTotalLogLinesCounterOnConcurrentDeletesAndCreations(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	bucket := "fake_concurrent_delete_bucket"
+	stored := []*service.LogLine{}
+	for i := 0; i < 4; i++ {
+		stored = append(stored, service.NewLogLineWithBucket(bucket, fmt.Sprintf("cdel_%02d", i), "fake value", time.Now()))
+	}
+	if err := r.CreateBatch(ctx, stored); err != nil {
+		t.Fatalf("unexpected error creating lines, error %v", err)
+	}
+
+	before, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting, error %v", err)
+	}
+
+	errs := make(chan error, 2*len(stored))
+	var wg sync.WaitGroup
+	for i := range stored {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- r.DeleteLogLines(ctx, []string{fmt.Sprintf("cdel_%02d", i)})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			errs <- r.CreateBatch(ctx, []*service.LogLine{service.NewLogLineWithBucket(bucket, fmt.Sprintf("cnew_%02d", i), "fake value", time.Now())})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error on concurrent writes, error %v", err)
+		}
+	}
+
+	after, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error counting, error %v", err)
+	}
+	if expected, got := before, after; expected != got {
+		t.Errorf("total log lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItExpiresLogLinesWithImmudbExpirationMetadata(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bucket := "fake_expiration_bucket"
+	p := retention.NewPolicies()
+	p.Set(bucket, time.Hour)
+	r := NewRepository(cl).WithRetention(p, true)
+	expired := service.NewSourceLogLine("exp", bucket, "fake expired value", time.Now().Add(-time.Hour*2))
+	if err := r.Add(ctx, expired); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+
+	if _, err := NewRepository(cl).GetByKey(ctx, string(expired.Key())); err == nil {
+		t.Error("expected expired key error")
+	}
+}
+
+func TestItExportsBucketLogLinesOnTimeRange(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := NewRepository(cl)
+	bucket := "fake_export_bucket"
+	now := time.Now()
+	lines := []*service.LogLine{
+		service.NewLogLineWithBucket(bucket, "export_00", "fake old value", now.Add(-time.Hour*2)),
+		service.NewLogLineWithBucket(bucket, "export_01", "fake value", now.Add(-time.Hour)),
+		service.NewLogLineWithBucket(bucket, "export_02", "fake future value", now.Add(time.Hour)),
+	}
+	if err := r.AddBatch(ctx, lines); err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	res := []*service.ExportedLogLine{}
+	err := r.ExportByBucket(ctx, bucket, now.Add(-time.Minute*90), now, func(e *service.ExportedLogLine) error {
+		res = append(res, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error exporting bucket, error %v", err)
+	}
+
+	if expected, got := 1, len(res); expected != got {
+		t.Fatalf("expectation does not match, expected %d got %d", expected, got)
+	}
+
+	if expected, got := "fake value", string(res[0].Value); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if res[0].Tx == 0 || res[0].Revision == 0 {
+		t.Errorf("expected tx and revision, got tx %d revision %d", res[0].Tx, res[0].Revision)
+	}
+
+	if d := res[0].CreatedAt.Sub(lines[1].Time()); d > time.Microsecond || d < -time.Microsecond {
+		t.Errorf("creation times do not match, expected %s got %s", lines[1].Time(), res[0].CreatedAt)
+	}
+}
+
+func TestItRefusesBucketReadsExceedingMaxResultSize(t *testing.T) {
+	defer reset()
+	r := NewRepository(cl).WithReadLimits(2, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	bucket := "fake_limited_bucket"
+	for i := 0; i < 3; i++ {
+		if err := r.Add(ctx, service.NewLogLineWithBucket(bucket, fmt.Sprintf("limited_%d", i), "fake value", time.Now())); err != nil {
+			t.Fatalf("unexpected error adding line, error %v", err)
+		}
+	}
+
+	if _, err := r.GetByBucket(ctx, bucket); !errors.Is(err, service.ErrResultTooLarge) {
+		t.Errorf("unexpected error getting bucket, got %v", err)
+	}
+	if _, err := r.GetByBucketAt(ctx, bucket, 1<<62); !errors.Is(err, service.ErrResultTooLarge) {
+		t.Errorf("unexpected error getting bucket as of tx, got %v", err)
+	}
+
+	ll, err := r.WithReadLimits(2, 3).GetByBucket(ctx, bucket)
+	if err != nil {
+		t.Fatalf("unexpected error getting bucket, error %v", err)
+	}
+	if expected, got := 3, len(ll); expected != got {
+		t.Errorf("lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func BenchmarkGetLastNLogLines(b *testing.B) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bench_bucket", fmt.Sprintf("bench_%03d", i), "fake value", time.Now())); err != nil {
+			b.Fatalf("unexpected error adding line, error %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	start := atomic.LoadUint64(&rpcs)
+	for i := 0; i < b.N; i++ {
+		if _, err := r.GetLastNLogLines(ctx, 50); err != nil {
+			b.Fatalf("unexpected error getting last lines, error %v", err)
+		}
+	}
+	reportRPCs(b, start)
+}
+
+func BenchmarkGetLastNLogLinesHistories(b *testing.B) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx := context.Background()
+
+	for _, v := range []string{"fake value", "fake value X"} {
+		for i := 0; i < 100; i++ {
+			if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bench_bucket", fmt.Sprintf("bench_%03d", i), v, time.Now())); err != nil {
+				b.Fatalf("unexpected error adding line, error %v", err)
+			}
+		}
+	}
+
+	b.ResetTimer()
+	start := atomic.LoadUint64(&rpcs)
+	for i := 0; i < b.N; i++ {
+		ll, err := r.GetLastNLogLines(ctx, 50)
+		if err != nil {
+			b.Fatalf("unexpected error getting last lines, error %v", err)
+		}
+		keys := make([]string, 0, len(ll))
+		for _, l := range ll {
+			keys = append(keys, string(l.Key()))
+		}
+
+		hs, err := r.Histories(ctx, keys)
+		if err != nil {
+			b.Fatalf("unexpected error getting histories, error %v", err)
+		}
+		if expected, got := len(keys), len(hs); expected != got {
+			b.Fatalf("histories do not match, expected %d got %d", expected, got)
+		}
+	}
+	reportRPCs(b, start)
+}
+
+func BenchmarkGetByBucket(b *testing.B) {
+	defer reset()
+	r := NewRepository(cl)
+	ctx := context.Background()
+	// benchmark runs once per b.N, each one on its own bucket
+	bucket := fmt.Sprintf("fake_bench_bucket_%d", b.N)
+	seedBucket(b, r, bucket, 2500)
+
+	b.ResetTimer()
+	start := atomic.LoadUint64(&rpcs)
+	for i := 0; i < b.N; i++ {
+		ll, err := r.GetByBucket(ctx, bucket)
+		if err != nil {
+			b.Fatalf("unexpected error getting bucket lines, error %v", err)
+		}
+		if expected, got := 2500, len(ll); expected != got {
+			b.Fatalf("lines do not match, expected %d got %d", expected, got)
+		}
+	}
+	reportRPCs(b, start)
+}
+
+func BenchmarkGetByBucketAt(b *testing.B) {
+	defer reset()
+	r := NewRepository(cl).WithReadLimits(8, 0)
+	ctx := context.Background()
+	bucket := fmt.Sprintf("fake_bench_asof_bucket_%d", b.N)
+	seedBucket(b, r, bucket, 500)
+
+	st, err := r.State(ctx)
+	if err != nil {
+		b.Fatalf("unexpected error getting state, error %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := r.Add(ctx, service.NewLogLineWithBucket(bucket, fmt.Sprintf("bench_%05d", i), "fake value X", time.Now())); err != nil {
+			b.Fatalf("unexpected error updating line, error %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	start := atomic.LoadUint64(&rpcs)
+	for i := 0; i < b.N; i++ {
+		ll, err := r.GetByBucketAt(ctx, bucket, st.GetTxId())
+		if err != nil {
+			b.Fatalf("unexpected error getting bucket lines, error %v", err)
+		}
+		if expected, got := 500, len(ll); expected != got {
+			b.Fatalf("lines do not match, expected %d got %d", expected, got)
+		}
+	}
+	reportRPCs(b, start)
+}
+
+// seedBucket adds n bucket log lines in batches
+func seedBucket(b *testing.B, r *repository, bucket string, n int) {
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < n; i += 100 {
+		lines := []*service.LogLine{}
+		for j := i; j < i+100 && j < n; j++ {
+			lines = append(lines, service.NewLogLineWithBucket(bucket, fmt.Sprintf("bench_%05d", j), "fake value", now.Add(time.Duration(j))))
+		}
+		if err := r.AddBatch(ctx, lines); err != nil {
+			b.Fatalf("unexpected error adding lines, error %v", err)
+		}
+	}
+}
+
 func setup() {
 	log.Println("SETUP")
 	options = server.DefaultOptions()
@@ -257,7 +865,7 @@ func setup() {
 	go bs.GrpcServer.Serve(listener)
 
 	opts := client.DefaultOptions().WithDialOptions(
-		[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()},
+		[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(), grpc.WithChainUnaryInterceptor(countRPCs)},
 	)
 	opts.Username = "immudb"
 	opts.Password = "immudb"
@@ -281,30 +889,46 @@ func setup() {
 }
 
 func reset() {
-	r := NewRepository(cl)
-	all, err := r.GetByPrefix(context.Background(), "")
-	if err != nil {
-		log.Fatalf("unexpected error %v", err)
-	}
-
-	keys := [][]byte{}
-	for _, line := range all {
-		keys = append(keys, line.Key())
+	// Soft delete all keys by pages, immudb limits scan results
+	req := &schema.ScanRequest{Limit: 500}
+	for {
+		all, err := cl.Scan(context.Background(), req)
+		if err != nil {
+			log.Fatalf("unexpected error %v", err)
+		}
+
+		keys := [][]byte{}
+		for _, entry := range all.Entries {
+			if !filterSelfSystemKey(string(entry.Key)) {
+				keys = append(keys, entry.Key)
+			}
+		}
+		if len(keys) > 0 {
+			_, _ = cl.Delete(context.Background(), &schema.DeleteKeysRequest{Keys: keys})
+		}
+
+		if len(all.Entries) < int(req.Limit) {
+			break
+		}
+		req.SeekKey = all.Entries[len(all.Entries)-1].Key
 	}
 
-	// Soft delete all keys
-	_, _ = cl.Delete(context.Background(), &schema.DeleteKeysRequest{
-		Keys:    keys,
-		SinceTx: 0,
-		NoWait:  false,
-	})
-
 	// Reset log line counter
 	var sizeValue = make([]byte, 8)
 	binary.BigEndian.PutUint64(sizeValue, 0)
 	_, _ = cl.Set(context.Background(), logSizeKeyPlaceHolder, sizeValue)
 }
 
+func countRPCs(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	atomic.AddUint64(&rpcs, 1)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// reportRPCs reports immudb client calls by operation since start
+func reportRPCs(b *testing.B, start uint64) {
+	b.ReportMetric(float64(atomic.LoadUint64(&rpcs)-start)/float64(b.N), "rpcs/op")
+}
+
 func shutdown() {
 	_ = listener.Close()
 	_ = immudbServer.Stop()
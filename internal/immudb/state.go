@@ -0,0 +1,45 @@
+package immudb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// excludeEntries skips transaction entries on proofs, just headers are needed
+var excludeEntries = &schema.EntriesSpec{
+	KvEntriesSpec:  &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+	ZEntriesSpec:   &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+	SqlEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_EXCLUDE},
+}
+
+// State returns immudb current database state, signed if immudb state signing is enabled
+func (r *repository) State(ctx context.Context) (*schema.ImmutableState, error) {
+	ctx, end := observe(ctx, "state")
+	defer end()
+
+	st, err := r.client().CurrentState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get immudb current state, error %w", err)
+	}
+
+	return st, nil
+}
+
+// ConsistencyProof returns the dual proof linking transaction sinceTx to transaction tx
+func (r *repository) ConsistencyProof(ctx context.Context, sinceTx, tx uint64) (*schema.DualProof, error) {
+	ctx, end := observe(ctx, "consistency_proof")
+	defer end()
+
+	vtx, err := r.client().GetServiceClient().VerifiableTxById(ctx, &schema.VerifiableTxRequest{
+		Tx:           tx,
+		ProveSinceTx: sinceTx,
+		EntriesSpec:  excludeEntries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tx %d consistency proof since tx %d, error %w", tx, sinceTx, err)
+	}
+
+	return vtx.GetDualProof(), nil
+}
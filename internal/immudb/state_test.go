@@ -0,0 +1,48 @@
+package immudb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/monitor"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItProvesStateConsistencyBetweenTransactions(t *testing.T) {
+	defer reset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	r := NewRepository(cl)
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_state_bucket", "state_00", "fake value", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	since, err := r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+	last := &monitor.Checkpoint{Database: since.GetDb(), TxID: since.GetTxId(), TxHash: since.GetTxHash()}
+
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_state_bucket", "state_01", "fake value", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	st, err := r.State(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting state, error %v", err)
+	}
+
+	proof, err := r.ConsistencyProof(ctx, since.GetTxId(), st.GetTxId())
+	if err != nil {
+		t.Fatalf("unexpected error getting consistency proof, error %v", err)
+	}
+
+	if err := monitor.VerifyConsistency(last, st, proof); err != nil {
+		t.Errorf("unexpected error verifying consistency, error %v", err)
+	}
+
+	last.TxHash = make([]byte, 32)
+	if err := monitor.VerifyConsistency(last, st, proof); err == nil {
+		t.Error("expected tampered checkpoint to be inconsistent")
+	}
+}
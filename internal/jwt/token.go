@@ -23,6 +23,7 @@ var (
 type CustomClaims struct {
 	PrincipalID string `json:"principal_id"`
 	Email       string `json:"email"`
+	TenantID    string `json:"tenant_id,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -51,6 +52,12 @@ func (s *Processor) Sign(ctx context.Context, c *CustomClaims) (string, error) {
 
 // Validate checks token signature
 func (s *Processor) Validate(c context.Context, rawToken string) error {
+	_, err := s.Parse(c, rawToken)
+	return err
+}
+
+// Parse checks token signature and returns its claims
+func (s *Processor) Parse(c context.Context, rawToken string) (*CustomClaims, error) {
 	keyFunc := func(t *jwt.Token) (interface{}, error) {
 		if t.Method.Alg() != jwtSigningAlgorithm {
 			return nil, ErrInvalidToken
@@ -61,16 +68,16 @@ func (s *Processor) Validate(c context.Context, rawToken string) error {
 	cl := &CustomClaims{}
 	token, err := jwt.ParseWithClaims(rawToken, cl, keyFunc)
 	if err != nil {
-		return fmt.Errorf("unable to parse jwt token, error %w", err)
+		return nil, fmt.Errorf("unable to parse jwt token, error %w", err)
 	}
 
 	if token == nil || !token.Valid {
-		return ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if cl == nil || cl.Issuer != issuerName {
-		return ErrInvalidJWTClaims
+		return nil, ErrInvalidJWTClaims
 	}
 
-	return nil
+	return cl, nil
 }
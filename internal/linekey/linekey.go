@@ -0,0 +1,158 @@
+package linekey
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version tags encoded keys, keys without it are legacy `source_unixnano` keys
+const Version = "v1"
+
+// separator splits encoded key fields, it sorts below any escaped source byte,
+// so keys sort by source, creation time and sequence
+const separator = ','
+
+// escape prefixes escaped source bytes by its two hex digits
+const escape = '~'
+
+const (
+	timeSize     = 16
+	sequenceSize = 8
+)
+
+var ErrInvalidKey = errors.New("invalid log line key")
+
+// Key identifies a log line by its source and creation time, Sequence tells apart lines sharing both
+type Key struct {
+	Source   string
+	Time     time.Time
+	Sequence uint32
+}
+
+// Encode returns versioned key, creation time and sequence are fixed width big endian hex,
+// so keys from the same source sort by them. Creation time sign bit is flipped, so times before epoch sort first
+func Encode(k Key) string {
+	return fmt.Sprintf("%s%016x%c%08x", SourcePrefix(k.Source), uint64(k.Time.UnixNano())^1<<63, separator, k.Sequence)
+}
+
+// SourcePrefix returns the prefix shared by all source keys and by no other source
+func SourcePrefix(source string) string {
+	return Version + string(separator) + escapeSource(source) + string(separator)
+}
+
+// Prefix returns read prefix, prefixes already in the versioned encoding are kept, anything else is a source
+// prefix, matching every source starting by it. Empty prefixes match every versioned key
+func Prefix(prefix string) string {
+	if strings.HasPrefix(prefix, Version+string(separator)) {
+		return prefix
+	}
+
+	return Version + string(separator) + escapeSource(prefix)
+}
+
+// IsLegacy returns true on keys without versioned encoding
+func IsLegacy(key string) bool {
+	return !strings.HasPrefix(key, Version+string(separator))
+}
+
+// Decode returns key source, creation time and sequence, legacy keys have no sequence
+func Decode(key string) (Key, error) {
+	if IsLegacy(key) {
+		return decodeLegacy(key)
+	}
+
+	fields := strings.Split(strings.TrimPrefix(key, Version+string(separator)), string(separator))
+	if len(fields) != 3 || len(fields[1]) != timeSize || len(fields[2]) != sequenceSize {
+		return Key{}, fmt.Errorf("key %s has no source, creation time and sequence, error %w", key, ErrInvalidKey)
+	}
+
+	source, err := unescapeSource(fields[0])
+	if err != nil {
+		return Key{}, fmt.Errorf("key %s source, error %w", key, err)
+	}
+
+	ts, err := strconv.ParseUint(fields[1], 16, 64)
+	if err != nil {
+		return Key{}, fmt.Errorf("key %s creation time, error %w", key, ErrInvalidKey)
+	}
+
+	seq, err := strconv.ParseUint(fields[2], 16, 32)
+	if err != nil {
+		return Key{}, fmt.Errorf("key %s sequence, error %w", key, ErrInvalidKey)
+	}
+
+	k := Key{
+		Source:   source,
+		Time:     time.Unix(0, int64(ts^1<<63)).UTC(),
+		Sequence: uint32(seq),
+	}
+	if Encode(k) != key {
+		return Key{}, fmt.Errorf("key %s is not canonically encoded, error %w", key, ErrInvalidKey)
+	}
+
+	return k, nil
+}
+
+// decodeLegacy splits legacy keys on its last underscore
+func decodeLegacy(key string) (Key, error) {
+	i := strings.LastIndex(key, "_")
+	if i < 1 {
+		return Key{}, fmt.Errorf("key %s without source, error %w", key, ErrInvalidKey)
+	}
+
+	ns, err := strconv.ParseInt(key[i+1:], 10, 64)
+	if err != nil {
+		return Key{}, fmt.Errorf("key %s without creation time, error %w", key, ErrInvalidKey)
+	}
+
+	return Key{Source: key[:i], Time: time.Unix(0, ns).UTC()}, nil
+}
+
+// escapeSource keeps letters, digits, dots, dashes and underscores, any other byte is escaped
+func escapeSource(source string) string {
+	var b strings.Builder
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		if isPlain(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte(escape)
+		b.WriteString(strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+
+	return b.String()
+}
+
+func unescapeSource(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isPlain(c) {
+			b.WriteByte(c)
+			continue
+		}
+		if c != escape || i+2 >= len(s) {
+			return "", ErrInvalidKey
+		}
+		raw, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", ErrInvalidKey
+		}
+		b.WriteByte(raw[0])
+		i += 2
+	}
+	if b.Len() == 0 {
+		return "", ErrInvalidKey
+	}
+
+	return b.String(), nil
+}
+
+func isPlain(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_'
+}
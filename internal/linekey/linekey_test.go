@@ -0,0 +1,101 @@
+package linekey
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItDecodesEncodedKeys(t *testing.T) {
+	for _, k := range []Key{
+		{Source: "fake-source", Time: time.Date(2022, 8, 2, 21, 48, 22, 165084420, time.UTC)},
+		{Source: "fake_source.a", Time: time.Date(2022, 8, 2, 21, 48, 22, 0, time.UTC), Sequence: 7},
+		{Source: "fake,source/~a", Time: time.Unix(0, -1).UTC(), Sequence: 1<<32 - 1},
+	} {
+		key := Encode(k)
+		got, err := Decode(key)
+		if err != nil {
+			t.Fatalf("unexpected error decoding key %s, error %v", key, err)
+		}
+		if !got.Time.Equal(k.Time) || got.Source != k.Source || got.Sequence != k.Sequence {
+			t.Errorf("keys do not match, expected %+v got %+v", k, got)
+		}
+	}
+}
+
+func TestItKeepsSourcesApart(t *testing.T) {
+	ts := time.Date(2022, 8, 2, 21, 48, 22, 0, time.UTC)
+	key := Encode(Key{Source: "app_worker", Time: ts})
+
+	if strings.HasPrefix(key, SourcePrefix("app")) {
+		t.Errorf("source app prefix %s matches app_worker key %s", SourcePrefix("app"), key)
+	}
+	if !strings.HasPrefix(key, Prefix("app_worker")) {
+		t.Errorf("source app_worker prefix %s does not match its key %s", Prefix("app_worker"), key)
+	}
+	if !strings.HasPrefix(Encode(Key{Source: "app-1", Time: ts}), Prefix("app")) {
+		t.Errorf("partial prefix %s does not match app-1 keys", Prefix("app"))
+	}
+	if expected, got := SourcePrefix("app"), Prefix(SourcePrefix("app")); expected != got {
+		t.Errorf("prefixes do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItSortsKeysBySourceCreationTimeAndSequence(t *testing.T) {
+	ts := time.Date(2022, 8, 2, 21, 48, 22, 0, time.UTC)
+	expected := []string{
+		Encode(Key{Source: "app", Time: time.Unix(0, -10)}),
+		Encode(Key{Source: "app", Time: time.Unix(9, 0)}),
+		Encode(Key{Source: "app", Time: ts}),
+		Encode(Key{Source: "app", Time: ts, Sequence: 1}),
+		Encode(Key{Source: "app", Time: ts.Add(time.Nanosecond)}),
+		Encode(Key{Source: "app-a", Time: time.Unix(0, 0)}),
+		Encode(Key{Source: "app_worker", Time: time.Unix(0, 0)}),
+	}
+
+	got := append([]string{}, expected...)
+	sort.Sort(sort.Reverse(sort.StringSlice(got)))
+	sort.Strings(got)
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Errorf("sorted keys do not match at %d, expected %s got %s", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestItDecodesLegacyKeys(t *testing.T) {
+	k, err := Decode("fake_source_1659469226165084420")
+	if err != nil {
+		t.Fatalf("unexpected error decoding legacy key %v", err)
+	}
+
+	if expected, got := "fake_source", k.Source; expected != got {
+		t.Errorf("sources do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := int64(1659469226165084420), k.Time.UnixNano(); expected != got {
+		t.Errorf("creation times do not match, expected %d got %d", expected, got)
+	}
+	if !IsLegacy("fake_source_1659469226165084420") || IsLegacy(Encode(k)) {
+		t.Error("unexpected legacy key detection")
+	}
+}
+
+func TestItRefusesInvalidKeys(t *testing.T) {
+	valid := Encode(Key{Source: "fake-source", Time: time.Unix(0, 0)})
+	for _, key := range []string{
+		"fake-source",
+		"v1,fake-source,0",
+		"v1,,8000000000000000,00000000",
+		"v1,fake~2,8000000000000000,00000000",
+		"v1,fake~61,8000000000000000,00000000",
+		strings.ToUpper(valid[:3]) + valid[3:],
+		strings.Replace(valid, "8000000000000000", "800000000000000G", 1),
+		valid + ",00000000",
+	} {
+		if _, err := Decode(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("unexpected error decoding %s, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// revision holds a log line value written on a transaction
+type revision struct {
+	value     []byte
+	tx        uint64
+	principal string
+}
+
+// line holds log line revisions, bucket and creation time come from its first write as immudb sorted sets do
+type line struct {
+	key       string
+	bucket    string
+	createdAt time.Time
+	revisions []*revision
+}
+
+// at returns line revision on transaction tx, nil if it was written later
+func (l *line) at(tx uint64) *revision {
+	for i := len(l.revisions) - 1; i >= 0; i-- {
+		if l.revisions[i].tx <= tx {
+			return l.revisions[i]
+		}
+	}
+
+	return nil
+}
+
+func (l *line) last() *revision {
+	return l.revisions[len(l.revisions)-1]
+}
+
+// repository stores log lines and buckets in memory, each write is a new transaction as it is on immudb.
+// Data is lost on restart, it is meant for tests and local development
+type repository struct {
+	mutex   sync.RWMutex
+	txs     []time.Time
+	lines   map[string]*line
+	buckets map[string]*service.Bucket
+}
+
+// NewRepository instantiates an empty in memory repository
+func NewRepository() *repository {
+	return &repository{
+		lines:   map[string]*line{},
+		buckets: map[string]*service.Bucket{},
+	}
+}
+
+// Add stores logLine as a new transaction, existing keys get a new revision
+func (r *repository) Add(ctx context.Context, l *service.LogLine) error {
+	return r.AddBatch(ctx, []*service.LogLine{l})
+}
+
+// AddBatch stores logLines on a single transaction
+func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	return r.addBatch(ctx, lines, false)
+}
+
+// CreateBatch stores new logLines on a single transaction, none is stored if any key already is
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	return r.addBatch(ctx, lines, true)
+}
+
+func (r *repository) addBatch(ctx context.Context, lines []*service.LogLine, create bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if create {
+		for _, l := range lines {
+			if _, ok := r.lines[string(l.Key())]; ok {
+				return fmt.Errorf("unable to create key %s error %w", l.Key(), service.ErrLogLineExists)
+			}
+		}
+	}
+
+	r.txs = append(r.txs, time.Now().UTC())
+	tx := uint64(len(r.txs))
+
+	var writer string
+	if p, ok := principal.FromContext(ctx); ok {
+		writer = p.ID
+	}
+
+	for _, l := range lines {
+		key := string(l.Key())
+		ln, ok := r.lines[key]
+		if !ok {
+			ln = &line{key: key, bucket: l.Bucket(), createdAt: l.Time()}
+			r.lines[key] = ln
+		}
+		ln.revisions = append(ln.revisions, &revision{value: l.Value(), tx: tx, principal: writer})
+	}
+
+	return nil
+}
+
+// History returns all revisions from a key
+func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	return r.history(key, false)
+}
+
+// Histories returns keys revisions in keys order
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	res := make([]*service.LogLineHistory, 0, len(keys))
+	for _, k := range keys {
+		h, err := r.history(k, false)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, h)
+	}
+
+	return res, nil
+}
+
+// RevisionHistory returns key revisions with its transaction time and writer principal
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	return r.history(key, true)
+}
+
+func (r *repository) history(key string, detailed bool) (*service.LogLineHistory, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ln, ok := r.lines[key]
+	if !ok {
+		return nil, fmt.Errorf("unable to get key %s history, error %w", key, service.ErrLogLineNotFound)
+	}
+
+	h := &service.LogLineHistory{Key: key}
+	for i, rv := range ln.revisions {
+		res := &service.LogLineRevision{Value: rv.value, Tx: rv.tx, Revision: uint64(i + 1)}
+		if detailed {
+			res.CommittedAt = r.txs[rv.tx-1]
+			res.Principal = rv.principal
+		}
+		h.Revision = append(h.Revision, res)
+	}
+
+	return h, nil
+}
+
+// Count returns total log lines
+func (r *repository) Count(ctx context.Context) (uint64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return uint64(len(r.lines)), nil
+}
+
+// GetByKey returns logLine by Key
+func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	return r.GetByKeyAt(ctx, key, r.lastTx())
+}
+
+// GetByPrefix gets logLines with prefixed key sorted by key
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	return r.GetByPrefixAt(ctx, prefix, r.lastTx())
+}
+
+// GetLastNLogLines gets last N written logLines, newest first
+func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	all := make([]*line, 0, len(r.lines))
+	for _, ln := range r.lines {
+		all = append(all, ln)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].last().tx != all[j].last().tx {
+			return all[i].last().tx > all[j].last().tx
+		}
+		return all[i].key < all[j].key
+	})
+
+	logs := []*service.LogLine{}
+	for i := 0; i < n && i < len(all); i++ {
+		logs = append(logs, service.DecodeLogLine(all[i].key, string(all[i].last().value)))
+	}
+
+	return logs, nil
+}
+
+// GetByBucket gets bucket logLines sorted by creation time
+func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
+	return r.GetByBucketAt(ctx, bucket, r.lastTx())
+}
+
+// TxAt returns last transaction committed at or before t, zero if no transaction was committed by then
+func (r *repository) TxAt(ctx context.Context, t time.Time) (uint64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return uint64(sort.Search(len(r.txs), func(i int) bool { return r.txs[i].After(t) })), nil
+}
+
+// GetByKeyAt returns logLine value as it was on transaction tx
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if ln, ok := r.lines[key]; ok {
+		if rv := ln.at(tx); rv != nil {
+			return service.DecodeLogLine(key, string(rv.value)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to get key %s on tx %d, error %w", key, tx, service.ErrLogLineNotFound)
+}
+
+// GetByPrefixAt gets prefixed logLines as they were on transaction tx sorted by key
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := []string{}
+	for k := range r.lines {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	logs := []*service.LogLine{}
+	for _, k := range keys {
+		if rv := r.lines[k].at(tx); rv != nil {
+			logs = append(logs, service.DecodeLogLine(k, string(rv.value)))
+		}
+	}
+
+	return logs, nil
+}
+
+// GetByBucketAt gets bucket logLines as they were on transaction tx sorted by creation time
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	logs := []*service.LogLine{}
+	for _, ln := range r.bucketLines(bucket, time.Time{}, time.Time{}) {
+		if rv := ln.at(tx); rv != nil {
+			logs = append(logs, service.DecodeLogLine(ln.key, string(rv.value)))
+		}
+	}
+
+	return logs, nil
+}
+
+// ExportByBucket walks bucket log lines created on [from, to], zero times leave the range open
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	r.mutex.RLock()
+	lines := r.bucketLines(bucket, from, to)
+	res := make([]*service.ExportedLogLine, 0, len(lines))
+	for _, ln := range lines {
+		res = append(res, &service.ExportedLogLine{
+			Key:       ln.key,
+			Bucket:    bucket,
+			Value:     ln.last().value,
+			Tx:        ln.last().tx,
+			Revision:  uint64(len(ln.revisions)),
+			CreatedAt: ln.createdAt.Round(time.Microsecond).UTC(),
+		})
+	}
+	r.mutex.RUnlock()
+
+	for _, l := range res {
+		if err := fn(l); err != nil {
+			return fmt.Errorf("unable to export key %s, error %w", l.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateBucket stores bucket metadata, fails if it already exists
+func (r *repository) CreateBucket(ctx context.Context, b *service.Bucket) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.buckets[b.Name]; ok {
+		return fmt.Errorf("unable to create bucket %s, error %w", b.Name, service.ErrBucketAlreadyExists)
+	}
+	cp := *b
+	r.buckets[b.Name] = &cp
+
+	return nil
+}
+
+// UpdateBucket stores bucket metadata
+func (r *repository) UpdateBucket(ctx context.Context, b *service.Bucket) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cp := *b
+	r.buckets[b.Name] = &cp
+
+	return nil
+}
+
+// GetBucket returns bucket metadata
+func (r *repository) GetBucket(ctx context.Context, name string) (*service.Bucket, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	b, ok := r.buckets[name]
+	if !ok {
+		return nil, fmt.Errorf("unable to get bucket %s, error %w", name, service.ErrBucketNotFound)
+	}
+	cp := *b
+
+	return &cp, nil
+}
+
+// ListBuckets returns all buckets metadata sorted by name
+func (r *repository) ListBuckets(ctx context.Context) ([]*service.Bucket, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	res := []*service.Bucket{}
+	for _, b := range r.buckets {
+		cp := *b
+		res = append(res, &cp)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// CountBucketLines returns total bucket log lines
+func (r *repository) CountBucketLines(ctx context.Context, name string) (uint64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return uint64(len(r.bucketLines(name, time.Time{}, time.Time{}))), nil
+}
+
+// bucketLines returns bucket lines created on [from, to] sorted by creation time, callers hold the lock
+func (r *repository) bucketLines(bucket string, from, to time.Time) []*line {
+	res := []*line{}
+	for _, ln := range r.lines {
+		if ln.bucket != bucket || (!from.IsZero() && ln.createdAt.Before(from)) || (!to.IsZero() && ln.createdAt.After(to)) {
+			continue
+		}
+		res = append(res, ln)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].createdAt.Equal(res[j].createdAt) {
+			return res[i].createdAt.Before(res[j].createdAt)
+		}
+		return res[i].key < res[j].key
+	})
+
+	return res
+}
+
+func (r *repository) lastTx() uint64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return uint64(len(r.txs))
+}
@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/marcosQuesada/log-api/internal/repotest"
+)
+
+func TestItPassesRepositoryConformance(t *testing.T) {
+	repotest.Run(t, NewRepository())
+}
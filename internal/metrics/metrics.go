@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "log_api"
+
+var (
+	// RPCRequests counts served gRPC requests by method and status code
+	RPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Served gRPC requests by method and status code.",
+	}, []string{"method", "code"})
+
+	// RPCDuration observes gRPC requests latency by method, streams are observed until they end
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC requests latency by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// RepositoryDuration observes immudb repository operations latency
+	RepositoryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "operation_duration_seconds",
+		Help:      "Immudb repository operations latency by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// PreconditionConflicts counts immudb writes refused by its preconditions, as existing keys or concurrent counter updates
+	PreconditionConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "precondition_conflicts_total",
+		Help:      "Immudb writes refused by preconditions by operation.",
+	}, []string{"operation"})
+
+	// Fallbacks counts writes retried another way once refused, updates on Add and one by one writes on AddBatch
+	Fallbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "fallbacks_total",
+		Help:      "Immudb writes retried another way by operation and fallback.",
+	}, []string{"operation", "fallback"})
+
+	// IngestedLines counts stored log lines by tenant and bucket, requests without tenant have an empty tenant
+	IngestedLines = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingest",
+		Name:      "lines_total",
+		Help:      "Ingested log lines by tenant and bucket.",
+	}, []string{"tenant", "bucket"})
+
+	// IngestedBytes counts stored log line value bytes by tenant and bucket
+	IngestedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingest",
+		Name:      "bytes_total",
+		Help:      "Ingested log line value bytes by tenant and bucket.",
+	}, []string{"tenant", "bucket"})
+
+	// QuotaRejections counts requests refused by rate limits and quotas by subject kind and reason
+	QuotaRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "quota",
+		Name:      "rejections_total",
+		Help:      "Requests refused by rate limits and quotas by subject kind and reason.",
+	}, []string{"kind", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(RPCRequests, RPCDuration, RepositoryDuration, PreconditionConflicts, Fallbacks, IngestedLines, IngestedBytes, QuotaRejections)
+}
+
+// ObserveRepository observes operation latency since start, it is meant to be deferred
+func ObserveRepository(operation string, start time.Time) {
+	RepositoryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// Ingested counts a stored log line with its value size on its tenant bucket
+func Ingested(tenant, bucket string, size int) {
+	IngestedLines.WithLabelValues(tenant, bucket).Inc()
+	IngestedBytes.WithLabelValues(tenant, bucket).Add(float64(size))
+}
+
+// UnaryInterceptor counts and observes unary requests, it goes first so refused requests are counted too
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	observeRPC(info.FullMethod, start, err)
+
+	return res, err
+}
+
+// StreamInterceptor counts and observes streams
+func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observeRPC(info.FullMethod, start, err)
+
+	return err
+}
+
+// Handler serves /metrics, it is meant for its own listener, so metrics are not exposed with the API
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return mux
+}
+
+func observeRPC(method string, start time.Time, err error) {
+	RPCRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	RPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItCountsRequestsByMethodAndCode(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unauthenticated, "fake failure")
+	}
+
+	_, _ = UnaryInterceptor(context.Background(), nil, info, fail)
+	_, _ = UnaryInterceptor(context.Background(), nil, info, fail)
+
+	if expected, got := float64(2), testutil.ToFloat64(RPCRequests.WithLabelValues(info.FullMethod, codes.Unauthenticated.String())); expected != got {
+		t.Errorf("request counts do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItServesMetricsByTenantOnlyOnMetricsPath(t *testing.T) {
+	Ingested("acme", "fake_bucket", 10)
+	srv := Handler()
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `log_api_ingest_bytes_total{bucket="fake_bucket",tenant="acme"} 10`) {
+		t.Errorf("expected ingested bytes on metrics, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/logs", nil))
+	if expected, got := http.StatusNotFound, rec.Code; expected != got {
+		t.Errorf("status codes do not match, expected %d got %d", expected, got)
+	}
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const defaultWebhookTimeout = time.Second * 10
+
+// Alert describes an inconsistency found by the monitor
+type Alert struct {
+	Database         string    `json:"database"`
+	TxID             uint64    `json:"tx_id"`
+	TxHash           []byte    `json:"tx_hash"`
+	Reason           string    `json:"reason"`
+	CheckedAt        time.Time `json:"checked_at"`
+	CheckpointTxID   uint64    `json:"checkpoint_tx_id,omitempty"`
+	CheckpointTxHash []byte    `json:"checkpoint_tx_hash,omitempty"`
+}
+
+// Alerter delivers monitor alerts
+type Alerter interface {
+	Alert(ctx context.Context, a *Alert) error
+}
+
+type webhookAlerter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAlerter instantiates an alerter posting alerts as json to url
+func NewWebhookAlerter(url string) Alerter {
+	return &webhookAlerter{
+		url:    url,
+		client: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+}
+
+// Alert posts alert to webhook, any non 2xx response is an error
+func (w *webhookAlerter) Alert(ctx context.Context, a *Alert) error {
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("unable to marshal alert, error %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("unable to build alert request, error %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to post alert, error %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("alert webhook responded status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+var (
+	ErrInvalidSigningKey     = errors.New("checkpoint signing key must be a 32 bytes ed25519 seed")
+	ErrCorruptedCheckpoints  = errors.New("corrupted checkpoints file")
+	ErrCheckpointNotFound    = errors.New("checkpoint not found")
+	errCheckpointsNotChained = errors.New("checkpoint does not chain to its previous one")
+)
+
+// Checkpoint records an immudb state verified by the monitor. Checkpoints are signed by the monitor,
+// each one chaining to its previous checkpoint signature, so the file can not be rewritten without the signing key
+type Checkpoint struct {
+	Database        string    `json:"database"`
+	TxID            uint64    `json:"tx_id"`
+	TxHash          []byte    `json:"tx_hash"`
+	ServerSignature []byte    `json:"server_signature,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+	Previous        []byte    `json:"previous,omitempty"`
+	Signature       []byte    `json:"signature"`
+}
+
+// payload returns checkpoint signed content, everything but its signature
+func (c *Checkpoint) payload() []byte {
+	cp := *c
+	cp.Signature = nil
+	raw, _ := json.Marshal(&cp)
+	return raw
+}
+
+// Checkpoints appends signed checkpoints to a local file
+type Checkpoints struct {
+	path string
+	key  ed25519.PrivateKey
+
+	mutex sync.Mutex
+	last  *Checkpoint
+	byDB  map[string]*Checkpoint
+}
+
+// OpenCheckpoints loads checkpoints file verifying its signatures and chain, signing key is a base64 decoded ed25519 seed
+func OpenCheckpoints(path string, seed []byte) (*Checkpoints, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidSigningKey
+	}
+
+	c := &Checkpoints{
+		path: path,
+		key:  ed25519.NewKeyFromSeed(seed),
+		byDB: map[string]*Checkpoint{},
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open checkpoints file %s, error %w", path, err)
+	}
+	defer f.Close()
+
+	pub := c.key.Public().(ed25519.PublicKey)
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		cp := &Checkpoint{}
+		if err := json.Unmarshal(s.Bytes(), cp); err != nil {
+			return nil, fmt.Errorf("unable to decode checkpoint %d, error %v, %w", n, err, ErrCorruptedCheckpoints)
+		}
+		if !ed25519.Verify(pub, cp.payload(), cp.Signature) {
+			return nil, fmt.Errorf("checkpoint %d signature does not verify, error %w", n, ErrCorruptedCheckpoints)
+		}
+		if !bytes.Equal(cp.Previous, chainHash(c.last)) {
+			return nil, fmt.Errorf("checkpoint %d, error %v, %w", n, errCheckpointsNotChained, ErrCorruptedCheckpoints)
+		}
+
+		c.last = cp
+		c.byDB[cp.Database] = cp
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read checkpoints file %s, error %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Last returns database last checkpoint
+func (c *Checkpoints) Last(database string) (*Checkpoint, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cp, ok := c.byDB[database]
+	if !ok {
+		return nil, fmt.Errorf("database %s, error %w", database, ErrCheckpointNotFound)
+	}
+
+	return cp, nil
+}
+
+// Append signs a checkpoint from a verified state and appends it to the checkpoints file
+func (c *Checkpoints) Append(st *schema.ImmutableState, at time.Time) (*Checkpoint, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cp := &Checkpoint{
+		Database:  st.GetDb(),
+		TxID:      st.GetTxId(),
+		TxHash:    st.GetTxHash(),
+		CheckedAt: at.UTC(),
+		Previous:  chainHash(c.last),
+	}
+	if st.GetSignature() != nil {
+		cp.ServerSignature = st.GetSignature().GetSignature()
+	}
+	cp.Signature = ed25519.Sign(c.key, cp.payload())
+
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal checkpoint, error %w", err)
+	}
+
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open checkpoints file %s, error %w", c.path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		return nil, fmt.Errorf("unable to append checkpoint, error %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return nil, fmt.Errorf("unable to sync checkpoints file, error %w", err)
+	}
+
+	c.last = cp
+	c.byDB[cp.Database] = cp
+
+	return cp, nil
+}
+
+// chainHash returns the hash next checkpoint chains to, first checkpoint chains to nothing
+func chainHash(prev *Checkpoint) []byte {
+	if prev == nil {
+		return nil
+	}
+
+	h := sha256.Sum256(prev.Signature)
+	return h[:]
+}
@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+func TestItReopensSignedCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints")
+	c, err := OpenCheckpoints(path, fakeSeed())
+	if err != nil {
+		t.Fatalf("unable to open checkpoints, error %v", err)
+	}
+
+	for i := uint64(1); i <= 3; i++ {
+		if _, err := c.Append(&schema.ImmutableState{Db: "defaultdb", TxId: i, TxHash: make([]byte, 32)}, time.Now()); err != nil {
+			t.Fatalf("unable to append checkpoint, error %v", err)
+		}
+	}
+
+	c, err = OpenCheckpoints(path, fakeSeed())
+	if err != nil {
+		t.Fatalf("unable to reopen checkpoints, error %v", err)
+	}
+
+	last, err := c.Last("defaultdb")
+	if err != nil {
+		t.Fatalf("unable to get last checkpoint, error %v", err)
+	}
+
+	if expected, got := uint64(3), last.TxID; expected != got {
+		t.Errorf("last tx does not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItDetectsTamperedCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints")
+	c, err := OpenCheckpoints(path, fakeSeed())
+	if err != nil {
+		t.Fatalf("unable to open checkpoints, error %v", err)
+	}
+	if _, err := c.Append(&schema.ImmutableState{Db: "defaultdb", TxId: 12, TxHash: make([]byte, 32)}, time.Now()); err != nil {
+		t.Fatalf("unable to append checkpoint, error %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read checkpoints, error %v", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(raw), `"tx_id":12`, `"tx_id":11`, 1)), 0600); err != nil {
+		t.Fatalf("unable to write checkpoints, error %v", err)
+	}
+
+	if _, err := OpenCheckpoints(path, fakeSeed()); !errors.Is(err, ErrCorruptedCheckpoints) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestItRefusesInvalidCheckpointSigningKeys(t *testing.T) {
+	if _, err := OpenCheckpoints(filepath.Join(t.TempDir(), "checkpoints"), []byte("short")); !errors.Is(err, ErrInvalidSigningKey) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func fakeSeed() []byte {
+	return []byte("0123456789abcdef0123456789abcdef")
+}
@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+var ErrInconsistentState = errors.New("immudb state is not consistent with last checkpoint")
+
+// StateSource gives immudb current state and consistency proofs between transactions
+type StateSource interface {
+	State(ctx context.Context) (*schema.ImmutableState, error)
+	ConsistencyProof(ctx context.Context, sinceTx, tx uint64) (*schema.DualProof, error)
+}
+
+// Monitor periodically verifies immudb current state is consistent with its last checkpoint,
+// consistent states are recorded as new checkpoints, any inconsistency raises an alert
+type Monitor struct {
+	source      StateSource
+	checkpoints *Checkpoints
+	alerter     Alerter
+	pubKey      *ecdsa.PublicKey
+}
+
+// NewMonitor instantiates monitor, server signing public key is optional, if provided unsigned states are refused
+func NewMonitor(s StateSource, c *Checkpoints, a Alerter, pubKey *ecdsa.PublicKey) *Monitor {
+	return &Monitor{
+		source:      s,
+		checkpoints: c,
+		alerter:     a,
+		pubKey:      pubKey,
+	}
+}
+
+// Run checks state on each interval until context is done or an inconsistency is found
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(ctx); errors.Is(err, ErrInconsistentState) {
+			return err
+		} else if err != nil {
+			log.Printf("Unable to check immudb state, error %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check verifies current state against database last checkpoint, appending it as new checkpoint once verified.
+// Inconsistencies are alerted and returned as ErrInconsistentState, source failures are just returned
+func (m *Monitor) Check(ctx context.Context) error {
+	st, err := m.source.State(ctx)
+	if err != nil {
+		return err
+	}
+
+	last, err := m.checkpoints.Last(st.GetDb())
+	if err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+		return err
+	}
+
+	if err := m.verify(ctx, last, st); err != nil {
+		if errors.Is(err, ErrInconsistentState) {
+			m.alert(ctx, last, st, err)
+		}
+		return err
+	}
+
+	if last != nil && last.TxID == st.GetTxId() {
+		return nil
+	}
+
+	cp, err := m.checkpoints.Append(st, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Checkpoint database %s at tx %d", cp.Database, cp.TxID)
+
+	return nil
+}
+
+func (m *Monitor) verify(ctx context.Context, last *Checkpoint, st *schema.ImmutableState) error {
+	if m.pubKey != nil {
+		if st.GetSignature() == nil {
+			return fmt.Errorf("state at tx %d is not signed, error %w", st.GetTxId(), ErrInconsistentState)
+		}
+		ok, err := st.CheckSignature(m.pubKey)
+		if err != nil || !ok {
+			return fmt.Errorf("state at tx %d signature does not verify, error %w", st.GetTxId(), ErrInconsistentState)
+		}
+	}
+
+	if last == nil || last.TxID == 0 {
+		return nil
+	}
+
+	if st.GetTxId() <= last.TxID {
+		return VerifyConsistency(last, st, nil)
+	}
+
+	proof, err := m.source.ConsistencyProof(ctx, last.TxID, st.GetTxId())
+	if err != nil {
+		return err
+	}
+
+	return VerifyConsistency(last, st, proof)
+}
+
+func (m *Monitor) alert(ctx context.Context, last *Checkpoint, st *schema.ImmutableState, cause error) {
+	log.Printf("ALERT database %s, %v", st.GetDb(), cause)
+	if m.alerter == nil {
+		return
+	}
+
+	a := &Alert{
+		Database:  st.GetDb(),
+		TxID:      st.GetTxId(),
+		TxHash:    st.GetTxHash(),
+		Reason:    cause.Error(),
+		CheckedAt: time.Now().UTC(),
+	}
+	if last != nil {
+		a.CheckpointTxID = last.TxID
+		a.CheckpointTxHash = last.TxHash
+	}
+
+	if err := m.alerter.Alert(ctx, a); err != nil {
+		log.Printf("unable to deliver alert, error %v", err)
+	}
+}
+
+// VerifyConsistency checks state is a valid evolution of checkpoint, same transaction must keep its hash,
+// newer transactions must be proven consistent with it by its dual proof, older transactions mean a rollback
+func VerifyConsistency(last *Checkpoint, st *schema.ImmutableState, proof *schema.DualProof) error {
+	switch {
+	case st.GetTxId() < last.TxID:
+		return fmt.Errorf("state rolled back from tx %d to tx %d, error %w", last.TxID, st.GetTxId(), ErrInconsistentState)
+
+	case st.GetTxId() == last.TxID:
+		if !bytes.Equal(st.GetTxHash(), last.TxHash) {
+			return fmt.Errorf("tx %d hash changed, error %w", st.GetTxId(), ErrInconsistentState)
+		}
+		return nil
+	}
+
+	if proof.GetSourceTxHeader() == nil || proof.GetTargetTxHeader() == nil || proof.GetLinearProof() == nil || len(last.TxHash) != sha256.Size || len(st.GetTxHash()) != sha256.Size {
+		return fmt.Errorf("missing consistency proof from tx %d to tx %d, error %w", last.TxID, st.GetTxId(), ErrInconsistentState)
+	}
+
+	if !store.VerifyDualProof(schema.DualProofFromProto(proof), last.TxID, st.GetTxId(), schema.DigestFromProto(last.TxHash), schema.DigestFromProto(st.GetTxHash())) {
+		return fmt.Errorf("tx %d is not consistent with tx %d, error %w", st.GetTxId(), last.TxID, ErrInconsistentState)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+func TestItCheckpointsStatesOnlyWhenTheyChange(t *testing.T) {
+	c := fakeCheckpoints(t)
+	s := &fakeStateSource{state: &schema.ImmutableState{Db: "defaultdb", TxId: 1, TxHash: make([]byte, 32)}}
+	m := NewMonitor(s, c, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		if err := m.Check(context.Background()); err != nil {
+			t.Fatalf("unexpected error checking state, error %v", err)
+		}
+	}
+
+	if expected, got := 1, len(c.byDB); expected != got {
+		t.Errorf("checkpointed databases do not match, expected %d got %d", expected, got)
+	}
+	if c.last.Previous != nil {
+		t.Error("expected a single checkpoint")
+	}
+}
+
+func TestItAlertsOnRolledBackStates(t *testing.T) {
+	c := fakeCheckpoints(t)
+	if _, err := c.Append(&schema.ImmutableState{Db: "defaultdb", TxId: 5, TxHash: make([]byte, 32)}, time.Now()); err != nil {
+		t.Fatalf("unable to append checkpoint, error %v", err)
+	}
+
+	a := &fakeAlerter{}
+	s := &fakeStateSource{state: &schema.ImmutableState{Db: "defaultdb", TxId: 4, TxHash: make([]byte, 32)}}
+	m := NewMonitor(s, c, a, nil)
+
+	if err := m.Check(context.Background()); !errors.Is(err, ErrInconsistentState) {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if expected, got := 1, len(a.alerts); expected != got {
+		t.Fatalf("alerts do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := uint64(5), a.alerts[0].CheckpointTxID; expected != got {
+		t.Errorf("alert checkpoint tx does not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItAlertsOnChangedTransactionHashes(t *testing.T) {
+	c := fakeCheckpoints(t)
+	if _, err := c.Append(&schema.ImmutableState{Db: "defaultdb", TxId: 5, TxHash: make([]byte, 32)}, time.Now()); err != nil {
+		t.Fatalf("unable to append checkpoint, error %v", err)
+	}
+
+	hash := make([]byte, 32)
+	hash[0] = 1
+	a := &fakeAlerter{}
+	s := &fakeStateSource{state: &schema.ImmutableState{Db: "defaultdb", TxId: 5, TxHash: hash}}
+	m := NewMonitor(s, c, a, nil)
+
+	if err := m.Check(context.Background()); !errors.Is(err, ErrInconsistentState) {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if expected, got := 1, len(a.alerts); expected != got {
+		t.Errorf("alerts do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItAlertsOnMissingConsistencyProofs(t *testing.T) {
+	c := fakeCheckpoints(t)
+	if _, err := c.Append(&schema.ImmutableState{Db: "defaultdb", TxId: 5, TxHash: make([]byte, 32)}, time.Now()); err != nil {
+		t.Fatalf("unable to append checkpoint, error %v", err)
+	}
+
+	a := &fakeAlerter{}
+	s := &fakeStateSource{state: &schema.ImmutableState{Db: "defaultdb", TxId: 6, TxHash: make([]byte, 32)}, proof: &schema.DualProof{}}
+	m := NewMonitor(s, c, a, nil)
+
+	if err := m.Check(context.Background()); !errors.Is(err, ErrInconsistentState) {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if expected, got := 1, len(a.alerts); expected != got {
+		t.Errorf("alerts do not match, expected %d got %d", expected, got)
+	}
+}
+
+func fakeCheckpoints(t *testing.T) *Checkpoints {
+	c, err := OpenCheckpoints(filepath.Join(t.TempDir(), "checkpoints"), fakeSeed())
+	if err != nil {
+		t.Fatalf("unable to open checkpoints, error %v", err)
+	}
+
+	return c
+}
+
+type fakeStateSource struct {
+	state *schema.ImmutableState
+	proof *schema.DualProof
+}
+
+func (f *fakeStateSource) State(ctx context.Context) (*schema.ImmutableState, error) {
+	return f.state, nil
+}
+
+func (f *fakeStateSource) ConsistencyProof(ctx context.Context, sinceTx, tx uint64) (*schema.DualProof, error) {
+	return f.proof, nil
+}
+
+type fakeAlerter struct {
+	alerts []*Alert
+}
+
+func (f *fakeAlerter) Alert(ctx context.Context, a *Alert) error {
+	f.alerts = append(f.alerts, a)
+	return nil
+}
@@ -0,0 +1,25 @@
+package principal
+
+import "context"
+
+type contextKey struct{}
+
+// Principal identifies the authenticated caller
+type Principal struct {
+	ID     string
+	Email  string
+	Tenant string
+	// KeyID identifies the API key, as the issued token, the request was authenticated with
+	KeyID string
+}
+
+// NewContext returns a new context carrying principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns context principal, false if the request has not been authenticated
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok
+}
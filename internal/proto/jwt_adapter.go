@@ -5,9 +5,13 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/marcosQuesada/log-api/internal/jwt"
+	"github.com/marcosQuesada/log-api/internal/principal"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -15,30 +19,79 @@ var ErrNoAuthorizationHeader = errors.New("no authorization header")
 var ErrNoMetadataProvided = errors.New("metadata is not provided")
 
 const (
-	authHeader                = "authorization"
-	bearerCleanOut            = "Bearer "
-	unrestrictedLoginEndpoint = "/v1.AuthService/Login"
+	authHeader     = "authorization"
+	bearerCleanOut = "Bearer "
 )
 
+// unrestrictedEndpoints are served without token, as login and orchestrator health checks
+var unrestrictedEndpoints = map[string]struct{}{
+	"/v1.AuthService/Login":        {},
+	"/grpc.health.v1.Health/Check": {},
+	"/grpc.health.v1.Health/Watch": {},
+}
+
 type requestValidator interface {
-	Validate(c context.Context, rawToken string) error
+	Parse(c context.Context, rawToken string) (*jwt.CustomClaims, error)
 }
 
 type JWTAuthAdapter struct {
-	validator requestValidator
+	validator    requestValidator
+	certificates bool
 }
 
 func NewJWTAuthAdapter(v requestValidator) *JWTAuthAdapter {
 	return &JWTAuthAdapter{validator: v}
 }
 
+// WithClientCertificates authenticates requests without token from its verified client certificate subject
+func (a *JWTAuthAdapter) WithClientCertificates() *JWTAuthAdapter {
+	a.certificates = true
+	return a
+}
+
 // Interceptor defines a unary Interceptor that validates JWT tokens
 func (a *JWTAuthAdapter) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	if info.FullMethod == unrestrictedLoginEndpoint {
+	if _, ok := unrestrictedEndpoints[info.FullMethod]; ok {
 		return handler(ctx, req)
 	}
 
+	ctx, err = a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor defines a stream Interceptor that validates JWT tokens
+func (a *JWTAuthAdapter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, ok := unrestrictedEndpoints[info.FullMethod]; ok {
+		return handler(srv, ss)
+	}
+
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate validates request JWT token and injects its principal on context, requests without token,
+// or with an empty one, fall back to client certificate principal when enabled
+func (a *JWTAuthAdapter) authenticate(ctx context.Context) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
+	var tkn string
+	if len(md[authHeader]) > 0 {
+		tkn = strings.ReplaceAll(md[authHeader][0], bearerCleanOut, "")
+	}
+
+	if a.certificates && strings.TrimSpace(tkn) == "" {
+		if p, ok := certificatePrincipal(ctx); ok {
+			return principal.NewContext(ctx, p), nil
+		}
+	}
+
 	if !ok {
 		return nil, ErrNoMetadataProvided
 	}
@@ -47,12 +100,49 @@ func (a *JWTAuthAdapter) Interceptor(ctx context.Context, req interface{}, info
 		return nil, ErrNoAuthorizationHeader
 	}
 
-	tkn := md[authHeader][0]
-	tkn = strings.ReplaceAll(tkn, bearerCleanOut, "")
-
-	if err := a.validator.Validate(ctx, tkn); err != nil {
+	cl, err := a.validator.Parse(ctx, tkn)
+	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid authorization token")
 	}
 
-	return handler(ctx, req)
+	return principal.NewContext(ctx, &principal.Principal{ID: cl.PrincipalID, Email: cl.Email, Tenant: cl.TenantID, KeyID: cl.Id}), nil
+}
+
+// certificatePrincipal maps peer verified client certificate subject to a principal, its common name as ID,
+// its first email address as email and its first organization as tenant
+func certificatePrincipal(ctx context.Context) (*principal.Principal, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	cert := info.State.VerifiedChains[0][0]
+	if cert.Subject.CommonName == "" {
+		return nil, false
+	}
+
+	pr := &principal.Principal{ID: cert.Subject.CommonName}
+	if len(cert.EmailAddresses) > 0 {
+		pr.Email = cert.EmailAddresses[0]
+	}
+	if len(cert.Subject.Organization) > 0 {
+		pr.Tenant = cert.Subject.Organization[0]
+	}
+
+	return pr, true
+}
+
+// authenticatedStream overrides stream context with the authenticated one
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
 }
@@ -2,11 +2,18 @@ package proto
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"testing"
 
+	"github.com/marcosQuesada/log-api/internal/jwt"
+	"github.com/marcosQuesada/log-api/internal/principal"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 func TestItSucceedsOnAuthorizationHeaderFound(t *testing.T) {
@@ -19,6 +26,30 @@ func TestItSucceedsOnAuthorizationHeaderFound(t *testing.T) {
 	}
 }
 
+func TestItAttachesTokenPrincipalToRequestContext(t *testing.T) {
+	v := &fakeRequestValidator{}
+	a := NewJWTAuthAdapter(v)
+
+	var p *principal.Principal
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		p, _ = principal.FromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"authorization": []string{"Bearer fake_jwt_token"}})
+	if _, err := a.Interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}, h); err != nil {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+
+	if p == nil {
+		t.Fatal("expected principal on request context")
+	}
+
+	if expected, got := "fake_principal", p.ID; expected != got {
+		t.Errorf("principal does not match, expected %s got %s", expected, got)
+	}
+}
+
 func TestItFailsOnAuthorizationHeaderNotFound(t *testing.T) {
 	v := &fakeRequestValidator{}
 	a := NewJWTAuthAdapter(v)
@@ -32,13 +63,100 @@ func TestItFailsOnAuthorizationHeaderNotFound(t *testing.T) {
 	}
 }
 
+func TestItServesHealthChecksWithoutAuthorizationHeader(t *testing.T) {
+	a := NewJWTAuthAdapter(&fakeRequestValidator{})
+
+	if _, err := a.Interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, nopUnaryHandler); err != nil {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+
+	h := func(srv interface{}, ss grpc.ServerStream) error { return nil }
+	if err := a.StreamInterceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, h); err != nil {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+}
+
+func TestItAttachesTokenPrincipalToStreamContext(t *testing.T) {
+	v := &fakeRequestValidator{}
+	a := NewJWTAuthAdapter(v)
+
+	var p *principal.Principal
+	h := func(srv interface{}, ss grpc.ServerStream) error {
+		p, _ = principal.FromContext(ss.Context())
+		return nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"authorization": []string{"Bearer fake_jwt_token"}})
+	if err := a.StreamInterceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/v1.FakeService/Foo"}, h); err != nil {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+
+	if p == nil {
+		t.Fatal("expected principal on stream context")
+	}
+
+	if expected, got := "fake_principal", p.ID; expected != got {
+		t.Errorf("principal does not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItAttachesClientCertificatePrincipalWithoutAuthorizationHeader(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "fake_service", Organization: []string{"fake_tenant"}},
+		EmailAddresses: []string{"fake@example.com"},
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+
+	var p *principal.Principal
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		p, _ = principal.FromContext(ctx)
+		return nil, nil
+	}
+
+	if _, err := NewJWTAuthAdapter(&fakeRequestValidator{}).Interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}, h); err == nil {
+		t.Fatal("expected validation error with client certificates disabled")
+	}
+
+	a := NewJWTAuthAdapter(&fakeRequestValidator{}).WithClientCertificates()
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"authorization": []string{"Bearer "}})
+	if _, err := a.Interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}, h); err != nil {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+
+	if p == nil {
+		t.Fatal("expected principal on request context")
+	}
+
+	if expected, got := (principal.Principal{ID: "fake_service", Email: "fake@example.com", Tenant: "fake_tenant"}), *p; expected != got {
+		t.Errorf("principal does not match, expected %+v got %+v", expected, got)
+	}
+
+	unverified := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+	}})
+	if _, err := a.Interceptor(unverified, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}, nopUnaryHandler); err == nil {
+		t.Fatal("expected validation error on unverified client certificate")
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
 type fakeRequestValidator struct {
 	rawToken string
 }
 
-func (f *fakeRequestValidator) Validate(c context.Context, rawToken string) error {
+func (f *fakeRequestValidator) Parse(c context.Context, rawToken string) (*jwt.CustomClaims, error) {
 	f.rawToken = rawToken
-	return nil
+	return &jwt.CustomClaims{PrincipalID: "fake_principal"}, nil
 }
 
 func nopUnaryHandler(ctx context.Context, req interface{}) (interface{}, error) {
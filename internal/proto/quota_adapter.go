@@ -0,0 +1,132 @@
+package proto
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marcosQuesada/log-api/internal/metrics"
+	"github.com/marcosQuesada/log-api/internal/principal"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/quota"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type quotaLimiter interface {
+	Acquire(costs []quota.Cost) (func(), error)
+}
+
+// QuotaAdapter enforces rate limits and daily quotas on authenticated requests, by principal, API key and written bucket
+type QuotaAdapter struct {
+	limiter quotaLimiter
+}
+
+func NewQuotaAdapter(l quotaLimiter) *QuotaAdapter {
+	return &QuotaAdapter{limiter: l}
+}
+
+// Interceptor defines a unary Interceptor refusing requests over its limits as resource exhausted with retry info
+func (a *QuotaAdapter) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	release, err := a.acquire(p, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := handler(ctx, req)
+	if err != nil {
+		release()
+	}
+
+	return res, err
+}
+
+// StreamInterceptor defines a stream Interceptor taking a rate token from caller on each stream
+func (a *QuotaAdapter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	p, ok := principal.FromContext(ss.Context())
+	if !ok {
+		return handler(srv, ss)
+	}
+
+	if _, err := a.acquire(p, nil); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (a *QuotaAdapter) acquire(p *principal.Principal, req interface{}) (func(), error) {
+	release, err := a.limiter.Acquire(requestCosts(p, req))
+	var exc *quota.Exceeded
+	if errors.As(err, &exc) {
+		metrics.QuotaRejections.WithLabelValues(exc.Subject.Kind, exc.Reason).Inc()
+		return nil, exhausted(exc)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot acquire quota!")
+	}
+
+	return release, nil
+}
+
+// requestCosts charges written lines and value bytes to caller principal and API key, and to each written bucket
+func requestCosts(p *principal.Principal, req interface{}) []quota.Cost {
+	var lines []*v1.CreateLogLineRequest
+	switch r := req.(type) {
+	case *v1.CreateLogLineRequest:
+		lines = []*v1.CreateLogLineRequest{r}
+	case *v1.BatchCreateLogLinesRequest:
+		lines = r.GetLines()
+	}
+
+	caller := quota.Cost{}
+	var buckets []quota.Cost
+	idx := map[string]int{}
+	for _, l := range lines {
+		size := int64(len(l.GetValue()))
+		caller.Lines++
+		caller.Bytes += size
+
+		i, ok := idx[l.GetBucket()]
+		if !ok {
+			i = len(buckets)
+			idx[l.GetBucket()] = i
+			buckets = append(buckets, quota.Cost{Subject: quota.Subject{Kind: quota.KindBucket, Scope: p.Tenant, ID: l.GetBucket()}})
+		}
+		buckets[i].Lines++
+		buckets[i].Bytes += size
+	}
+
+	pc := caller
+	pc.Subject = quota.Subject{Kind: quota.KindPrincipal, ID: p.ID}
+	costs := []quota.Cost{pc}
+	if p.KeyID != "" {
+		kc := caller
+		kc.Subject = quota.Subject{Kind: quota.KindAPIKey, ID: p.KeyID}
+		costs = append(costs, kc)
+	}
+
+	return append(costs, buckets...)
+}
+
+// exhausted returns resource exhausted status with refused subject, plus retry delay once the request may fit later
+func exhausted(e *quota.Exceeded) error {
+	st := status.New(codes.ResourceExhausted, e.Error())
+	violation := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: e.Subject.String(), Description: e.Reason}}}
+	ds, err := st.WithDetails(violation)
+	if e.RetryAfter > 0 {
+		ds, err = st.WithDetails(violation, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	}
+	if err != nil {
+		return st.Err()
+	}
+
+	return ds.Err()
+}
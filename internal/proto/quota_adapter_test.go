@@ -0,0 +1,104 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/quota"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItChargesBatchLinesToCallerAndBuckets(t *testing.T) {
+	l := &fakeQuotaLimiter{}
+	a := NewQuotaAdapter(l)
+
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal", Tenant: "fake_tenant", KeyID: "fake_key"})
+	req := &v1.BatchCreateLogLinesRequest{Lines: []*v1.CreateLogLineRequest{
+		{Bucket: "debug", Value: "foo"},
+		{Bucket: "audit", Value: "bar_baz"},
+		{Bucket: "debug", Value: "x"},
+	}}
+	if _, err := a.Interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/v1.LogService/BatchCreateLogLines"}, nopUnaryHandler); err != nil {
+		t.Fatalf("unexpected interceptor error %v", err)
+	}
+
+	expected := []quota.Cost{
+		{Subject: quota.Subject{Kind: quota.KindPrincipal, ID: "fake_principal"}, Lines: 3, Bytes: 11},
+		{Subject: quota.Subject{Kind: quota.KindAPIKey, ID: "fake_key"}, Lines: 3, Bytes: 11},
+		{Subject: quota.Subject{Kind: quota.KindBucket, Scope: "fake_tenant", ID: "debug"}, Lines: 2, Bytes: 4},
+		{Subject: quota.Subject{Kind: quota.KindBucket, Scope: "fake_tenant", ID: "audit"}, Lines: 1, Bytes: 7},
+	}
+	if expected, got := len(expected), len(l.costs); expected != got {
+		t.Fatalf("costs size do not match, expected %d got %d", expected, got)
+	}
+	for i, c := range expected {
+		if got := l.costs[i]; c != got {
+			t.Errorf("costs do not match, expected %+v got %+v", c, got)
+		}
+	}
+}
+
+func TestItRefusesExhaustedRequestsWithRetryInfo(t *testing.T) {
+	l := &fakeQuotaLimiter{err: &quota.Exceeded{Subject: quota.Subject{Kind: quota.KindPrincipal, ID: "fake_principal"}, Reason: quota.ReasonRate, RetryAfter: time.Second}}
+	a := NewQuotaAdapter(l)
+
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal"})
+	_, err := a.Interceptor(ctx, &v1.CreateLogLineRequest{Bucket: "debug"}, &grpc.UnaryServerInfo{FullMethod: "/v1.LogService/CreateLogLine"}, nopUnaryHandler)
+
+	st := status.Convert(err)
+	if expected, got := codes.ResourceExhausted, st.Code(); expected != got {
+		t.Fatalf("status codes do not match, expected %s got %s", expected, got)
+	}
+
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry == nil {
+		t.Fatal("expected retry info detail")
+	}
+	if expected, got := time.Second, retry.GetRetryDelay().AsDuration(); expected != got {
+		t.Errorf("retry delays do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItReleasesQuotaOnFailedRequests(t *testing.T) {
+	l := &fakeQuotaLimiter{}
+	a := NewQuotaAdapter(l)
+
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal"})
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("fake failure")
+	}
+	if _, err := a.Interceptor(ctx, &v1.CreateLogLineRequest{Bucket: "debug"}, &grpc.UnaryServerInfo{FullMethod: "/v1.LogService/CreateLogLine"}, h); err == nil {
+		t.Fatal("expected handler error")
+	}
+
+	if !l.released {
+		t.Error("expected released quota")
+	}
+}
+
+type fakeQuotaLimiter struct {
+	costs    []quota.Cost
+	err      error
+	released bool
+}
+
+func (f *fakeQuotaLimiter) Acquire(costs []quota.Cost) (func(), error) {
+	f.costs = costs
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return func() { f.released = true }, nil
+}
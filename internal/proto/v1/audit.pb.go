@@ -0,0 +1,506 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.5.1
+// source: internal/proto/v1/audit.proto
+
+package v1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AuditBundleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	From   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *AuditBundleRequest) Reset() {
+	*x = AuditBundleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_audit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditBundleRequest) ProtoMessage() {}
+
+func (x *AuditBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_audit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditBundleRequest.ProtoReflect.Descriptor instead.
+func (*AuditBundleRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_audit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuditBundleRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *AuditBundleRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AuditBundleRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// AuditBundleEntry streams audited state first, then all proven log lines
+type AuditBundleEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Entry:
+	//	*AuditBundleEntry_State
+	//	*AuditBundleEntry_Line
+	Entry isAuditBundleEntry_Entry `protobuf_oneof:"entry"`
+}
+
+func (x *AuditBundleEntry) Reset() {
+	*x = AuditBundleEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_audit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditBundleEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditBundleEntry) ProtoMessage() {}
+
+func (x *AuditBundleEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_audit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditBundleEntry.ProtoReflect.Descriptor instead.
+func (*AuditBundleEntry) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_audit_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *AuditBundleEntry) GetEntry() isAuditBundleEntry_Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (x *AuditBundleEntry) GetState() *AuditState {
+	if x, ok := x.GetEntry().(*AuditBundleEntry_State); ok {
+		return x.State
+	}
+	return nil
+}
+
+func (x *AuditBundleEntry) GetLine() *AuditedLogLine {
+	if x, ok := x.GetEntry().(*AuditBundleEntry_Line); ok {
+		return x.Line
+	}
+	return nil
+}
+
+type isAuditBundleEntry_Entry interface {
+	isAuditBundleEntry_Entry()
+}
+
+type AuditBundleEntry_State struct {
+	State *AuditState `protobuf:"bytes,1,opt,name=state,proto3,oneof"`
+}
+
+type AuditBundleEntry_Line struct {
+	Line *AuditedLogLine `protobuf:"bytes,2,opt,name=line,proto3,oneof"`
+}
+
+func (*AuditBundleEntry_State) isAuditBundleEntry_Entry() {}
+
+func (*AuditBundleEntry_Line) isAuditBundleEntry_Entry() {}
+
+// AuditState holds immudb state log lines are proven against, state is immudb ImmutableState serialized
+type AuditState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Database string `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	TxId     uint64 `protobuf:"varint,2,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Signed   bool   `protobuf:"varint,3,opt,name=signed,proto3" json:"signed,omitempty"`
+	State    []byte `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *AuditState) Reset() {
+	*x = AuditState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_audit_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditState) ProtoMessage() {}
+
+func (x *AuditState) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_audit_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditState.ProtoReflect.Descriptor instead.
+func (*AuditState) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_audit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AuditState) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *AuditState) GetTxId() uint64 {
+	if x != nil {
+		return x.TxId
+	}
+	return 0
+}
+
+func (x *AuditState) GetSigned() bool {
+	if x != nil {
+		return x.Signed
+	}
+	return false
+}
+
+func (x *AuditState) GetState() []byte {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+// AuditedLogLine holds a stored log line with its proof, proof is immudb VerifiableEntry serialized
+type AuditedLogLine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key       string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Bucket    string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Value     string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Tx        uint64                 `protobuf:"varint,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	Revision  uint64                 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Proof     []byte                 `protobuf:"bytes,7,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *AuditedLogLine) Reset() {
+	*x = AuditedLogLine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_audit_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditedLogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditedLogLine) ProtoMessage() {}
+
+func (x *AuditedLogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_audit_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditedLogLine.ProtoReflect.Descriptor instead.
+func (*AuditedLogLine) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_audit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AuditedLogLine) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AuditedLogLine) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *AuditedLogLine) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *AuditedLogLine) GetTx() uint64 {
+	if x != nil {
+		return x.Tx
+	}
+	return 0
+}
+
+func (x *AuditedLogLine) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *AuditedLogLine) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AuditedLogLine) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+var File_internal_proto_v1_audit_proto protoreflect.FileDescriptor
+
+var file_internal_proto_v1_audit_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75, 0x64, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x02, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x88, 0x01, 0x0a, 0x12, 0x41, 0x75, 0x64, 0x69, 0x74, 0x42, 0x75, 0x6e, 0x64,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x6d, 0x0a,
+	0x10, 0x41, 0x75, 0x64, 0x69, 0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x6c, 0x69, 0x6e,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x00, 0x52, 0x04, 0x6c,
+	0x69, 0x6e, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x6b, 0x0a, 0x0a,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x74, 0x78, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x78, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x0e, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x0e, 0x0a, 0x02,
+	0x74, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74, 0x78, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x32, 0x7a, 0x0a, 0x0c, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6a, 0x0a, 0x11, 0x45, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x16,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x25, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x1f, 0x12, 0x1d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75,
+	0x64, 0x69, 0x74, 0x2f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x2f, 0x7b, 0x62, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x7d, 0x30, 0x01, 0x42, 0x14, 0x5a, 0x12, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_internal_proto_v1_audit_proto_rawDescOnce sync.Once
+	file_internal_proto_v1_audit_proto_rawDescData = file_internal_proto_v1_audit_proto_rawDesc
+)
+
+func file_internal_proto_v1_audit_proto_rawDescGZIP() []byte {
+	file_internal_proto_v1_audit_proto_rawDescOnce.Do(func() {
+		file_internal_proto_v1_audit_proto_rawDescData = protoimpl.X.CompressGZIP(file_internal_proto_v1_audit_proto_rawDescData)
+	})
+	return file_internal_proto_v1_audit_proto_rawDescData
+}
+
+var file_internal_proto_v1_audit_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_internal_proto_v1_audit_proto_goTypes = []interface{}{
+	(*AuditBundleRequest)(nil),    // 0: v1.AuditBundleRequest
+	(*AuditBundleEntry)(nil),      // 1: v1.AuditBundleEntry
+	(*AuditState)(nil),            // 2: v1.AuditState
+	(*AuditedLogLine)(nil),        // 3: v1.AuditedLogLine
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_internal_proto_v1_audit_proto_depIdxs = []int32{
+	4, // 0: v1.AuditBundleRequest.from:type_name -> google.protobuf.Timestamp
+	4, // 1: v1.AuditBundleRequest.to:type_name -> google.protobuf.Timestamp
+	2, // 2: v1.AuditBundleEntry.state:type_name -> v1.AuditState
+	3, // 3: v1.AuditBundleEntry.line:type_name -> v1.AuditedLogLine
+	4, // 4: v1.AuditedLogLine.created_at:type_name -> google.protobuf.Timestamp
+	0, // 5: v1.AuditService.ExportAuditBundle:input_type -> v1.AuditBundleRequest
+	1, // 6: v1.AuditService.ExportAuditBundle:output_type -> v1.AuditBundleEntry
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_internal_proto_v1_audit_proto_init() }
+func file_internal_proto_v1_audit_proto_init() {
+	if File_internal_proto_v1_audit_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_internal_proto_v1_audit_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditBundleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_v1_audit_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditBundleEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_v1_audit_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_v1_audit_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditedLogLine); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_internal_proto_v1_audit_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*AuditBundleEntry_State)(nil),
+		(*AuditBundleEntry_Line)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_internal_proto_v1_audit_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_internal_proto_v1_audit_proto_goTypes,
+		DependencyIndexes: file_internal_proto_v1_audit_proto_depIdxs,
+		MessageInfos:      file_internal_proto_v1_audit_proto_msgTypes,
+	}.Build()
+	File_internal_proto_v1_audit_proto = out.File
+	file_internal_proto_v1_audit_proto_rawDesc = nil
+	file_internal_proto_v1_audit_proto_goTypes = nil
+	file_internal_proto_v1_audit_proto_depIdxs = nil
+}
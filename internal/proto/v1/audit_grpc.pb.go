@@ -0,0 +1,128 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AuditServiceClient is the client API for AuditService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuditServiceClient interface {
+	ExportAuditBundle(ctx context.Context, in *AuditBundleRequest, opts ...grpc.CallOption) (AuditService_ExportAuditBundleClient, error)
+}
+
+type auditServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuditServiceClient(cc grpc.ClientConnInterface) AuditServiceClient {
+	return &auditServiceClient{cc}
+}
+
+func (c *auditServiceClient) ExportAuditBundle(ctx context.Context, in *AuditBundleRequest, opts ...grpc.CallOption) (AuditService_ExportAuditBundleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AuditService_ServiceDesc.Streams[0], "/v1.AuditService/ExportAuditBundle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &auditServiceExportAuditBundleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AuditService_ExportAuditBundleClient interface {
+	Recv() (*AuditBundleEntry, error)
+	grpc.ClientStream
+}
+
+type auditServiceExportAuditBundleClient struct {
+	grpc.ClientStream
+}
+
+func (x *auditServiceExportAuditBundleClient) Recv() (*AuditBundleEntry, error) {
+	m := new(AuditBundleEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AuditServiceServer is the server API for AuditService service.
+// All implementations must embed UnimplementedAuditServiceServer
+// for forward compatibility
+type AuditServiceServer interface {
+	ExportAuditBundle(*AuditBundleRequest, AuditService_ExportAuditBundleServer) error
+	mustEmbedUnimplementedAuditServiceServer()
+}
+
+// UnimplementedAuditServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAuditServiceServer struct {
+}
+
+func (UnimplementedAuditServiceServer) ExportAuditBundle(*AuditBundleRequest, AuditService_ExportAuditBundleServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportAuditBundle not implemented")
+}
+func (UnimplementedAuditServiceServer) mustEmbedUnimplementedAuditServiceServer() {}
+
+// UnsafeAuditServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuditServiceServer will
+// result in compilation errors.
+type UnsafeAuditServiceServer interface {
+	mustEmbedUnimplementedAuditServiceServer()
+}
+
+func RegisterAuditServiceServer(s grpc.ServiceRegistrar, srv AuditServiceServer) {
+	s.RegisterService(&AuditService_ServiceDesc, srv)
+}
+
+func _AuditService_ExportAuditBundle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AuditBundleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuditServiceServer).ExportAuditBundle(m, &auditServiceExportAuditBundleServer{stream})
+}
+
+type AuditService_ExportAuditBundleServer interface {
+	Send(*AuditBundleEntry) error
+	grpc.ServerStream
+}
+
+type auditServiceExportAuditBundleServer struct {
+	grpc.ServerStream
+}
+
+func (x *auditServiceExportAuditBundleServer) Send(m *AuditBundleEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// AuditService_ServiceDesc is the grpc.ServiceDesc for AuditService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuditService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.AuditService",
+	HandlerType: (*AuditServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportAuditBundle",
+			Handler:       _AuditService_ExportAuditBundle_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/proto/v1/audit.proto",
+}
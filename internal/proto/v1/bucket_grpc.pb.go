@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BucketServiceClient is the client API for BucketService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BucketServiceClient interface {
+	CreateBucket(ctx context.Context, in *CreateBucketRequest, opts ...grpc.CallOption) (*Bucket, error)
+	ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*Buckets, error)
+	GetBucket(ctx context.Context, in *GetBucketRequest, opts ...grpc.CallOption) (*Bucket, error)
+	ArchiveBucket(ctx context.Context, in *ArchiveBucketRequest, opts ...grpc.CallOption) (*Bucket, error)
+}
+
+type bucketServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBucketServiceClient(cc grpc.ClientConnInterface) BucketServiceClient {
+	return &bucketServiceClient{cc}
+}
+
+func (c *bucketServiceClient) CreateBucket(ctx context.Context, in *CreateBucketRequest, opts ...grpc.CallOption) (*Bucket, error) {
+	out := new(Bucket)
+	err := c.cc.Invoke(ctx, "/v1.BucketService/CreateBucket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bucketServiceClient) ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*Buckets, error) {
+	out := new(Buckets)
+	err := c.cc.Invoke(ctx, "/v1.BucketService/ListBuckets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bucketServiceClient) GetBucket(ctx context.Context, in *GetBucketRequest, opts ...grpc.CallOption) (*Bucket, error) {
+	out := new(Bucket)
+	err := c.cc.Invoke(ctx, "/v1.BucketService/GetBucket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bucketServiceClient) ArchiveBucket(ctx context.Context, in *ArchiveBucketRequest, opts ...grpc.CallOption) (*Bucket, error) {
+	out := new(Bucket)
+	err := c.cc.Invoke(ctx, "/v1.BucketService/ArchiveBucket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BucketServiceServer is the server API for BucketService service.
+// All implementations must embed UnimplementedBucketServiceServer
+// for forward compatibility
+type BucketServiceServer interface {
+	CreateBucket(context.Context, *CreateBucketRequest) (*Bucket, error)
+	ListBuckets(context.Context, *ListBucketsRequest) (*Buckets, error)
+	GetBucket(context.Context, *GetBucketRequest) (*Bucket, error)
+	ArchiveBucket(context.Context, *ArchiveBucketRequest) (*Bucket, error)
+	mustEmbedUnimplementedBucketServiceServer()
+}
+
+// UnimplementedBucketServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBucketServiceServer struct {
+}
+
+func (UnimplementedBucketServiceServer) CreateBucket(context.Context, *CreateBucketRequest) (*Bucket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBucket not implemented")
+}
+func (UnimplementedBucketServiceServer) ListBuckets(context.Context, *ListBucketsRequest) (*Buckets, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBuckets not implemented")
+}
+func (UnimplementedBucketServiceServer) GetBucket(context.Context, *GetBucketRequest) (*Bucket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBucket not implemented")
+}
+func (UnimplementedBucketServiceServer) ArchiveBucket(context.Context, *ArchiveBucketRequest) (*Bucket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveBucket not implemented")
+}
+func (UnimplementedBucketServiceServer) mustEmbedUnimplementedBucketServiceServer() {}
+
+// UnsafeBucketServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BucketServiceServer will
+// result in compilation errors.
+type UnsafeBucketServiceServer interface {
+	mustEmbedUnimplementedBucketServiceServer()
+}
+
+func RegisterBucketServiceServer(s grpc.ServiceRegistrar, srv BucketServiceServer) {
+	s.RegisterService(&BucketService_ServiceDesc, srv)
+}
+
+func _BucketService_CreateBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBucketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketServiceServer).CreateBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.BucketService/CreateBucket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketServiceServer).CreateBucket(ctx, req.(*CreateBucketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BucketService_ListBuckets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBucketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketServiceServer).ListBuckets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.BucketService/ListBuckets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketServiceServer).ListBuckets(ctx, req.(*ListBucketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BucketService_GetBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketServiceServer).GetBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.BucketService/GetBucket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketServiceServer).GetBucket(ctx, req.(*GetBucketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BucketService_ArchiveBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveBucketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketServiceServer).ArchiveBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.BucketService/ArchiveBucket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketServiceServer).ArchiveBucket(ctx, req.(*ArchiveBucketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BucketService_ServiceDesc is the grpc.ServiceDesc for BucketService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BucketService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.BucketService",
+	HandlerType: (*BucketServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBucket",
+			Handler:    _BucketService_CreateBucket_Handler,
+		},
+		{
+			MethodName: "ListBuckets",
+			Handler:    _BucketService_ListBuckets_Handler,
+		},
+		{
+			MethodName: "GetBucket",
+			Handler:    _BucketService_GetBucket_Handler,
+		},
+		{
+			MethodName: "ArchiveBucket",
+			Handler:    _BucketService_ArchiveBucket_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/proto/v1/bucket.proto",
+}
@@ -23,10 +23,13 @@ type LogServiceClient interface {
 	BatchCreateLogLines(ctx context.Context, in *BatchCreateLogLinesRequest, opts ...grpc.CallOption) (*BatchCreateLogLinesResponse, error)
 	GetAllLogLinesHistory(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LogLineHistories, error)
 	GetLastNLogLinesHistory(ctx context.Context, in *LastNLogLinesHistoryRequest, opts ...grpc.CallOption) (*LogLineHistories, error)
+	GetLogLineHistory(ctx context.Context, in *LogLineHistoryRequest, opts ...grpc.CallOption) (*LogLineHistory, error)
 	GetLogLineCount(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Count, error)
 	GetLogLineByKey(ctx context.Context, in *LogLineByKeyRequest, opts ...grpc.CallOption) (*LogLine, error)
 	GetLogLinesByPrefix(ctx context.Context, in *LogLineByPrefixRequest, opts ...grpc.CallOption) (*LogLines, error)
 	GetLogLinesByBucket(ctx context.Context, in *LogLineByBucketRequest, opts ...grpc.CallOption) (*LogLines, error)
+	GetSigningKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SigningKeys, error)
+	ExportLogLines(ctx context.Context, in *ExportLogLinesRequest, opts ...grpc.CallOption) (LogService_ExportLogLinesClient, error)
 }
 
 type logServiceClient struct {
@@ -73,6 +76,15 @@ func (c *logServiceClient) GetLastNLogLinesHistory(ctx context.Context, in *Last
 	return out, nil
 }
 
+func (c *logServiceClient) GetLogLineHistory(ctx context.Context, in *LogLineHistoryRequest, opts ...grpc.CallOption) (*LogLineHistory, error) {
+	out := new(LogLineHistory)
+	err := c.cc.Invoke(ctx, "/v1.LogService/GetLogLineHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *logServiceClient) GetLogLineCount(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Count, error) {
 	out := new(Count)
 	err := c.cc.Invoke(ctx, "/v1.LogService/GetLogLineCount", in, out, opts...)
@@ -109,6 +121,47 @@ func (c *logServiceClient) GetLogLinesByBucket(ctx context.Context, in *LogLineB
 	return out, nil
 }
 
+func (c *logServiceClient) GetSigningKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SigningKeys, error) {
+	out := new(SigningKeys)
+	err := c.cc.Invoke(ctx, "/v1.LogService/GetSigningKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logServiceClient) ExportLogLines(ctx context.Context, in *ExportLogLinesRequest, opts ...grpc.CallOption) (LogService_ExportLogLinesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LogService_ServiceDesc.Streams[0], "/v1.LogService/ExportLogLines", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logServiceExportLogLinesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogService_ExportLogLinesClient interface {
+	Recv() (*ExportedLogLine, error)
+	grpc.ClientStream
+}
+
+type logServiceExportLogLinesClient struct {
+	grpc.ClientStream
+}
+
+func (x *logServiceExportLogLinesClient) Recv() (*ExportedLogLine, error) {
+	m := new(ExportedLogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LogServiceServer is the server API for LogService service.
 // All implementations must embed UnimplementedLogServiceServer
 // for forward compatibility
@@ -117,10 +170,13 @@ type LogServiceServer interface {
 	BatchCreateLogLines(context.Context, *BatchCreateLogLinesRequest) (*BatchCreateLogLinesResponse, error)
 	GetAllLogLinesHistory(context.Context, *emptypb.Empty) (*LogLineHistories, error)
 	GetLastNLogLinesHistory(context.Context, *LastNLogLinesHistoryRequest) (*LogLineHistories, error)
+	GetLogLineHistory(context.Context, *LogLineHistoryRequest) (*LogLineHistory, error)
 	GetLogLineCount(context.Context, *emptypb.Empty) (*Count, error)
 	GetLogLineByKey(context.Context, *LogLineByKeyRequest) (*LogLine, error)
 	GetLogLinesByPrefix(context.Context, *LogLineByPrefixRequest) (*LogLines, error)
 	GetLogLinesByBucket(context.Context, *LogLineByBucketRequest) (*LogLines, error)
+	GetSigningKeys(context.Context, *emptypb.Empty) (*SigningKeys, error)
+	ExportLogLines(*ExportLogLinesRequest, LogService_ExportLogLinesServer) error
 	mustEmbedUnimplementedLogServiceServer()
 }
 
@@ -140,6 +196,9 @@ func (UnimplementedLogServiceServer) GetAllLogLinesHistory(context.Context, *emp
 func (UnimplementedLogServiceServer) GetLastNLogLinesHistory(context.Context, *LastNLogLinesHistoryRequest) (*LogLineHistories, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLastNLogLinesHistory not implemented")
 }
+func (UnimplementedLogServiceServer) GetLogLineHistory(context.Context, *LogLineHistoryRequest) (*LogLineHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLineHistory not implemented")
+}
 func (UnimplementedLogServiceServer) GetLogLineCount(context.Context, *emptypb.Empty) (*Count, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLogLineCount not implemented")
 }
@@ -152,6 +211,12 @@ func (UnimplementedLogServiceServer) GetLogLinesByPrefix(context.Context, *LogLi
 func (UnimplementedLogServiceServer) GetLogLinesByBucket(context.Context, *LogLineByBucketRequest) (*LogLines, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLogLinesByBucket not implemented")
 }
+func (UnimplementedLogServiceServer) GetSigningKeys(context.Context, *emptypb.Empty) (*SigningKeys, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSigningKeys not implemented")
+}
+func (UnimplementedLogServiceServer) ExportLogLines(*ExportLogLinesRequest, LogService_ExportLogLinesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportLogLines not implemented")
+}
 func (UnimplementedLogServiceServer) mustEmbedUnimplementedLogServiceServer() {}
 
 // UnsafeLogServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -237,6 +302,24 @@ func _LogService_GetLastNLogLinesHistory_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogService_GetLogLineHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLineHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).GetLogLineHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.LogService/GetLogLineHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).GetLogLineHistory(ctx, req.(*LogLineHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LogService_GetLogLineCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -309,6 +392,45 @@ func _LogService_GetLogLinesByBucket_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogService_GetSigningKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).GetSigningKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.LogService/GetSigningKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).GetSigningKeys(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogService_ExportLogLines_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportLogLinesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogServiceServer).ExportLogLines(m, &logServiceExportLogLinesServer{stream})
+}
+
+type LogService_ExportLogLinesServer interface {
+	Send(*ExportedLogLine) error
+	grpc.ServerStream
+}
+
+type logServiceExportLogLinesServer struct {
+	grpc.ServerStream
+}
+
+func (x *logServiceExportLogLinesServer) Send(m *ExportedLogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // LogService_ServiceDesc is the grpc.ServiceDesc for LogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -332,6 +454,10 @@ var LogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLastNLogLinesHistory",
 			Handler:    _LogService_GetLastNLogLinesHistory_Handler,
 		},
+		{
+			MethodName: "GetLogLineHistory",
+			Handler:    _LogService_GetLogLineHistory_Handler,
+		},
 		{
 			MethodName: "GetLogLineCount",
 			Handler:    _LogService_GetLogLineCount_Handler,
@@ -348,7 +474,17 @@ var LogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLogLinesByBucket",
 			Handler:    _LogService_GetLogLinesByBucket_Handler,
 		},
+		{
+			MethodName: "GetSigningKeys",
+			Handler:    _LogService_GetSigningKeys_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportLogLines",
+			Handler:       _LogService_ExportLogLines_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/proto/v1/log.proto",
 }
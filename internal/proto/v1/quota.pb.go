@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.5.1
+// source: internal/proto/v1/quota.proto
+
+package v1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// QuotaUsageRequest reports caller principal and API key usage, plus requested buckets on its tenant
+type QuotaUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Buckets []string `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+}
+
+func (x *QuotaUsageRequest) Reset() {
+	*x = QuotaUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_quota_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuotaUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaUsageRequest) ProtoMessage() {}
+
+func (x *QuotaUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_quota_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaUsageRequest.ProtoReflect.Descriptor instead.
+func (*QuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_quota_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuotaUsageRequest) GetBuckets() []string {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// QuotaUsage holds a subject limits and its usage, zero limits are unlimited
+type QuotaUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind       string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Id         string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Rate       float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	Burst      int64                  `protobuf:"varint,4,opt,name=burst,proto3" json:"burst,omitempty"`
+	Available  float64                `protobuf:"fixed64,5,opt,name=available,proto3" json:"available,omitempty"`
+	DailyLines int64                  `protobuf:"varint,6,opt,name=daily_lines,json=dailyLines,proto3" json:"daily_lines,omitempty"`
+	DailyBytes int64                  `protobuf:"varint,7,opt,name=daily_bytes,json=dailyBytes,proto3" json:"daily_bytes,omitempty"`
+	UsedLines  int64                  `protobuf:"varint,8,opt,name=used_lines,json=usedLines,proto3" json:"used_lines,omitempty"`
+	UsedBytes  int64                  `protobuf:"varint,9,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	ResetsAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=resets_at,json=resetsAt,proto3" json:"resets_at,omitempty"`
+}
+
+func (x *QuotaUsage) Reset() {
+	*x = QuotaUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_quota_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuotaUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaUsage) ProtoMessage() {}
+
+func (x *QuotaUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_quota_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaUsage.ProtoReflect.Descriptor instead.
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_quota_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QuotaUsage) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetDailyLines() int64 {
+	if x != nil {
+		return x.DailyLines
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetDailyBytes() int64 {
+	if x != nil {
+		return x.DailyBytes
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetUsedLines() int64 {
+	if x != nil {
+		return x.UsedLines
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetResetsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResetsAt
+	}
+	return nil
+}
+
+type QuotaUsages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Usages []*QuotaUsage `protobuf:"bytes,1,rep,name=usages,proto3" json:"usages,omitempty"`
+}
+
+func (x *QuotaUsages) Reset() {
+	*x = QuotaUsages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_v1_quota_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuotaUsages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaUsages) ProtoMessage() {}
+
+func (x *QuotaUsages) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_v1_quota_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaUsages.ProtoReflect.Descriptor instead.
+func (*QuotaUsages) Descriptor() ([]byte, []int) {
+	return file_internal_proto_v1_quota_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *QuotaUsages) GetUsages() []*QuotaUsage {
+	if x != nil {
+		return x.Usages
+	}
+	return nil
+}
+
+var File_internal_proto_v1_quota_proto protoreflect.FileDescriptor
+
+var file_internal_proto_v1_quota_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x02, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x2d, 0x0a, 0x11, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x22, 0xb1, 0x02, 0x0a, 0x0a, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x75, 0x72, 0x73,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x64, 0x61, 0x69, 0x6c, 0x79, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x75, 0x73, 0x65, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x75, 0x73, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x09,
+	0x72, 0x65, 0x73, 0x65, 0x74, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x72, 0x65, 0x73,
+	0x65, 0x74, 0x73, 0x41, 0x74, 0x22, 0x35, 0x0a, 0x0b, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x06, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x32, 0x64, 0x0a, 0x0c,
+	0x51, 0x75, 0x6f, 0x74, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x54, 0x0a, 0x0d,
+	0x47, 0x65, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x15, 0x2e,
+	0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x2f, 0x75, 0x73, 0x61,
+	0x67, 0x65, 0x42, 0x14, 0x5a, 0x12, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_internal_proto_v1_quota_proto_rawDescOnce sync.Once
+	file_internal_proto_v1_quota_proto_rawDescData = file_internal_proto_v1_quota_proto_rawDesc
+)
+
+func file_internal_proto_v1_quota_proto_rawDescGZIP() []byte {
+	file_internal_proto_v1_quota_proto_rawDescOnce.Do(func() {
+		file_internal_proto_v1_quota_proto_rawDescData = protoimpl.X.CompressGZIP(file_internal_proto_v1_quota_proto_rawDescData)
+	})
+	return file_internal_proto_v1_quota_proto_rawDescData
+}
+
+var file_internal_proto_v1_quota_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_internal_proto_v1_quota_proto_goTypes = []interface{}{
+	(*QuotaUsageRequest)(nil),     // 0: v1.QuotaUsageRequest
+	(*QuotaUsage)(nil),            // 1: v1.QuotaUsage
+	(*QuotaUsages)(nil),           // 2: v1.QuotaUsages
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_internal_proto_v1_quota_proto_depIdxs = []int32{
+	3, // 0: v1.QuotaUsage.resets_at:type_name -> google.protobuf.Timestamp
+	1, // 1: v1.QuotaUsages.usages:type_name -> v1.QuotaUsage
+	0, // 2: v1.QuotaService.GetQuotaUsage:input_type -> v1.QuotaUsageRequest
+	2, // 3: v1.QuotaService.GetQuotaUsage:output_type -> v1.QuotaUsages
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_internal_proto_v1_quota_proto_init() }
+func file_internal_proto_v1_quota_proto_init() {
+	if File_internal_proto_v1_quota_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_internal_proto_v1_quota_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuotaUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_v1_quota_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuotaUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_v1_quota_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuotaUsages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_internal_proto_v1_quota_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_internal_proto_v1_quota_proto_goTypes,
+		DependencyIndexes: file_internal_proto_v1_quota_proto_depIdxs,
+		MessageInfos:      file_internal_proto_v1_quota_proto_msgTypes,
+	}.Build()
+	File_internal_proto_v1_quota_proto = out.File
+	file_internal_proto_v1_quota_proto_rawDesc = nil
+	file_internal_proto_v1_quota_proto_goTypes = nil
+	file_internal_proto_v1_quota_proto_depIdxs = nil
+}
@@ -0,0 +1,302 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// Subject kinds
+const (
+	KindPrincipal = "principal"
+	KindAPIKey    = "api_key"
+	KindBucket    = "bucket"
+)
+
+// Refusal reasons
+const (
+	ReasonRate       = "rate"
+	ReasonBurst      = "burst"
+	ReasonDailyLines = "daily_lines"
+	ReasonDailyBytes = "daily_bytes"
+)
+
+const day = time.Hour * 24
+
+var ErrExhausted = errors.New("quota exhausted")
+
+// Subject identifies a limited caller or bucket, Scope keeps tenant buckets usage apart while ID selects its limit
+type Subject struct {
+	Kind  string
+	Scope string
+	ID    string
+}
+
+func (s Subject) String() string {
+	if s.Scope == "" {
+		return s.Kind + ":" + s.ID
+	}
+
+	return s.Kind + ":" + s.Scope + "/" + s.ID
+}
+
+// Cost is a request share on a subject, requests writing no lines still take a rate token
+type Cost struct {
+	Subject Subject
+	Lines   int64
+	Bytes   int64
+}
+
+// Exceeded reports the subject refusing a request, RetryAfter is zero when the request never fits its limit
+type Exceeded struct {
+	Subject    Subject
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *Exceeded) Error() string {
+	if e.RetryAfter == 0 {
+		return fmt.Sprintf("%s %s quota exhausted, request exceeds its limit", e.Subject, e.Reason)
+	}
+
+	return fmt.Sprintf("%s %s quota exhausted, retry after %v", e.Subject, e.Reason, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *Exceeded) Is(target error) bool {
+	return target == ErrExhausted
+}
+
+// Usage holds a subject limit, with its effective burst, and its current usage
+type Usage struct {
+	Subject   Subject
+	Limit     Limit
+	Available float64
+	Lines     int64
+	Bytes     int64
+	ResetsAt  time.Time
+}
+
+type state struct {
+	tokens float64
+	last   time.Time
+	lines  int64
+	bytes  int64
+}
+
+// Limiter enforces policy token buckets and daily quotas in memory, so usage is per server instance
+// and starts over on restarts
+type Limiter struct {
+	mutex  sync.Mutex
+	policy *Policy
+	states map[Subject]*state
+	day    time.Time
+	now    func() time.Time
+}
+
+// NewLimiter instantiates limiter enforcing policy
+func NewLimiter(p *Policy) *Limiter {
+	return &Limiter{
+		policy: p,
+		states: make(map[Subject]*state),
+		now:    time.Now,
+	}
+}
+
+// SetPolicy replaces enforced policy, usage is kept
+func (l *Limiter) SetPolicy(p *Policy) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.policy = p
+}
+
+// Acquire takes request costs from all its subjects or none, refusals are *Exceeded errors.
+// Returned release gives daily quotas back, as for requests failing afterwards
+func (l *Limiter) Acquire(costs []Cost) (func(), error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.roll(now)
+
+	for _, c := range costs {
+		lim := l.policy.Limit(c.Subject)
+		st := l.state(c.Subject, lim, now)
+		if err := l.check(c, lim, st, now); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range costs {
+		st := l.states[c.Subject]
+		if l.policy.Limit(c.Subject).Rate > 0 {
+			st.tokens -= tokens(c)
+		}
+		st.lines += c.Lines
+		st.bytes += c.Bytes
+	}
+
+	today := l.day
+	return func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		if !l.day.Equal(today) {
+			return
+		}
+		for _, c := range costs {
+			if st, ok := l.states[c.Subject]; ok {
+				st.lines -= c.Lines
+				st.bytes -= c.Bytes
+			}
+		}
+	}, nil
+}
+
+// Usage returns subject limit and usage
+func (l *Limiter) Usage(s Subject) Usage {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.roll(now)
+	lim := l.policy.Limit(s)
+	u := Usage{Subject: s, Limit: lim, ResetsAt: l.day.Add(day)}
+	if lim.Rate > 0 {
+		u.Limit.Burst = int64(lim.burst())
+		u.Available = lim.burst()
+	}
+
+	st, ok := l.states[s]
+	if !ok {
+		return u
+	}
+	l.refill(st, lim, now)
+	if lim.Rate > 0 {
+		u.Available = st.tokens
+	}
+	u.Lines, u.Bytes = st.lines, st.bytes
+
+	return u
+}
+
+// Watch reloads policy file on each interval once it changes, until context is done.
+// Invalid policies are logged and the enforced one is kept
+func (l *Limiter) Watch(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var modified time.Time
+	if fi, err := os.Stat(path); err == nil {
+		modified = fi.ModTime()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			log.Printf("Unable to stat quota policy %s, error %v", path, err)
+			continue
+		}
+		if fi.ModTime().Equal(modified) {
+			continue
+		}
+		modified = fi.ModTime()
+
+		p, err := LoadPolicy(path)
+		if err != nil {
+			log.Printf("Unable to reload quota policy, keeping enforced one, error %v", err)
+			continue
+		}
+		l.SetPolicy(p)
+		log.Printf("Quota policy %s reloaded", path)
+	}
+}
+
+func (l *Limiter) check(c Cost, lim Limit, st *state, now time.Time) error {
+	if lim.DailyLines > 0 && c.Lines > 0 && st.lines+c.Lines > lim.DailyLines {
+		return &Exceeded{Subject: c.Subject, Reason: ReasonDailyLines, RetryAfter: l.retryTomorrow(c.Lines, lim.DailyLines, now)}
+	}
+	if lim.DailyBytes > 0 && c.Bytes > 0 && st.bytes+c.Bytes > lim.DailyBytes {
+		return &Exceeded{Subject: c.Subject, Reason: ReasonDailyBytes, RetryAfter: l.retryTomorrow(c.Bytes, lim.DailyBytes, now)}
+	}
+
+	if lim.Rate == 0 {
+		return nil
+	}
+	n := tokens(c)
+	if n > lim.burst() {
+		return &Exceeded{Subject: c.Subject, Reason: ReasonBurst}
+	}
+	if st.tokens < n {
+		wait := time.Duration(math.Ceil((n - st.tokens) / lim.Rate * float64(time.Second)))
+		return &Exceeded{Subject: c.Subject, Reason: ReasonRate, RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// retryTomorrow returns time until quotas reset, requests bigger than the whole quota never fit
+func (l *Limiter) retryTomorrow(cost, quota int64, now time.Time) time.Duration {
+	if cost > quota {
+		return 0
+	}
+
+	return l.day.Add(day).Sub(now)
+}
+
+// state returns refilled subject state, new subjects start with a full bucket
+func (l *Limiter) state(s Subject, lim Limit, now time.Time) *state {
+	st, ok := l.states[s]
+	if !ok {
+		st = &state{tokens: lim.burst(), last: now}
+		l.states[s] = st
+		return st
+	}
+	l.refill(st, lim, now)
+
+	return st
+}
+
+func (l *Limiter) refill(st *state, lim Limit, now time.Time) {
+	if elapsed := now.Sub(st.last).Seconds(); elapsed > 0 {
+		st.tokens = math.Min(lim.burst(), st.tokens+elapsed*lim.Rate)
+	}
+	st.last = now
+}
+
+// roll resets daily quotas on UTC day change, dropping subjects idle since, so states do not grow with issued keys
+func (l *Limiter) roll(now time.Time) {
+	today := now.UTC().Truncate(day)
+	if today.Equal(l.day) {
+		return
+	}
+	l.day = today
+
+	for s, st := range l.states {
+		if now.Sub(st.last) > day {
+			delete(l.states, s)
+			continue
+		}
+		st.lines, st.bytes = 0, 0
+	}
+}
+
+// tokens returns cost rate tokens, one at least
+func tokens(c Cost) float64 {
+	if c.Lines > 1 {
+		return float64(c.Lines)
+	}
+
+	return 1
+}
@@ -0,0 +1,157 @@
+package quota
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestItRefusesRequestsOverRateWithRetryDelay(t *testing.T) {
+	l, clock := newFakeLimiter(&Policy{Principals: Rules{Default: Limit{Rate: 10, Burst: 10}}})
+	principal := Subject{Kind: KindPrincipal, ID: "fake_principal"}
+
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 8}}); err != nil {
+		t.Fatalf("unexpected error acquiring %v", err)
+	}
+
+	_, err := l.Acquire([]Cost{{Subject: principal, Lines: 4}})
+	var exc *Exceeded
+	if !errors.As(err, &exc) || !errors.Is(err, ErrExhausted) {
+		t.Fatalf("unexpected error acquiring over rate, got %v", err)
+	}
+	if expected, got := ReasonRate, exc.Reason; expected != got {
+		t.Errorf("reasons do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := time.Millisecond*200, exc.RetryAfter; expected != got {
+		t.Errorf("retry delays do not match, expected %v got %v", expected, got)
+	}
+
+	clock.add(exc.RetryAfter)
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 4}}); err != nil {
+		t.Fatalf("unexpected error acquiring after retry delay %v", err)
+	}
+
+	_, err = l.Acquire([]Cost{{Subject: principal, Lines: 11}})
+	if !errors.As(err, &exc) || exc.Reason != ReasonBurst || exc.RetryAfter != 0 {
+		t.Errorf("unexpected error acquiring over burst, got %v", err)
+	}
+}
+
+func TestItTakesCostsFromAllSubjectsOrNone(t *testing.T) {
+	l, _ := newFakeLimiter(&Policy{
+		Principals: Rules{Default: Limit{Rate: 100}},
+		Buckets:    Rules{Overrides: map[string]Limit{"fake_bucket": {DailyLines: 5}}},
+	})
+	principal := Subject{Kind: KindPrincipal, ID: "fake_principal"}
+	bucket := Subject{Kind: KindBucket, Scope: "fake_tenant", ID: "fake_bucket"}
+
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 6}, {Subject: bucket, Lines: 6}}); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("unexpected error acquiring over bucket quota, got %v", err)
+	}
+
+	u := l.Usage(principal)
+	if expected, got := float64(100), u.Available; expected != got {
+		t.Errorf("available tokens do not match, expected %v got %v", expected, got)
+	}
+	if expected, got := int64(0), u.Lines; expected != got {
+		t.Errorf("used lines do not match, expected %d got %d", expected, got)
+	}
+
+	other := Subject{Kind: KindBucket, Scope: "other_tenant", ID: "fake_bucket"}
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 5}, {Subject: bucket, Lines: 5}, {Subject: other, Lines: 5}}); err != nil {
+		t.Fatalf("unexpected error acquiring %v", err)
+	}
+	if expected, got := int64(5), l.Usage(other).Lines; expected != got {
+		t.Errorf("other tenant bucket used lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItResetsDailyQuotasOnNextUTCDay(t *testing.T) {
+	l, clock := newFakeLimiter(&Policy{APIKeys: Rules{Default: Limit{DailyLines: 10, DailyBytes: 100}}})
+	key := Subject{Kind: KindAPIKey, ID: "fake_key"}
+
+	release, err := l.Acquire([]Cost{{Subject: key, Lines: 10, Bytes: 50}})
+	if err != nil {
+		t.Fatalf("unexpected error acquiring %v", err)
+	}
+
+	_, err = l.Acquire([]Cost{{Subject: key, Lines: 1, Bytes: 1}})
+	var exc *Exceeded
+	if !errors.As(err, &exc) || exc.Reason != ReasonDailyLines {
+		t.Fatalf("unexpected error acquiring over daily lines, got %v", err)
+	}
+	if expected, got := time.Hour*12, exc.RetryAfter; expected != got {
+		t.Errorf("retry delays do not match, expected %v got %v", expected, got)
+	}
+
+	release()
+	if _, err := l.Acquire([]Cost{{Subject: key, Lines: 1, Bytes: 60}}); err != nil {
+		t.Fatalf("unexpected error acquiring once released %v", err)
+	}
+	if _, err := l.Acquire([]Cost{{Subject: key, Lines: 1, Bytes: 50}}); !errors.As(err, &exc) || exc.Reason != ReasonDailyBytes {
+		t.Fatalf("unexpected error acquiring over daily bytes, got %v", err)
+	}
+
+	clock.add(exc.RetryAfter)
+	if expected, got := int64(0), l.Usage(key).Lines; expected != got {
+		t.Errorf("used lines do not match, expected %d got %d", expected, got)
+	}
+	if _, err := l.Acquire([]Cost{{Subject: key, Lines: 10, Bytes: 100}}); err != nil {
+		t.Fatalf("unexpected error acquiring on next day %v", err)
+	}
+}
+
+func TestItAppliesReplacedPolicyKeepingUsage(t *testing.T) {
+	l, _ := newFakeLimiter(&Policy{})
+	principal := Subject{Kind: KindPrincipal, ID: "fake_principal"}
+
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 100}}); err != nil {
+		t.Fatalf("unexpected error acquiring unlimited %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "quota.json")
+	if err := os.WriteFile(path, []byte(`{"principals": {"overrides": {"fake_principal": {"daily_lines": 150}}}}`), 0o600); err != nil {
+		t.Fatalf("unexpected error writing policy %v", err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading policy %v", err)
+	}
+	l.SetPolicy(p)
+
+	if _, err := l.Acquire([]Cost{{Subject: principal, Lines: 51}}); !errors.Is(err, ErrExhausted) {
+		t.Errorf("unexpected error acquiring over replaced policy, got %v", err)
+	}
+	if expected, got := int64(150), l.Usage(principal).Limit.DailyLines; expected != got {
+		t.Errorf("daily lines limits do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItRefusesInvalidPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	if err := os.WriteFile(path, []byte(`{"buckets": {"default": {"rate": -1}}}`), 0o600); err != nil {
+		t.Fatalf("unexpected error writing policy %v", err)
+	}
+
+	if _, err := LoadPolicy(path); !errors.Is(err, ErrInvalidLimit) {
+		t.Errorf("unexpected error loading policy, got %v", err)
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) add(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newFakeLimiter(p *Policy) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)}
+	l := NewLimiter(p)
+	l.now = func() time.Time { return clock.now }
+
+	return l, clock
+}
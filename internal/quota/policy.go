@@ -0,0 +1,109 @@
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// Limit bounds a subject, Rate refills Burst tokens per second, each written log line takes a token and other
+// requests take one. Daily quotas bound written log lines and value bytes per UTC day. Zero values are unlimited
+type Limit struct {
+	Rate       float64 `json:"rate"`
+	Burst      int64   `json:"burst"`
+	DailyLines int64   `json:"daily_lines"`
+	DailyBytes int64   `json:"daily_bytes"`
+}
+
+// burst returns token bucket capacity, it defaults to a second of rate
+func (l Limit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	if l.Rate < 1 {
+		return 1
+	}
+
+	return l.Rate
+}
+
+func (l Limit) validate() error {
+	if l.Rate < 0 || l.Burst < 0 || l.DailyLines < 0 || l.DailyBytes < 0 {
+		return fmt.Errorf("negative values on %+v, error %w", l, ErrInvalidLimit)
+	}
+
+	return nil
+}
+
+// Rules holds a subject kind default limit and its overrides by subject id
+type Rules struct {
+	Default   Limit            `json:"default"`
+	Overrides map[string]Limit `json:"overrides"`
+}
+
+// Limit returns id override, default limit otherwise
+func (r Rules) Limit(id string) Limit {
+	if l, ok := r.Overrides[id]; ok {
+		return l
+	}
+
+	return r.Default
+}
+
+func (r Rules) validate() error {
+	if err := r.Default.validate(); err != nil {
+		return err
+	}
+	for id, l := range r.Overrides {
+		if err := l.validate(); err != nil {
+			return fmt.Errorf("override %s, error %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// Policy holds limits by subject kind, zero policy is unlimited
+type Policy struct {
+	Principals Rules `json:"principals"`
+	APIKeys    Rules `json:"api_keys"`
+	Buckets    Rules `json:"buckets"`
+}
+
+// Limit returns subject limit
+func (p *Policy) Limit(s Subject) Limit {
+	switch s.Kind {
+	case KindPrincipal:
+		return p.Principals.Limit(s.ID)
+	case KindAPIKey:
+		return p.APIKeys.Limit(s.ID)
+	case KindBucket:
+		return p.Buckets.Limit(s.ID)
+	}
+
+	return Limit{}
+}
+
+// LoadPolicy reads a json policy file
+func LoadPolicy(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read quota policy %s, error %w", path, err)
+	}
+
+	p := &Policy{}
+	if err := json.Unmarshal(raw, p); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal quota policy %s, error %w", path, err)
+	}
+
+	for kind, r := range map[string]Rules{KindPrincipal: p.Principals, KindAPIKey: p.APIKeys, KindBucket: p.Buckets} {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("quota policy %s %s rules, error %w", path, kind, err)
+		}
+	}
+
+	return p, nil
+}
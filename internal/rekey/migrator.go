@@ -0,0 +1,308 @@
+package rekey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// DefaultBatchSize defines how many log lines are rewritten on each transaction
+const DefaultBatchSize = 100
+
+var ErrSignedLine = errors.New("signed log line requires a server signing key")
+
+// storage lists buckets and keys to migrate, and deletes migrated legacy keys. Key buckets are found from bucket
+// sorted sets, so lines keep its bucket even when it has no metadata
+type storage interface {
+	ListBuckets(ctx context.Context) ([]*service.Bucket, error)
+	ScanKeys(ctx context.Context, fn func(key string) error) error
+	ScanKeyBuckets(ctx context.Context, fn func(key, bucket string) error) error
+	DeleteLogLines(ctx context.Context, keys []string) error
+}
+
+// Move describes a legacy key rewritten on the versioned encoding
+type Move struct {
+	Bucket string
+	From   string
+	To     string
+}
+
+// Report summarizes a migration, Reused counts legacy keys whose rewritten line was already stored.
+// Left lists legacy keys remaining once migrated, as keys without source and creation time
+type Report struct {
+	Migrated int
+	Reused   int
+	Moves    []*Move
+	Left     []string
+}
+
+// Migrator rewrites log lines legacy keys on the versioned encoding. Lines are read and written through
+// the repository, so encrypted values and signatures get rebuilt on its new key, and legacy keys are deleted once
+// its line is stored, so interrupted migrations resume by running them again
+type Migrator struct {
+	repository   service.Repository
+	storage      storage
+	batchSize    int
+	refuseSigned bool
+}
+
+// NewMigrator instantiates migrator rewriting lines in batches
+func NewMigrator(r service.Repository, s storage, batchSize int) *Migrator {
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Migrator{
+		repository: r,
+		storage:    s,
+		batchSize:  batchSize,
+	}
+}
+
+// WithoutServerSigning refuses signed legacy lines. Rewritten lines are signed by the server key on its new key,
+// signatures are bound to its key, so without server key signed lines would end up unsigned
+func (m *Migrator) WithoutServerSigning() *Migrator {
+	m.refuseSigned = true
+	return m
+}
+
+// Migrate rewrites all legacy keys, on dry run moves are just reported. Bucket lines are rewritten first,
+// so they keep its bucket, buckets are listed from its metadata and from legacy keys sorted set entries. Then legacy
+// keys found by a key scan without sorted set entries are rewritten without bucket. Legacy keys on a bucket not
+// exporting them are left as they are, so no line loses its bucket
+func (m *Migrator) Migrate(ctx context.Context, dryRun bool) (*Report, error) {
+	buckets, err := m.storage.ListBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list buckets, error %w", err)
+	}
+
+	keyBuckets, err := m.legacyKeyBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	listed := map[string]struct{}{}
+	for _, b := range buckets {
+		names = append(names, b.Name)
+		listed[b.Name] = struct{}{}
+	}
+	for _, b := range keyBuckets {
+		if _, ok := listed[b]; !ok {
+			names = append(names, b)
+			listed[b] = struct{}{}
+		}
+	}
+
+	mg := &migration{
+		dryRun: dryRun,
+		report: &Report{},
+		moved:  map[string]struct{}{},
+		taken:  map[string]struct{}{},
+	}
+	rep := mg.report
+	for _, name := range names {
+		legacy := []*service.ExportedLogLine{}
+		err := m.repository.ExportByBucket(ctx, name, time.Time{}, time.Time{}, func(e *service.ExportedLogLine) error {
+			if linekey.IsLegacy(e.Key) {
+				legacy = append(legacy, e)
+			}
+			return nil
+		})
+		if err != nil {
+			return rep, fmt.Errorf("unable to scan bucket %s, error %w", name, err)
+		}
+
+		if err := m.migrate(ctx, mg, legacy); err != nil {
+			return rep, err
+		}
+	}
+
+	keys, err := m.legacyKeys(ctx)
+	if err != nil {
+		return rep, err
+	}
+	unbucketed := []*service.ExportedLogLine{}
+	for _, k := range keys {
+		if _, ok := mg.moved[k]; ok {
+			continue
+		}
+		// bucket lines not exported by its bucket can not be rewritten keeping it
+		if _, ok := keyBuckets[k]; ok {
+			continue
+		}
+		l, err := m.repository.GetByKey(ctx, k)
+		// expired lines are left for retention to prune
+		if errors.Is(err, service.ErrLogLineNotFound) {
+			continue
+		}
+		if err != nil {
+			return rep, fmt.Errorf("unable to get key %s, error %w", k, err)
+		}
+		unbucketed = append(unbucketed, &service.ExportedLogLine{Key: k, Value: l.Value()})
+	}
+	if err := m.migrate(ctx, mg, unbucketed); err != nil {
+		return rep, err
+	}
+
+	if !dryRun {
+		if keys, err = m.legacyKeys(ctx); err != nil {
+			return rep, err
+		}
+	}
+	for _, k := range keys {
+		if _, ok := mg.moved[k]; !ok || !dryRun {
+			rep.Left = append(rep.Left, k)
+		}
+	}
+
+	return rep, nil
+}
+
+// legacyKeys returns stored keys without versioned encoding
+func (m *Migrator) legacyKeys(ctx context.Context) ([]string, error) {
+	keys := []string{}
+	err := m.storage.ScanKeys(ctx, func(key string) error {
+		if linekey.IsLegacy(key) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan legacy keys, error %w", err)
+	}
+
+	return keys, nil
+}
+
+// legacyKeyBuckets returns legacy keys bucket from its sorted set entries
+func (m *Migrator) legacyKeyBuckets(ctx context.Context) (map[string]string, error) {
+	buckets := map[string]string{}
+	err := m.storage.ScanKeyBuckets(ctx, func(key, bucket string) error {
+		if linekey.IsLegacy(key) {
+			buckets[key] = bucket
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan legacy keys buckets, error %w", err)
+	}
+
+	return buckets, nil
+}
+
+// migration holds Migrate run state, legacy keys already moved and rewritten keys taken by the run
+type migration struct {
+	dryRun bool
+	report *Report
+	moved  map[string]struct{}
+	taken  map[string]struct{}
+}
+
+// migrate rewrites legacy lines in batches, keys without source and creation time are left as they are
+func (m *Migrator) migrate(ctx context.Context, mg *migration, legacy []*service.ExportedLogLine) error {
+	lines := []*service.LogLine{}
+	from := []string{}
+	for _, e := range legacy {
+		if err := m.checkSigned(ctx, e.Key); err != nil {
+			return err
+		}
+		line, stored, err := m.rewrite(ctx, e, mg.taken)
+		if errors.Is(err, linekey.ErrInvalidKey) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		mg.moved[e.Key] = struct{}{}
+		mg.report.Moves = append(mg.report.Moves, &Move{Bucket: e.Bucket, From: e.Key, To: string(line.Key())})
+		if stored {
+			mg.report.Reused++
+		} else {
+			mg.report.Migrated++
+			lines = append(lines, line)
+		}
+		from = append(from, e.Key)
+
+		if len(from) >= m.batchSize {
+			if err := m.store(ctx, lines, from, mg.dryRun); err != nil {
+				return err
+			}
+			lines, from = []*service.LogLine{}, []string{}
+		}
+	}
+
+	return m.store(ctx, lines, from, mg.dryRun)
+}
+
+// checkSigned fails on signed legacy lines when they are refused
+func (m *Migrator) checkSigned(ctx context.Context, key string) error {
+	if !m.refuseSigned {
+		return nil
+	}
+
+	l, err := m.repository.GetByKey(ctx, key)
+	if errors.Is(err, service.ErrLogLineNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get key %s, error %w", key, err)
+	}
+	if l.Signature() != nil {
+		return fmt.Errorf("unable to rewrite key %s, error %w", key, ErrSignedLine)
+	}
+
+	return nil
+}
+
+// rewrite returns exported line on its versioned key, lines sharing source and creation time with an already
+// stored one get the next free sequence. Stored is true when the line was rewritten by an interrupted migration
+func (m *Migrator) rewrite(ctx context.Context, e *service.ExportedLogLine, taken map[string]struct{}) (*service.LogLine, bool, error) {
+	k, err := linekey.Decode(e.Key)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode key %s, error %w", e.Key, err)
+	}
+
+	for {
+		line := service.NewKeyedLogLine(k, e.Bucket, string(e.Value))
+		key := string(line.Key())
+		if _, ok := taken[key]; ok {
+			k.Sequence++
+			continue
+		}
+
+		l, err := m.repository.GetByKey(ctx, key)
+		if err != nil && !errors.Is(err, service.ErrLogLineNotFound) {
+			return nil, false, fmt.Errorf("unable to get key %s, error %w", key, err)
+		}
+		if err == nil && string(l.Value()) != string(e.Value) {
+			k.Sequence++
+			continue
+		}
+		taken[key] = struct{}{}
+
+		return line, err == nil, nil
+	}
+}
+
+// store writes rewritten lines and deletes its legacy keys afterwards
+func (m *Migrator) store(ctx context.Context, lines []*service.LogLine, from []string, dryRun bool) error {
+	if dryRun || len(from) == 0 {
+		return nil
+	}
+
+	if len(lines) > 0 {
+		if err := m.repository.AddBatch(ctx, lines); err != nil {
+			return fmt.Errorf("unable to store %d rewritten log lines, error %w", len(lines), err)
+		}
+	}
+
+	if err := m.storage.DeleteLogLines(ctx, from); err != nil {
+		return fmt.Errorf("unable to delete %d legacy keys, error %w", len(from), err)
+	}
+
+	return nil
+}
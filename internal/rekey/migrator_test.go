@@ -0,0 +1,273 @@
+package rekey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItRewritesLegacyKeysDeletingThemOnceStored(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("fake_bucket", "app_1659469108710408961", "fake value a")
+	fr.add("fake_bucket", "app_worker_1659469108710408961", "fake value b")
+	kept := linekey.Encode(linekey.Key{Source: "app", Time: time.Unix(0, 1659469108710409242)})
+	fr.add("fake_bucket", kept, "fake value c")
+
+	rep, err := NewMigrator(fr, fr, 1).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	if expected, got := 2, rep.Migrated; expected != got {
+		t.Errorf("migrated keys do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := 3, len(fr.lines); expected != got {
+		t.Errorf("stored keys do not match, expected %d got %d", expected, got)
+	}
+
+	key := linekey.Encode(linekey.Key{Source: "app_worker", Time: time.Unix(0, 1659469108710408961)})
+	l, ok := fr.lines[key]
+	if !ok {
+		t.Fatalf("expected rewritten key %s", key)
+	}
+	if expected, got := "fake value b", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if _, ok := fr.lines["app_worker_1659469108710408961"]; ok {
+		t.Error("expected legacy key deleted")
+	}
+}
+
+func TestItTakesNextSequenceOnRewrittenKeysAlreadyTaken(t *testing.T) {
+	ts := time.Unix(0, 1659469108710408961)
+	fr := newFakeRepository()
+	fr.add("fake_bucket", linekey.Encode(linekey.Key{Source: "app", Time: ts}), "another value")
+	fr.add("fake_bucket", "app_1659469108710408961", "fake value")
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	if expected, got := linekey.Encode(linekey.Key{Source: "app", Time: ts, Sequence: 1}), rep.Moves[0].To; expected != got {
+		t.Errorf("rewritten keys do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItResumesInterruptedMigrationsReusingStoredLines(t *testing.T) {
+	ts := time.Unix(0, 1659469108710408961)
+	fr := newFakeRepository()
+	fr.add("fake_bucket", linekey.Encode(linekey.Key{Source: "app", Time: ts}), "fake value")
+	fr.add("fake_bucket", "app_1659469108710408961", "fake value")
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	if expected, got := 1, rep.Reused; expected != got {
+		t.Errorf("reused keys do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := 1, len(fr.lines); expected != got {
+		t.Errorf("stored keys do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItReportsMovesWithoutWritingOnDryRun(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("fake_bucket", "app_1659469108710408961", "fake value")
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	if expected, got := 1, len(rep.Moves); expected != got {
+		t.Fatalf("moves do not match, expected %d got %d", expected, got)
+	}
+	if _, ok := fr.lines["app_1659469108710408961"]; !ok {
+		t.Error("expected legacy key kept on dry run")
+	}
+}
+
+func TestItRewritesLegacyKeysWithoutBucketReportingKeysLeft(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("", "app_1659469108710408961", "fake value")
+	fr.add("", "app", "fake undecodable value")
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	key := linekey.Encode(linekey.Key{Source: "app", Time: time.Unix(0, 1659469108710408961)})
+	if _, ok := fr.lines[key]; !ok {
+		t.Errorf("expected rewritten key %s", key)
+	}
+	if expected, got := 1, rep.Migrated; expected != got {
+		t.Errorf("migrated keys do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := []string{"app"}, rep.Left; len(expected) != len(got) || expected[0] != got[0] {
+		t.Errorf("keys left do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItRefusesSignedLegacyLinesWithoutServerSigning(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("fake_bucket", "app_1659469108710408961", "fake value")
+	fr.lines["app_1659469108710408961"].WithSignature(&service.Signature{KeyID: "fake_key", Value: []byte("fake signature")})
+
+	_, err := NewMigrator(fr, fr, 10).WithoutServerSigning().Migrate(context.Background(), false)
+	if !errors.Is(err, ErrSignedLine) {
+		t.Fatalf("unexpected error migrating signed line, got %v", err)
+	}
+
+	if _, ok := fr.lines["app_1659469108710408961"]; !ok {
+		t.Errorf("expected legacy key kept")
+	}
+	if expected, got := 1, len(fr.lines); expected != got {
+		t.Errorf("stored lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItKeepsBucketOfLegacyLinesOnBucketsWithoutMetadata(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("unlisted_bucket", "app_1659469108710408961", "fake value")
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	key := linekey.Encode(linekey.Key{Source: "app", Time: time.Unix(0, 1659469108710408961)})
+	l, ok := fr.lines[key]
+	if !ok {
+		t.Fatalf("expected rewritten key %s", key)
+	}
+	if expected, got := "unlisted_bucket", l.Bucket(); expected != got {
+		t.Errorf("buckets do not match, expected %s got %s", expected, got)
+	}
+	if _, ok := fr.lines["app_1659469108710408961"]; ok {
+		t.Error("expected legacy key deleted")
+	}
+	if expected, got := 0, len(rep.Left); expected != got {
+		t.Errorf("keys left do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItLeavesLegacyKeysNotExportedByItsBucket(t *testing.T) {
+	fr := newFakeRepository()
+	fr.add("", "app_1659469108710408961", "fake value")
+	fr.zsets["app_1659469108710408961"] = "pruned_bucket"
+
+	rep, err := NewMigrator(fr, fr, 10).Migrate(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error migrating, error %v", err)
+	}
+
+	if _, ok := fr.lines["app_1659469108710408961"]; !ok {
+		t.Error("expected legacy key kept")
+	}
+	if expected, got := 1, len(fr.lines); expected != got {
+		t.Errorf("stored lines do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := []string{"app_1659469108710408961"}, rep.Left; len(expected) != len(got) || expected[0] != got[0] {
+		t.Errorf("keys left do not match, expected %v got %v", expected, got)
+	}
+}
+
+type fakeRepository struct {
+	service.Repository
+	lines map[string]*service.LogLine
+	order []string
+	zsets map[string]string
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{lines: map[string]*service.LogLine{}, zsets: map[string]string{}}
+}
+
+func (f *fakeRepository) add(bucket, key, value string) {
+	f.lines[key] = service.NewLogLineWithBucket(bucket, key, value, time.Now())
+	f.order = append(f.order, key)
+	if bucket != "" {
+		f.zsets[key] = bucket
+	}
+}
+
+func (f *fakeRepository) ScanKeyBuckets(ctx context.Context, fn func(key, bucket string) error) error {
+	for _, k := range f.order {
+		b, ok := f.zsets[k]
+		if !ok {
+			continue
+		}
+		if err := fn(k, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepository) ListBuckets(ctx context.Context) ([]*service.Bucket, error) {
+	return []*service.Bucket{{Name: "fake_bucket"}}, nil
+}
+
+func (f *fakeRepository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	for _, k := range f.order {
+		l, ok := f.lines[k]
+		if !ok || l.Bucket() != bucket {
+			continue
+		}
+		if err := fn(&service.ExportedLogLine{Key: k, Bucket: bucket, Value: l.Value()}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepository) ScanKeys(ctx context.Context, fn func(key string) error) error {
+	for _, k := range f.order {
+		if _, ok := f.lines[k]; !ok {
+			continue
+		}
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	l, ok := f.lines[key]
+	if !ok {
+		return nil, service.ErrLogLineNotFound
+	}
+
+	return l, nil
+}
+
+func (f *fakeRepository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	for _, l := range lines {
+		if _, ok := f.lines[string(l.Key())]; ok {
+			return errors.New("fake key already exists")
+		}
+		f.lines[string(l.Key())] = l
+	}
+
+	return nil
+}
+
+func (f *fakeRepository) DeleteLogLines(ctx context.Context, keys []string) error {
+	for _, k := range keys {
+		delete(f.lines, k)
+	}
+
+	return nil
+}
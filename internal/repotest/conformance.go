@@ -0,0 +1,437 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// Repository is a storage backend, it stores log lines and buckets metadata
+type Repository interface {
+	service.Repository
+	service.BucketRepository
+}
+
+// Run checks storage backend behaves as service expects, every backend must pass it.
+// Repository may hold previous data, each run writes its own keys and buckets
+func Run(t *testing.T, r Repository) {
+	ns := fmt.Sprintf("conformance%d_", time.Now().UnixNano())
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repository, ns string)
+	}{
+		{"AddsAndGetsLogLinesByKey", addsAndGetsLogLinesByKey},
+		{"CountsNewLogLinesOnce", countsNewLogLinesOnce},
+		{"CreatesOnlyNewLogLines", createsOnlyNewLogLines},
+		{"KeepsLogLineRevisions", keepsLogLineRevisions},
+		{"GetsHistoriesInKeysOrder", getsHistoriesInKeysOrder},
+		{"RecordsRevisionWriters", recordsRevisionWriters},
+		{"GetsLogLinesByPrefixSortedByKey", getsLogLinesByPrefixSortedByKey},
+		{"GetsBucketLogLinesSortedByCreationTime", getsBucketLogLinesSortedByCreationTime},
+		{"ExportsBucketLogLinesOnTimeRange", exportsBucketLogLinesOnTimeRange},
+		{"GetsLastNLogLinesNewestFirst", getsLastNLogLinesNewestFirst},
+		{"ReadsLogLinesAsTheyWereOnPastTransactions", readsLogLinesAsTheyWereOnPastTransactions},
+		{"StoresBuckets", storesBuckets},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, r, fmt.Sprintf("%s%d_", ns, i))
+		})
+	}
+}
+
+func addsAndGetsLogLinesByKey(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "fake value", time.Now()))
+
+	l, err := r.GetByKey(ctx, ns+"foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if _, err := r.GetByKey(ctx, ns+"unknown"); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting unknown key, got %v", err)
+	}
+}
+
+func countsNewLogLinesOnce(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	before := count(t, r)
+
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "fake value", time.Now()))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "fake value X", time.Now()))
+	err := r.AddBatch(ctx, []*service.LogLine{
+		service.NewLogLineWithBucket(ns+"bucket", ns+"bar", "fake value", time.Now()),
+		service.NewLogLineWithBucket(ns+"bucket", ns+"zoo", "fake value", time.Now()),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error adding batch, error %v", err)
+	}
+
+	if expected, got := before+3, count(t, r); expected != got {
+		t.Errorf("counters do not match, expected %d got %d", expected, got)
+	}
+}
+
+func createsOnlyNewLogLines(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	before := count(t, r)
+
+	err := r.CreateBatch(ctx, []*service.LogLine{
+		service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "fake value", time.Now()),
+		service.NewLogLineWithBucket(ns+"bucket", ns+"bar", "fake value", time.Now()),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating batch, error %v", err)
+	}
+
+	err = r.CreateBatch(ctx, []*service.LogLine{
+		service.NewLogLineWithBucket(ns+"bucket", ns+"zoo", "fake value", time.Now()),
+		service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "fake value X", time.Now()),
+	})
+	if !errors.Is(err, service.ErrLogLineExists) {
+		t.Fatalf("unexpected error creating stored key, got %v", err)
+	}
+
+	l, err := r.GetByKey(ctx, ns+"foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if _, err := r.GetByKey(ctx, ns+"zoo"); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting refused batch key, got %v", err)
+	}
+	if expected, got := before+2, count(t, r); expected != got {
+		t.Errorf("log lines count do not match, expected %d got %d", expected, got)
+	}
+}
+
+func keepsLogLineRevisions(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	for _, v := range []string{"a", "b", "c"} {
+		add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", v, time.Now()))
+	}
+
+	h, err := r.History(ctx, ns+"foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting history, error %v", err)
+	}
+	if expected, got := 3, len(h.Revision); expected != got {
+		t.Fatalf("revisions do not match, expected %d got %d", expected, got)
+	}
+	for i, v := range []string{"a", "b", "c"} {
+		rv := h.Revision[i]
+		if expected, got := v, string(rv.Value); expected != got {
+			t.Errorf("values do not match, expected %s got %s", expected, got)
+		}
+		if expected, got := uint64(i+1), rv.Revision; expected != got {
+			t.Errorf("revisions do not match, expected %d got %d", expected, got)
+		}
+		if i > 0 && rv.Tx <= h.Revision[i-1].Tx {
+			t.Errorf("revision %d tx %d not after previous tx %d", rv.Revision, rv.Tx, h.Revision[i-1].Tx)
+		}
+	}
+
+	l, err := r.GetByKey(ctx, ns+"foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "c", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	if _, err := r.History(ctx, ns+"unknown"); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting unknown key history, got %v", err)
+	}
+}
+
+func getsHistoriesInKeysOrder(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	for _, v := range []string{"a", "b"} {
+		add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", v, time.Now()))
+	}
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"bar", "c", time.Now()))
+
+	hs, err := r.Histories(ctx, []string{ns + "foo", ns + "bar"})
+	if err != nil {
+		t.Fatalf("unexpected error getting histories, error %v", err)
+	}
+	if expected, got := 2, len(hs); expected != got {
+		t.Fatalf("histories do not match, expected %d got %d", expected, got)
+	}
+
+	for i, expected := range [][]string{{"a", "b"}, {"c"}} {
+		h := hs[i]
+		if len(expected) != len(h.Revision) {
+			t.Fatalf("key %s revisions do not match, expected %d got %d", h.Key, len(expected), len(h.Revision))
+		}
+		for j, v := range expected {
+			if got := string(h.Revision[j].Value); v != got {
+				t.Errorf("key %s values do not match, expected %s got %s", h.Key, v, got)
+			}
+			if expected, got := uint64(j+1), h.Revision[j].Revision; expected != got {
+				t.Errorf("key %s revisions do not match, expected %d got %d", h.Key, expected, got)
+			}
+		}
+	}
+	if expected, got := ns+"bar", hs[1].Key; expected != got {
+		t.Errorf("keys do not match, expected %s got %s", expected, got)
+	}
+
+	if _, err := r.Histories(ctx, []string{ns + "foo", ns + "unknown"}); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting unknown key histories, got %v", err)
+	}
+}
+
+func recordsRevisionWriters(t *testing.T, r Repository, ns string) {
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: ns + "writer"})
+	if err := r.Add(ctx, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "a", time.Now())); err != nil {
+		t.Fatalf("unexpected error adding line, error %v", err)
+	}
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo", "b", time.Now()))
+
+	h, err := r.RevisionHistory(ctx, ns+"foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting revision history, error %v", err)
+	}
+	if expected, got := 2, len(h.Revision); expected != got {
+		t.Fatalf("revisions do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := ns+"writer", h.Revision[0].Principal; expected != got {
+		t.Errorf("principals do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := "", h.Revision[1].Principal; expected != got {
+		t.Errorf("principals do not match, expected %s got %s", expected, got)
+	}
+	for _, rv := range h.Revision {
+		if rv.CommittedAt.IsZero() || rv.CommittedAt.After(time.Now()) {
+			t.Errorf("unexpected revision %d commit time %v", rv.Revision, rv.CommittedAt)
+		}
+	}
+}
+
+func getsLogLinesByPrefixSortedByKey(t *testing.T, r Repository, ns string) {
+	for _, k := range []string{"foo_2", "foo_0", "bar_0", "foo_1"} {
+		add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+k, "fake value "+k, time.Now()))
+	}
+
+	ll, err := r.GetByPrefix(context.Background(), ns+"foo_")
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	assertKeys(t, ns, ll, "foo_0", "foo_1", "foo_2")
+	if expected, got := "fake value foo_0", string(ll[0].Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func getsBucketLogLinesSortedByCreationTime(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	now := time.Now()
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"b", "fake value", now.Add(time.Second)))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"a", "fake value", now.Add(time.Second*2)))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"c", "fake value", now))
+	add(t, r, service.NewLogLineWithBucket(ns+"other", ns+"d", "fake value", now))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"a", "fake value X", now))
+
+	ll, err := r.GetByBucket(ctx, ns+"bucket")
+	if err != nil {
+		t.Fatalf("unexpected error getting by bucket, error %v", err)
+	}
+	assertKeys(t, ns, ll, "c", "b", "a")
+	if expected, got := "fake value X", string(ll[2].Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+
+	total, err := r.CountBucketLines(ctx, ns+"bucket")
+	if err != nil {
+		t.Fatalf("unexpected error counting bucket lines, error %v", err)
+	}
+	if expected, got := uint64(3), total; expected != got {
+		t.Errorf("bucket lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func exportsBucketLogLinesOnTimeRange(t *testing.T, r Repository, ns string) {
+	ts := time.Date(2022, 8, 31, 12, 0, 0, 123456000, time.UTC)
+	for i := 0; i < 4; i++ {
+		add(t, r, service.NewLogLineWithBucket(ns+"bucket", fmt.Sprintf("%sfoo_%d", ns, i), fmt.Sprintf("fake value %d", i), ts.Add(time.Hour*time.Duration(i))))
+	}
+
+	res := []*service.ExportedLogLine{}
+	err := r.ExportByBucket(context.Background(), ns+"bucket", ts.Add(time.Hour), ts.Add(time.Hour*2), func(l *service.ExportedLogLine) error {
+		res = append(res, l)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error exporting bucket, error %v", err)
+	}
+
+	if expected, got := 2, len(res); expected != got {
+		t.Fatalf("exported lines do not match, expected %d got %d", expected, got)
+	}
+	for i, l := range res {
+		if expected, got := fmt.Sprintf("%sfoo_%d", ns, i+1), l.Key; expected != got {
+			t.Errorf("keys do not match, expected %s got %s", expected, got)
+		}
+		if expected, got := ns+"bucket", l.Bucket; expected != got {
+			t.Errorf("buckets do not match, expected %s got %s", expected, got)
+		}
+		if expected, got := fmt.Sprintf("fake value %d", i+1), string(l.Value); expected != got {
+			t.Errorf("values do not match, expected %s got %s", expected, got)
+		}
+		if expected, got := ts.Add(time.Hour*time.Duration(i+1)), l.CreatedAt; !expected.Equal(got) {
+			t.Errorf("creation times do not match, expected %v got %v", expected, got)
+		}
+		if l.Tx == 0 || l.Revision != 1 {
+			t.Errorf("unexpected tx %d revision %d", l.Tx, l.Revision)
+		}
+	}
+}
+
+func getsLastNLogLinesNewestFirst(t *testing.T, r Repository, ns string) {
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_0", "fake value", time.Now()))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_1", "fake value", time.Now()))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_2", "fake value", time.Now()))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_0", "fake value X", time.Now()))
+
+	ll, err := r.GetLastNLogLines(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error getting last lines, error %v", err)
+	}
+	assertKeys(t, ns, ll, "foo_0", "foo_2", "foo_1")
+	if expected, got := "fake value X", string(ll[0].Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func readsLogLinesAsTheyWereOnPastTransactions(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	ts := time.Now()
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_0", "fake value", ts))
+
+	tx, err := r.TxAt(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error resolving tx, error %v", err)
+	}
+	if tx == 0 {
+		t.Fatal("unexpected empty tx")
+	}
+
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_0", "fake value X", ts))
+	add(t, r, service.NewLogLineWithBucket(ns+"bucket", ns+"foo_1", "fake value", ts))
+
+	l, err := r.GetByKeyAt(ctx, ns+"foo_0", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting key, error %v", err)
+	}
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if _, err := r.GetByKeyAt(ctx, ns+"foo_1", tx); !errors.Is(err, service.ErrLogLineNotFound) {
+		t.Errorf("unexpected error getting key written later, got %v", err)
+	}
+
+	byPrefix, err := r.GetByPrefixAt(ctx, ns+"foo_", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix, error %v", err)
+	}
+	byBucket, err := r.GetByBucketAt(ctx, ns+"bucket", tx)
+	if err != nil {
+		t.Fatalf("unexpected error getting by bucket, error %v", err)
+	}
+	for _, ll := range [][]*service.LogLine{byPrefix, byBucket} {
+		assertKeys(t, ns, ll, "foo_0")
+		if expected, got := "fake value", string(ll[0].Value()); expected != got {
+			t.Errorf("values do not match, expected %s got %s", expected, got)
+		}
+	}
+
+	first, err := r.TxAt(ctx, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error resolving tx, error %v", err)
+	}
+	if expected, got := uint64(0), first; expected != got {
+		t.Errorf("txs do not match, expected %d got %d", expected, got)
+	}
+}
+
+func storesBuckets(t *testing.T, r Repository, ns string) {
+	ctx := context.Background()
+	b := &service.Bucket{Name: ns + "bucket", Owner: "fake owner", Retention: time.Hour, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	if err := r.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+	if err := r.CreateBucket(ctx, b); !errors.Is(err, service.ErrBucketAlreadyExists) {
+		t.Errorf("unexpected error creating duplicated bucket, got %v", err)
+	}
+
+	b.Description = "fake description"
+	if err := r.UpdateBucket(ctx, b); err != nil {
+		t.Fatalf("unexpected error updating bucket, error %v", err)
+	}
+
+	got, err := r.GetBucket(ctx, ns+"bucket")
+	if err != nil {
+		t.Fatalf("unexpected error getting bucket, error %v", err)
+	}
+	if got.Owner != b.Owner || got.Description != b.Description || got.Retention != b.Retention || !got.CreatedAt.Equal(b.CreatedAt) {
+		t.Errorf("buckets do not match, expected %+v got %+v", b, got)
+	}
+
+	if _, err := r.GetBucket(ctx, ns+"unknown"); !errors.Is(err, service.ErrBucketNotFound) {
+		t.Errorf("unexpected error getting unknown bucket, got %v", err)
+	}
+
+	all, err := r.ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing buckets, error %v", err)
+	}
+	found := false
+	for _, l := range all {
+		found = found || l.Name == b.Name
+	}
+	if !found {
+		t.Errorf("bucket %s not listed", b.Name)
+	}
+}
+
+func add(t *testing.T, r Repository, l *service.LogLine) {
+	t.Helper()
+	if err := r.Add(context.Background(), l); err != nil {
+		t.Fatalf("unexpected error adding line %s, error %v", string(l.Key()), err)
+	}
+}
+
+func count(t *testing.T, r Repository) uint64 {
+	t.Helper()
+	c, err := r.Count(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error counting lines, error %v", err)
+	}
+	return c
+}
+
+func assertKeys(t *testing.T, ns string, ll []*service.LogLine, keys ...string) {
+	t.Helper()
+	if expected, got := len(keys), len(ll); expected != got {
+		t.Fatalf("lines do not match, expected %d got %d", expected, got)
+	}
+	for i, k := range keys {
+		if expected, got := ns+k, string(ll[i].Key()); expected != got {
+			t.Errorf("keys do not match, expected %s got %s", expected, got)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forever keeps log lines with no expiration
+const Forever time.Duration = 0
+
+const foreverLabel = "forever"
+
+var ErrInvalidPolicy = errors.New("invalid retention policy")
+
+// Policies keeps per bucket log lines max age, buckets without policy are kept forever
+type Policies struct {
+	mutex  sync.RWMutex
+	maxAge map[string]time.Duration
+}
+
+// NewPolicies instantiates an empty retention policy set
+func NewPolicies() *Policies {
+	return &Policies{
+		maxAge: map[string]time.Duration{},
+	}
+}
+
+// Parse builds policies from its raw definition, as "debug=7d,audit=forever"
+func Parse(raw string) (*Policies, error) {
+	p := NewPolicies()
+	if strings.TrimSpace(raw) == "" {
+		return p, nil
+	}
+
+	for _, def := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(def), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("unable to parse policy %q, error %w", def, ErrInvalidPolicy)
+		}
+
+		d, err := ParseMaxAge(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		p.Set(parts[0], d)
+	}
+
+	return p, nil
+}
+
+// ParseMaxAge parses retention max age, accepts Go durations, days as "7d" and "forever"
+func ParseMaxAge(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == foreverLabel || raw == "" {
+		return Forever, nil
+	}
+
+	if strings.HasSuffix(raw, "d") {
+		days, err := strconv.ParseUint(strings.TrimSuffix(raw, "d"), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse max age %q, error %w", raw, ErrInvalidPolicy)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("unable to parse max age %q, error %w", raw, ErrInvalidPolicy)
+	}
+
+	return d, nil
+}
+
+// FormatMaxAge returns max age human representation
+func FormatMaxAge(d time.Duration) string {
+	if d == Forever {
+		return foreverLabel
+	}
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// Set defines bucket max age, Forever removes its expiration
+func (p *Policies) Set(bucket string, maxAge time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if maxAge == Forever {
+		delete(p.maxAge, bucket)
+		return
+	}
+	p.maxAge[bucket] = maxAge
+}
+
+// MaxAge returns bucket max age
+func (p *Policies) MaxAge(bucket string) time.Duration {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.maxAge[bucket]
+}
+
+// ExpiresAt returns when a bucket log line created at t expires, false if it never does
+func (p *Policies) ExpiresAt(bucket string, t time.Time) (time.Time, bool) {
+	d := p.MaxAge(bucket)
+	if d == Forever || t.IsZero() {
+		return time.Time{}, false
+	}
+
+	return t.Add(d), true
+}
+
+// Cutoff returns bucket oldest non expired log line time, false if bucket lines never expire
+func (p *Policies) Cutoff(bucket string, now time.Time) (time.Time, bool) {
+	d := p.MaxAge(bucket)
+	if d == Forever {
+		return time.Time{}, false
+	}
+
+	return now.Add(-d), true
+}
+
+// Buckets returns sorted buckets with expiring log lines
+func (p *Policies) Buckets() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	b := make([]string, 0, len(p.maxAge))
+	for bucket := range p.maxAge {
+		b = append(b, bucket)
+	}
+	sort.Strings(b)
+
+	return b
+}
+
+// Expired describes expired log line keys from a bucket
+type Expired struct {
+	Bucket string
+	Cutoff time.Time
+	Keys   []string
+}
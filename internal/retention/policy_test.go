@@ -0,0 +1,65 @@
+package retention
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestItParsesRetentionPolicies(t *testing.T) {
+	p, err := Parse("debug=7d, audit=forever,trace=90m")
+	if err != nil {
+		t.Fatalf("unable to parse policies, error %v", err)
+	}
+
+	if expected, got := 7*24*time.Hour, p.MaxAge("debug"); expected != got {
+		t.Errorf("max age does not match, expected %v got %v", expected, got)
+	}
+
+	if expected, got := Forever, p.MaxAge("audit"); expected != got {
+		t.Errorf("max age does not match, expected %v got %v", expected, got)
+	}
+
+	if expected, got := 90*time.Minute, p.MaxAge("trace"); expected != got {
+		t.Errorf("max age does not match, expected %v got %v", expected, got)
+	}
+
+	if expected, got := 2, len(p.Buckets()); expected != got {
+		t.Errorf("expiring buckets do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItFailsParsingInvalidPolicies(t *testing.T) {
+	for _, raw := range []string{"debug", "=7d", "debug=xd", "debug=-1h"} {
+		if _, err := Parse(raw); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("unexpected error type on %s, got %v", raw, err)
+		}
+	}
+}
+
+func TestItCalculatesBucketCutoff(t *testing.T) {
+	p := NewPolicies()
+	p.Set("debug", time.Hour)
+	now := time.Now()
+
+	c, ok := p.Cutoff("debug", now)
+	if !ok {
+		t.Fatal("expected debug cutoff")
+	}
+
+	if expected, got := now.Add(-time.Hour), c; !expected.Equal(got) {
+		t.Errorf("cutoff does not match, expected %v got %v", expected, got)
+	}
+
+	if _, ok := p.Cutoff("audit", now); ok {
+		t.Error("unexpected audit cutoff")
+	}
+}
+
+func TestItFormatsMaxAge(t *testing.T) {
+	for expected, d := range map[string]time.Duration{"forever": Forever, "7d": 7 * 24 * time.Hour, "1h30m0s": 90 * time.Minute} {
+		if got := FormatMaxAge(d); expected != got {
+			t.Errorf("format does not match, expected %s got %s", expected, got)
+		}
+	}
+}
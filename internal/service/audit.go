@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrAuditUnsupported = errors.New("storage does not prove log lines")
+
+// AuditState holds the serialized immudb state audited log lines are proven against
+type AuditState struct {
+	Database string
+	TxID     uint64
+	Signed   bool
+	State    []byte
+}
+
+// AuditedLogLine holds a stored log line with its serialized immudb inclusion and consistency proof
+type AuditedLogLine struct {
+	ExportedLogLine
+	Proof []byte
+}
+
+// AuditRepository proves stored log lines against a single immudb state, values are proven as stored
+type AuditRepository interface {
+	AuditByBucket(ctx context.Context, bucket string, from, to time.Time, state func(*AuditState) error, fn func(*AuditedLogLine) error) error
+}
+
+type AuditService struct {
+	v1.UnimplementedAuditServiceServer
+	repository AuditRepository
+}
+
+func NewAuditService(r AuditRepository) *AuditService {
+	return &AuditService{
+		repository: r,
+	}
+}
+
+// ExportAuditBundle streams audited state, then bucket log lines created on the requested time range with their proofs
+func (a *AuditService) ExportAuditBundle(req *v1.AuditBundleRequest, stream v1.AuditService_ExportAuditBundleServer) error {
+	var from, to time.Time
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return status.Error(codes.InvalidArgument, "audit range ends before its start")
+	}
+
+	state := func(s *AuditState) error {
+		return stream.Send(&v1.AuditBundleEntry{Entry: &v1.AuditBundleEntry_State{State: &v1.AuditState{
+			Database: s.Database,
+			TxId:     s.TxID,
+			Signed:   s.Signed,
+			State:    s.State,
+		}}})
+	}
+
+	err := a.repository.AuditByBucket(stream.Context(), req.GetBucket(), from, to, state, func(l *AuditedLogLine) error {
+		return stream.Send(&v1.AuditBundleEntry{Entry: &v1.AuditBundleEntry_Line{Line: &v1.AuditedLogLine{
+			Key:       l.Key,
+			Bucket:    l.Bucket,
+			Value:     string(l.Value),
+			Tx:        l.Tx,
+			Revision:  l.Revision,
+			CreatedAt: timestamppb.New(l.CreatedAt),
+			Proof:     l.Proof,
+		}}})
+	})
+	if errors.Is(err, ErrAuditUnsupported) {
+		return status.Error(codes.Unimplemented, "Audit bundles require immudb storage!")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, "Cannot audit Bucket on repository!")
+	}
+
+	return nil
+}
@@ -16,10 +16,15 @@ type signer interface {
 	Sign(ctx context.Context, j *jwt.CustomClaims) (string, error)
 }
 
+// User describes an authenticated principal, Tenants lists the tenants it is allowed to log in to
 type User struct {
-	ID string
+	ID      string
+	Tenants []string
 }
 
+// ErrTenantNotAllowed happens on logins requesting a tenant the user does not belong to
+var ErrTenantNotAllowed = errors.New("tenant not allowed")
+
 type UserRepository interface {
 	Get(ctx context.Context, user, password string) (*User, error)
 }
@@ -39,7 +44,10 @@ func NewAuth(s signer, u UserRepository) *auth {
 }
 
 func (a *auth) Login(ctx context.Context, r *v1.LoginRequest) (*v1.LoginResponse, error) {
-	t, err := a.login(ctx, r.Username, r.Password)
+	t, err := a.login(ctx, r.Username, r.Password, r.Tenant)
+	if errors.Is(err, ErrTenantNotAllowed) {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("unable to login, error %v", err))
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("unable to login, error %v", err))
 	}
@@ -47,15 +55,20 @@ func (a *auth) Login(ctx context.Context, r *v1.LoginRequest) (*v1.LoginResponse
 	return &v1.LoginResponse{Token: t}, nil
 }
 
-func (a *auth) login(ctx context.Context, email, password string) (token string, err error) {
+func (a *auth) login(ctx context.Context, email, password, tenant string) (token string, err error) {
 	u, err := a.repository.Get(ctx, email, password)
 	if err != nil {
 		return "", fmt.Errorf("unable to find email %s error %w", email, err)
 	}
 
+	if tenant != "" && !u.member(tenant) {
+		return "", fmt.Errorf("user %s tenant %s error %w", email, tenant, ErrTenantNotAllowed)
+	}
+
 	tkn, err := a.signer.Sign(ctx, &jwt.CustomClaims{
 		PrincipalID: u.ID,
 		Email:       email,
+		TenantID:    tenant,
 	})
 
 	if err != nil {
@@ -65,17 +78,30 @@ func (a *auth) login(ctx context.Context, email, password string) (token string,
 	return tkn, nil
 }
 
+func (u *User) member(tenant string) bool {
+	for _, t := range u.Tenants {
+		if t == tenant {
+			return true
+		}
+	}
+
+	return false
+}
+
 var errEmptyUserCredentials = errors.New("empty user credentials")
 
-type userFakeRepository struct{}
+type userFakeRepository struct {
+	tenants []string
+}
 
-func NewAuthFakeRepository() UserRepository {
-	return &userFakeRepository{}
+// NewAuthFakeRepository accepts any non empty credentials, its users belong to the given tenants
+func NewAuthFakeRepository(tenants ...string) UserRepository {
+	return &userFakeRepository{tenants: tenants}
 }
 
 func (a *userFakeRepository) Get(ctx context.Context, user, password string) (*User, error) {
 	if user == "" || password == "" {
 		return nil, errEmptyUserCredentials
 	}
-	return &User{ID: uuid.New().String()}, nil
+	return &User{ID: uuid.New().String(), Tenants: a.tenants}, nil
 }
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcosQuesada/log-api/internal/jwt"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItIssuesTokensOnUserTenants(t *testing.T) {
+	p := jwt.NewProcessor("fake_secret")
+	a := NewAuth(p, NewAuthFakeRepository("acme"))
+
+	res, err := a.Login(context.Background(), &v1.LoginRequest{Username: "fake_user", Password: "fake_password", Tenant: "acme"})
+	if err != nil {
+		t.Fatalf("unexpected error login, error %v", err)
+	}
+
+	c, err := p.Parse(context.Background(), res.GetToken())
+	if err != nil {
+		t.Fatalf("unexpected error parsing token, error %v", err)
+	}
+
+	if expected, got := "acme", c.TenantID; expected != got {
+		t.Errorf("tenants do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItRefusesLoginsOnTenantsUserDoesNotBelongTo(t *testing.T) {
+	a := NewAuth(jwt.NewProcessor("fake_secret"), NewAuthFakeRepository("acme"))
+
+	_, err := a.Login(context.Background(), &v1.LoginRequest{Username: "fake_user", Password: "fake_password", Tenant: "globex"})
+	if expected, got := codes.PermissionDenied, status.Code(err); expected != got {
+		t.Errorf("unexpected code, expected %v got %v", expected, got)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/retention"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrBucketNotFound      = errors.New("bucket not found")
+	ErrBucketAlreadyExists = errors.New("bucket already exists")
+	ErrBucketArchived      = errors.New("bucket archived")
+	ErrInvalidBucketName   = errors.New("invalid bucket name")
+)
+
+var bucketNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+// Bucket describes a log lines bucket
+type Bucket struct {
+	Name        string        `json:"name"`
+	Owner       string        `json:"owner"`
+	Description string        `json:"description"`
+	Retention   time.Duration `json:"retention"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Archived    bool          `json:"archived"`
+	ArchivedAt  time.Time     `json:"archived_at"`
+}
+
+// BucketRepository stores buckets metadata, bucket retention gets registered as retention policy
+type BucketRepository interface {
+	CreateBucket(ctx context.Context, b *Bucket) error
+	UpdateBucket(ctx context.Context, b *Bucket) error
+	GetBucket(ctx context.Context, name string) (*Bucket, error)
+	ListBuckets(ctx context.Context) ([]*Bucket, error)
+	CountBucketLines(ctx context.Context, name string) (uint64, error)
+}
+
+type BucketService struct {
+	v1.UnimplementedBucketServiceServer
+	repository BucketRepository
+	autoCreate bool
+
+	mutex  sync.RWMutex
+	active map[string]struct{}
+}
+
+// NewBucketService instantiates bucket service, on auto create unknown buckets get created on ingestion
+func NewBucketService(r BucketRepository, autoCreate bool) *BucketService {
+	return &BucketService{
+		repository: r,
+		autoCreate: autoCreate,
+		active:     map[string]struct{}{},
+	}
+}
+
+// EnsureBucket checks bucket accepts new log lines, creating it if auto create is enabled
+func (b *BucketService) EnsureBucket(ctx context.Context, name string) error {
+	key := activeBucketKey(ctx, name)
+	b.mutex.RLock()
+	_, ok := b.active[key]
+	b.mutex.RUnlock()
+	if ok {
+		return nil
+	}
+
+	bucket, err := b.repository.GetBucket(ctx, name)
+	if errors.Is(err, ErrBucketNotFound) && b.autoCreate {
+		bucket, err = b.create(ctx, name, "", retention.Forever)
+		if errors.Is(err, ErrBucketAlreadyExists) {
+			bucket, err = b.repository.GetBucket(ctx, name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if bucket.Archived {
+		return ErrBucketArchived
+	}
+
+	b.mutex.Lock()
+	b.active[key] = struct{}{}
+	b.mutex.Unlock()
+
+	return nil
+}
+
+func (b *BucketService) CreateBucket(ctx context.Context, r *v1.CreateBucketRequest) (*v1.Bucket, error) {
+	d, err := retention.ParseMaxAge(r.GetRetention())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	bucket, err := b.create(ctx, r.GetName(), r.GetDescription(), d)
+	if errors.Is(err, ErrInvalidBucketName) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrBucketAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot create Bucket on repository!")
+	}
+
+	return convertBucketToProtocol(bucket, 0), nil
+}
+
+func (b *BucketService) ListBuckets(ctx context.Context, r *v1.ListBucketsRequest) (*v1.Buckets, error) {
+	all, err := b.repository.ListBuckets(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot list Buckets on repository!")
+	}
+
+	res := []*v1.Bucket{}
+	for _, bucket := range all {
+		if bucket.Archived && !r.GetIncludeArchived() {
+			continue
+		}
+		total, err := b.repository.CountBucketLines(ctx, bucket.Name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Cannot count Bucket lines on repository!")
+		}
+		res = append(res, convertBucketToProtocol(bucket, total))
+	}
+
+	return &v1.Buckets{Buckets: res}, nil
+}
+
+func (b *BucketService) GetBucket(ctx context.Context, r *v1.GetBucketRequest) (*v1.Bucket, error) {
+	bucket, err := b.repository.GetBucket(ctx, r.GetName())
+	if errors.Is(err, ErrBucketNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot get Bucket on repository!")
+	}
+
+	total, err := b.repository.CountBucketLines(ctx, bucket.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot count Bucket lines on repository!")
+	}
+
+	return convertBucketToProtocol(bucket, total), nil
+}
+
+func (b *BucketService) ArchiveBucket(ctx context.Context, r *v1.ArchiveBucketRequest) (*v1.Bucket, error) {
+	bucket, err := b.repository.GetBucket(ctx, r.GetName())
+	if errors.Is(err, ErrBucketNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot get Bucket on repository!")
+	}
+
+	if !bucket.Archived {
+		bucket.Archived = true
+		bucket.ArchivedAt = time.Now()
+		if err := b.repository.UpdateBucket(ctx, bucket); err != nil {
+			return nil, status.Error(codes.Internal, "Cannot archive Bucket on repository!")
+		}
+	}
+
+	b.mutex.Lock()
+	delete(b.active, activeBucketKey(ctx, bucket.Name))
+	b.mutex.Unlock()
+
+	return convertBucketToProtocol(bucket, 0), nil
+}
+
+func (b *BucketService) create(ctx context.Context, name, description string, maxAge time.Duration) (*Bucket, error) {
+	if !bucketNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("unable to create bucket %q, error %w", name, ErrInvalidBucketName)
+	}
+
+	owner := ""
+	if p, ok := principal.FromContext(ctx); ok {
+		owner = p.ID
+	}
+
+	bucket := &Bucket{
+		Name:        name,
+		Owner:       owner,
+		Description: description,
+		Retention:   maxAge,
+		CreatedAt:   time.Now(),
+	}
+	if err := b.repository.CreateBucket(ctx, bucket); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// activeBucketKey scopes active buckets cache by request tenant
+func activeBucketKey(ctx context.Context, name string) string {
+	if p, ok := principal.FromContext(ctx); ok && p.Tenant != "" {
+		return p.Tenant + "/" + name
+	}
+	return name
+}
+
+// requestTenant returns request principal tenant, empty on requests routed to the default database
+func requestTenant(ctx context.Context) string {
+	if p, ok := principal.FromContext(ctx); ok {
+		return p.Tenant
+	}
+	return ""
+}
+
+func convertBucketToProtocol(b *Bucket, lineCount uint64) *v1.Bucket {
+	res := &v1.Bucket{
+		Name:        b.Name,
+		Owner:       b.Owner,
+		Description: b.Description,
+		Retention:   retention.FormatMaxAge(b.Retention),
+		CreatedAt:   timestamppb.New(b.CreatedAt),
+		LineCount:   lineCount,
+		Archived:    b.Archived,
+	}
+	if b.Archived {
+		res.ArchivedAt = timestamppb.New(b.ArchivedAt)
+	}
+
+	return res
+}
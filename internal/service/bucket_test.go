@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItCreatesBucketOwnedByRequestPrincipal(t *testing.T) {
+	r := newFakeBucketRepository()
+	b := NewBucketService(r, false)
+
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal"})
+	res, err := b.CreateBucket(ctx, &v1.CreateBucketRequest{Name: "debug", Retention: "7d"})
+	if err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+
+	if expected, got := "fake_principal", res.GetOwner(); expected != got {
+		t.Errorf("owner does not match, expected %s got %s", expected, got)
+	}
+
+	if expected, got := 7*24*time.Hour, r.buckets["debug"].Retention; expected != got {
+		t.Errorf("retention does not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItRefusesInvalidBucketNames(t *testing.T) {
+	b := NewBucketService(newFakeBucketRepository(), false)
+
+	for _, name := range []string{"", "foo bar", "foo/bar", "_foo"} {
+		_, err := b.CreateBucket(context.Background(), &v1.CreateBucketRequest{Name: name})
+		if expected, got := codes.InvalidArgument, status.Code(err); expected != got {
+			t.Errorf("unexpected code on %q, expected %v got %v", name, expected, got)
+		}
+	}
+}
+
+func TestItEnsuresBucketsAcceptLogLines(t *testing.T) {
+	r := newFakeBucketRepository()
+	b := NewBucketService(r, false)
+	ctx := context.Background()
+
+	if err := b.EnsureBucket(ctx, "unknown"); !errors.Is(err, ErrBucketNotFound) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+
+	if _, err := b.CreateBucket(ctx, &v1.CreateBucketRequest{Name: "known"}); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+
+	if err := b.EnsureBucket(ctx, "known"); err != nil {
+		t.Errorf("unexpected error ensuring bucket, error %v", err)
+	}
+
+	if _, err := b.ArchiveBucket(ctx, &v1.ArchiveBucketRequest{Name: "known"}); err != nil {
+		t.Fatalf("unexpected error archiving bucket, error %v", err)
+	}
+
+	if err := b.EnsureBucket(ctx, "known"); !errors.Is(err, ErrBucketArchived) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+}
+
+func TestItScopesActiveBucketsByTenant(t *testing.T) {
+	r := newFakeBucketRepository()
+	b := NewBucketService(r, false)
+
+	acme := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal", Tenant: "acme"})
+	if _, err := b.CreateBucket(acme, &v1.CreateBucketRequest{Name: "shared"}); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+	if err := b.EnsureBucket(acme, "shared"); err != nil {
+		t.Fatalf("unexpected error ensuring bucket, error %v", err)
+	}
+
+	delete(r.buckets, "shared")
+	globex := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal", Tenant: "globex"})
+	if err := b.EnsureBucket(globex, "shared"); !errors.Is(err, ErrBucketNotFound) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+}
+
+func TestItAutoCreatesBucketsOnEnsure(t *testing.T) {
+	r := newFakeBucketRepository()
+	b := NewBucketService(r, true)
+
+	if err := b.EnsureBucket(context.Background(), "auto"); err != nil {
+		t.Fatalf("unexpected error ensuring bucket, error %v", err)
+	}
+
+	if _, ok := r.buckets["auto"]; !ok {
+		t.Error("expected auto created bucket")
+	}
+}
+
+func TestItListsBucketsWithItsLineCount(t *testing.T) {
+	r := newFakeBucketRepository()
+	b := NewBucketService(r, false)
+
+	if _, err := b.CreateBucket(context.Background(), &v1.CreateBucketRequest{Name: "debug"}); err != nil {
+		t.Fatalf("unexpected error creating bucket, error %v", err)
+	}
+	r.lines["debug"] = 12
+
+	res, err := b.ListBuckets(context.Background(), &v1.ListBucketsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error listing buckets, error %v", err)
+	}
+	if expected, got := 1, len(res.GetBuckets()); expected != got {
+		t.Fatalf("buckets do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := uint64(12), res.GetBuckets()[0].GetLineCount(); expected != got {
+		t.Errorf("line counts do not match, expected %d got %d", expected, got)
+	}
+}
+
+type fakeBucketRepository struct {
+	buckets map[string]*Bucket
+	lines   map[string]uint64
+}
+
+func newFakeBucketRepository() *fakeBucketRepository {
+	return &fakeBucketRepository{buckets: map[string]*Bucket{}, lines: map[string]uint64{}}
+}
+
+func (f *fakeBucketRepository) CreateBucket(ctx context.Context, b *Bucket) error {
+	if _, ok := f.buckets[b.Name]; ok {
+		return ErrBucketAlreadyExists
+	}
+	f.buckets[b.Name] = b
+	return nil
+}
+
+func (f *fakeBucketRepository) UpdateBucket(ctx context.Context, b *Bucket) error {
+	f.buckets[b.Name] = b
+	return nil
+}
+
+func (f *fakeBucketRepository) GetBucket(ctx context.Context, name string) (*Bucket, error) {
+	b, ok := f.buckets[name]
+	if !ok {
+		return nil, ErrBucketNotFound
+	}
+	return b, nil
+}
+
+func (f *fakeBucketRepository) ListBuckets(ctx context.Context) ([]*Bucket, error) {
+	res := []*Bucket{}
+	for _, b := range f.buckets {
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+func (f *fakeBucketRepository) CountBucketLines(ctx context.Context, name string) (uint64, error) {
+	return f.lines[name], nil
+}
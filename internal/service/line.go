@@ -1,6 +1,12 @@
 package service
 
-import "time"
+import (
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+)
+
+var ErrInvalidLogLineKey = linekey.ErrInvalidKey
 
 type LogLine struct {
 	key   string
@@ -8,6 +14,8 @@ type LogLine struct {
 
 	bucket string
 	time   time.Time
+
+	signature *Signature
 }
 
 func NewLogLine(key, value string) *LogLine {
@@ -26,6 +34,31 @@ func NewLogLineWithBucket(bucket, key, value string, ts time.Time) *LogLine {
 	}
 }
 
+// DecodeLogLine builds a stored log line, creation time comes from its key when it can be decoded
+func DecodeLogLine(key, value string) *LogLine {
+	l := NewLogLine(key, value)
+	if k, err := linekey.Decode(key); err == nil {
+		l.time = k.Time
+	}
+
+	return l
+}
+
+// NewSourceLogLine builds a bucket log line keyed by its source and creation time, with no sequence
+func NewSourceLogLine(source, bucket, value string, ts time.Time) *LogLine {
+	return NewKeyedLogLine(linekey.Key{Source: source, Time: ts}, bucket, value)
+}
+
+// NewKeyedLogLine builds a bucket log line on key k
+func NewKeyedLogLine(k linekey.Key, bucket, value string) *LogLine {
+	return NewLogLineWithBucket(bucket, linekey.Encode(k), value, k.Time)
+}
+
+// ParseLogLineKey returns log line source, creation time and sequence from its key, legacy keys included
+func ParseLogLineKey(key string) (linekey.Key, error) {
+	return linekey.Decode(key)
+}
+
 func (l *LogLine) Key() []byte {
 	return []byte(l.key)
 }
@@ -42,6 +75,17 @@ func (l *LogLine) Time() time.Time {
 	return l.time
 }
 
+// WithSignature attaches line signature
+func (l *LogLine) WithSignature(s *Signature) *LogLine {
+	l.signature = s
+	return l
+}
+
+// Signature returns line signature, nil on unsigned lines
+func (l *LogLine) Signature() *Signature {
+	return l.signature
+}
+
 type LogLineHistory struct {
 	Key      string
 	Revision []*LogLineRevision
@@ -51,4 +95,18 @@ type LogLineRevision struct {
 	Value    []byte
 	Tx       uint64
 	Revision uint64
+
+	// CommittedAt and Principal are just filled on revision histories
+	CommittedAt time.Time
+	Principal   string
+}
+
+// ExportedLogLine holds a log line with its immudb transaction and revision
+type ExportedLogLine struct {
+	Key       string
+	Bucket    string
+	Value     []byte
+	Tx        uint64
+	Revision  uint64
+	CreatedAt time.Time
 }
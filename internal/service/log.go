@@ -2,31 +2,65 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
+	"github.com/marcosQuesada/log-api/internal/diff"
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/metrics"
 	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var (
+	ErrLogLineNotFound = errors.New("log line not found")
+	ErrResultTooLarge  = errors.New("result exceeds max result size")
+	ErrLogLineExists   = errors.New("log line already exists")
+)
+
+// maxKeyRetries bounds log lines creation retries on keys already stored by concurrent requests
+const maxKeyRetries = 8
+
 type Repository interface {
 	Add(ctx context.Context, line *LogLine) error
 	AddBatch(ctx context.Context, lines []*LogLine) error
+	CreateBatch(ctx context.Context, lines []*LogLine) error
 	History(ctx context.Context, key string) (*LogLineHistory, error)
+	Histories(ctx context.Context, keys []string) ([]*LogLineHistory, error)
+	RevisionHistory(ctx context.Context, key string) (*LogLineHistory, error)
 	Count(ctx context.Context) (uint64, error)
 	GetByKey(ctx context.Context, key string) (*LogLine, error)
 	GetByPrefix(ctx context.Context, prefix string) ([]*LogLine, error)
 	GetLastNLogLines(ctx context.Context, n int) ([]*LogLine, error)
 
 	GetByBucket(ctx context.Context, bucket string) ([]*LogLine, error)
+
+	TxAt(ctx context.Context, t time.Time) (uint64, error)
+	GetByKeyAt(ctx context.Context, key string, tx uint64) (*LogLine, error)
+	GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*LogLine, error)
+	GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*LogLine, error)
+
+	ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*ExportedLogLine) error) error
+}
+
+type bucketGuard interface {
+	EnsureBucket(ctx context.Context, name string) error
 }
 
 type LogService struct {
 	v1.UnimplementedLogServiceServer
 	repository Repository
+	buckets    bucketGuard
+	signatures signatures
+	limits     LineLimits
+	sequence   uint32
+	legacyKeys bool
 }
 
 func NewLogService(r Repository) *LogService {
@@ -35,13 +69,49 @@ func NewLogService(r Repository) *LogService {
 	}
 }
 
+// WithBucketGuard enables bucket validation on log lines ingestion
+func (l *LogService) WithBucketGuard(g bucketGuard) *LogService {
+	l.buckets = g
+	return l
+}
+
+// WithLineLimits bounds ingested log line values size and batches length
+func (l *LogService) WithLineLimits(limits LineLimits) *LogService {
+	l.limits = limits
+	return l
+}
+
+// WithSignatures enables client signed log lines, signatures are verified against its source registered key
+func (l *LogService) WithSignatures(s signatures) *LogService {
+	l.signatures = s
+	return l
+}
+
+// WithLegacyKeyReads scans legacy source_unixnano keys on source prefix reads too, until keys get migrated
+func (l *LogService) WithLegacyKeyReads() *LogService {
+	l.legacyKeys = true
+	return l
+}
+
 func (l *LogService) CreateLogLine(ctx context.Context, r *v1.CreateLogLineRequest) (*v1.CreateLogLineResponse, error) {
 	log.Printf("Create Log Line %v", r)
 
-	line := convertLogLineRequest(r)
-	if err := l.repository.Add(ctx, line); err != nil {
+	if v := l.limits.validateLine(r, ""); len(v) > 0 {
+		return nil, invalidArgument("invalid log line", v)
+	}
+
+	line := l.convertLogLineRequest(r, time.Now(), map[string]uint32{})
+	if err := l.verifySignature(r, line); err != nil {
+		return nil, err
+	}
+	if err := l.ensureBuckets(ctx, line); err != nil {
+		return nil, err
+	}
+
+	if err := l.create(ctx, []*LogLine{line}, []bool{r.GetCreatedAt() != nil}); err != nil {
 		return nil, status.Error(codes.Internal, "Cannot add LoginLine on repository!")
 	}
+	metrics.Ingested(requestTenant(ctx), line.bucket, len(line.value))
 
 	return &v1.CreateLogLineResponse{
 		Key: line.key,
@@ -49,18 +119,36 @@ func (l *LogService) CreateLogLine(ctx context.Context, r *v1.CreateLogLineReque
 }
 
 func (l *LogService) BatchCreateLogLines(ctx context.Context, lines *v1.BatchCreateLogLinesRequest) (*v1.BatchCreateLogLinesResponse, error) {
+	if v := l.limits.validateBatch(lines); len(v) > 0 {
+		return nil, invalidArgument("invalid log lines batch", v)
+	}
+
 	logs := []*LogLine{}
-	ids := []string{}
+	keyed := []bool{}
+	taken := map[string]uint32{}
 	for _, r := range lines.Lines {
-		line := convertLogLineRequest(r)
+		line := l.convertLogLineRequest(r, time.Now(), taken)
+		if err := l.verifySignature(r, line); err != nil {
+			return nil, err
+		}
 
 		logs = append(logs, line)
-		ids = append(ids, line.key)
+		keyed = append(keyed, r.GetCreatedAt() != nil)
 	}
 
-	if err := l.repository.AddBatch(ctx, logs); err != nil {
+	if err := l.ensureBuckets(ctx, logs...); err != nil {
+		return nil, err
+	}
+
+	if err := l.create(ctx, logs, keyed); err != nil {
 		return nil, status.Error(codes.Internal, "Cannot process BatchCreateLogLines on repository!")
 	}
+	ids := []string{}
+	tenant := requestTenant(ctx)
+	for _, line := range logs {
+		ids = append(ids, line.key)
+		metrics.Ingested(tenant, line.bucket, len(line.value))
+	}
 
 	return &v1.BatchCreateLogLinesResponse{
 		Key: ids,
@@ -85,6 +173,53 @@ func (l *LogService) GetLastNLogLinesHistory(ctx context.Context, e *v1.LastNLog
 	return l.histories(ctx, all)
 }
 
+// GetLogLineHistory returns key revisions with its transaction timestamp and writer principal,
+// with diff each revision describes its changes from the previous one
+func (l *LogService) GetLogLineHistory(ctx context.Context, req *v1.LogLineHistoryRequest) (*v1.LogLineHistory, error) {
+	h, err := l.repository.RevisionHistory(ctx, req.GetKey())
+	if errors.Is(err, ErrLogLineNotFound) {
+		return nil, status.Errorf(codes.NotFound, "log line %q not found", req.GetKey())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot get History on repository!")
+	}
+
+	r := []*v1.LogLineRevision{}
+	var prev *LogLineRevision
+	for _, i := range h.Revision {
+		rv := &v1.LogLineRevision{
+			Tx:        int64(i.Tx),
+			Value:     string(i.Value),
+			Revision:  int64(i.Revision),
+			Principal: i.Principal,
+		}
+		if !i.CommittedAt.IsZero() {
+			rv.CommittedAt = timestamppb.New(i.CommittedAt)
+		}
+
+		if req.GetDiff() {
+			var from []byte
+			fromLabel := "/dev/null"
+			if prev != nil {
+				from, fromLabel = prev.Value, fmt.Sprintf("revision %d", prev.Revision)
+			}
+
+			rv.DiffFormat, rv.Diff, err = diff.Diff(from, i.Value, fromLabel, fmt.Sprintf("revision %d", i.Revision))
+			if err != nil {
+				return nil, status.Error(codes.Internal, "Cannot diff log line Revisions!")
+			}
+		}
+
+		r = append(r, rv)
+		prev = i
+	}
+
+	return &v1.LogLineHistory{
+		Key:      h.Key,
+		Revision: r,
+	}, nil
+}
+
 func (l *LogService) GetLogLineCount(ctx context.Context, e *emptypb.Empty) (*v1.Count, error) {
 	total, err := l.repository.Count(ctx)
 	if err != nil {
@@ -96,19 +231,56 @@ func (l *LogService) GetLogLineCount(ctx context.Context, e *emptypb.Empty) (*v1
 }
 
 func (l *LogService) GetLogLineByKey(ctx context.Context, line *v1.LogLineByKeyRequest) (*v1.LogLine, error) {
-	ll, err := l.repository.GetByKey(ctx, line.Key)
+	tx, asOf, err := l.asOfTx(ctx, line.GetAsOfTx(), line.GetAsOfTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var ll *LogLine
+	if asOf {
+		ll, err = l.repository.GetByKeyAt(ctx, line.Key, tx)
+	} else {
+		ll, err = l.repository.GetByKey(ctx, line.Key)
+	}
+	if errors.Is(err, ErrLogLineNotFound) {
+		return nil, status.Errorf(codes.NotFound, "log line %q not found", line.Key)
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Cannot get by Key on repository!")
 	}
 	return convertLogLinesToProtocol(ll), nil
 }
 
+// GetLogLinesByPrefix returns log lines whose source starts by prefix, empty prefixes return every line. Prefixes on
+// the versioned key encoding are scanned as they are, so they narrow source lines down by creation time. With legacy
+// key reads, legacy lines starting by prefix go first
 func (l *LogService) GetLogLinesByPrefix(ctx context.Context, line *v1.LogLineByPrefixRequest) (*v1.LogLines, error) {
-	ll, err := l.repository.GetByPrefix(ctx, line.Prefix)
+	tx, asOf, err := l.asOfTx(ctx, line.GetAsOfTx(), line.GetAsOfTime())
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := linekey.Prefix(line.Prefix)
+	ll, err := l.getByPrefix(ctx, prefix, tx, asOf)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Cannot get by Prefix on repository!")
 	}
 
+	if l.legacyKeys && prefix != line.Prefix {
+		legacy, err := l.getByPrefix(ctx, line.Prefix, tx, asOf)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Cannot get by legacy Prefix on repository!")
+		}
+
+		source := []*LogLine{}
+		for _, logLine := range legacy {
+			if _, err := linekey.Decode(string(logLine.Key())); err == nil && linekey.IsLegacy(string(logLine.Key())) {
+				source = append(source, logLine)
+			}
+		}
+		ll = append(source, ll...)
+	}
+
 	lines := []*v1.LogLine{}
 	for _, logLine := range ll {
 		lines = append(lines, convertLogLinesToProtocol(logLine))
@@ -116,8 +288,28 @@ func (l *LogService) GetLogLinesByPrefix(ctx context.Context, line *v1.LogLineBy
 	return &v1.LogLines{LogLines: lines}, nil
 }
 
+func (l *LogService) getByPrefix(ctx context.Context, prefix string, tx uint64, asOf bool) ([]*LogLine, error) {
+	if asOf {
+		return l.repository.GetByPrefixAt(ctx, prefix, tx)
+	}
+	return l.repository.GetByPrefix(ctx, prefix)
+}
+
 func (l *LogService) GetLogLinesByBucket(ctx context.Context, req *v1.LogLineByBucketRequest) (*v1.LogLines, error) {
-	ll, err := l.repository.GetByBucket(ctx, req.GetBucket())
+	tx, asOf, err := l.asOfTx(ctx, req.GetAsOfTx(), req.GetAsOfTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var ll []*LogLine
+	if asOf {
+		ll, err = l.repository.GetByBucketAt(ctx, req.GetBucket(), tx)
+	} else {
+		ll, err = l.repository.GetByBucket(ctx, req.GetBucket())
+	}
+	if errors.Is(err, ErrResultTooLarge) {
+		return nil, status.Error(codes.ResourceExhausted, "Bucket exceeds max result size, export it instead!")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Cannot get by Bucket on repository!")
 	}
@@ -129,14 +321,136 @@ func (l *LogService) GetLogLinesByBucket(ctx context.Context, req *v1.LogLineByB
 	return &v1.LogLines{LogLines: lines}, nil
 }
 
-func (l *LogService) histories(ctx context.Context, all []*LogLine) (*v1.LogLineHistories, error) {
-	lh := []*v1.LogLineHistory{}
-	for _, line := range all {
-		h, err := l.repository.History(ctx, string(line.Key()))
+// ExportLogLines streams bucket log lines created on the requested time range, lines are sent as they are read
+func (l *LogService) ExportLogLines(req *v1.ExportLogLinesRequest, stream v1.LogService_ExportLogLinesServer) error {
+	var from, to time.Time
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return status.Error(codes.InvalidArgument, "export range ends before its start")
+	}
+
+	err := l.repository.ExportByBucket(stream.Context(), req.GetBucket(), from, to, func(e *ExportedLogLine) error {
+		return stream.Send(&v1.ExportedLogLine{
+			Key:       e.Key,
+			Bucket:    e.Bucket,
+			Value:     string(e.Value),
+			Tx:        e.Tx,
+			Revision:  e.Revision,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		})
+	})
+	if err != nil {
+		return status.Error(codes.Internal, "Cannot export Bucket on repository!")
+	}
+
+	return nil
+}
+
+// GetSigningKeys returns public keys verifying log line signatures
+func (l *LogService) GetSigningKeys(ctx context.Context, e *emptypb.Empty) (*v1.SigningKeys, error) {
+	keys := []*v1.SigningKey{}
+	if l.signatures == nil {
+		return &v1.SigningKeys{Keys: keys}, nil
+	}
+
+	for _, k := range l.signatures.Keys() {
+		keys = append(keys, &v1.SigningKey{
+			Id:        k.ID,
+			Source:    k.Source,
+			PublicKey: k.PublicKey,
+		})
+	}
+
+	return &v1.SigningKeys{Keys: keys}, nil
+}
+
+// asOfTx resolves point in time reads transaction, as of time reads get last transaction committed by then.
+// It returns false on current reads
+func (l *LogService) asOfTx(ctx context.Context, tx uint64, t *timestamppb.Timestamp) (uint64, bool, error) {
+	if tx > 0 && t != nil {
+		return 0, false, status.Error(codes.InvalidArgument, "as_of_tx and as_of_time are exclusive")
+	}
+	if tx > 0 {
+		return tx, true, nil
+	}
+	if t == nil {
+		return 0, false, nil
+	}
+
+	tx, err := l.repository.TxAt(ctx, t.AsTime())
+	if err != nil {
+		return 0, false, status.Error(codes.Internal, "Cannot resolve Transaction on repository!")
+	}
+
+	return tx, true, nil
+}
+
+// verifySignature verifies client submitted signature, attaching it to the line
+func (l *LogService) verifySignature(r *v1.CreateLogLineRequest, line *LogLine) error {
+	if len(r.GetSignature()) == 0 {
+		return nil
+	}
+	if l.signatures == nil {
+		return status.Error(codes.InvalidArgument, "log line signatures are not enabled")
+	}
+
+	s, err := l.signatures.VerifySource(r.GetSource(), line, r.GetSignature())
+	if errors.Is(err, ErrInvalidSignature) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, "Cannot verify log line Signature!")
+	}
+	line.WithSignature(s)
+
+	return nil
+}
+
+func (l *LogService) ensureBuckets(ctx context.Context, lines ...*LogLine) error {
+	if l.buckets == nil {
+		return nil
+	}
+
+	checked := map[string]struct{}{}
+	for _, line := range lines {
+		if _, ok := checked[line.bucket]; ok {
+			continue
+		}
+		checked[line.bucket] = struct{}{}
+
+		err := l.buckets.EnsureBucket(ctx, line.bucket)
+		if errors.Is(err, ErrInvalidBucketName) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, ErrBucketNotFound) || errors.Is(err, ErrBucketArchived) {
+			return status.Errorf(codes.FailedPrecondition, "bucket %q does not accept log lines, %v", line.bucket, err)
+		}
 		if err != nil {
-			return nil, status.Error(codes.Internal, "Cannot get History on repository!")
+			return status.Error(codes.Internal, "Cannot validate Bucket on repository!")
 		}
+	}
 
+	return nil
+}
+
+func (l *LogService) histories(ctx context.Context, all []*LogLine) (*v1.LogLineHistories, error) {
+	keys := make([]string, 0, len(all))
+	for _, line := range all {
+		keys = append(keys, string(line.Key()))
+	}
+
+	hs, err := l.repository.Histories(ctx, keys)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Cannot get Histories on repository!")
+	}
+
+	lh := []*v1.LogLineHistory{}
+	for _, h := range hs {
 		r := []*v1.LogLineRevision{}
 		for _, i := range h.Revision {
 			r = append(r, &v1.LogLineRevision{
@@ -146,7 +460,7 @@ func (l *LogService) histories(ctx context.Context, all []*LogLine) (*v1.LogLine
 			})
 		}
 		lh = append(lh, &v1.LogLineHistory{
-			Key:      string(line.Key()),
+			Key:      h.Key,
 			Revision: r,
 		})
 	}
@@ -156,24 +470,113 @@ func (l *LogService) histories(ctx context.Context, all []*LogLine) (*v1.LogLine
 	}, nil
 }
 
-func convertLogLineRequest(l *v1.CreateLogLineRequest) *LogLine {
-	return &LogLine{
-		key:   logLineKey(l.GetSource(), l.CreatedAt.AsTime()),
-		value: l.Value,
+// create stores request lines. Client timed lines are keyed by the request itself, so they are written as they are,
+// stored keys getting a new revision. Server timed lines never overwrite stored ones, lines whose keys got stored
+// meanwhile by another service instance are retried on its next free sequence
+func (l *LogService) create(ctx context.Context, lines []*LogLine, keyed []bool) error {
+	updates, creates := []*LogLine{}, []*LogLine{}
+	for i, line := range lines {
+		if keyed[i] {
+			updates = append(updates, line)
+			continue
+		}
+		creates = append(creates, line)
+	}
 
-		bucket: l.GetBucket(), // @TODO: Refactor on next iteration
-		time:   l.CreatedAt.AsTime(),
+	for attempt := 0; len(creates) > 0; attempt++ {
+		err := l.repository.CreateBatch(ctx, creates)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrLogLineExists) {
+			return err
+		}
+		if attempt == maxKeyRetries {
+			return fmt.Errorf("unable to find free keys after %d attempts, error %v", attempt+1, err)
+		}
+		metrics.PreconditionConflicts.WithLabelValues("create_log_lines").Inc()
+
+		if err := l.nextFreeKeys(ctx, creates); err != nil {
+			return err
+		}
+	}
+
+	if len(updates) == 0 {
+		return nil
 	}
 
+	return l.repository.AddBatch(ctx, updates)
 }
 
-func convertLogLinesToProtocol(l *LogLine) *v1.LogLine {
-	return &v1.LogLine{
-		Key:   l.key,
-		Value: l.value,
+// nextFreeKeys moves lines with stored keys to its next sequence not taken by the request
+func (l *LogService) nextFreeKeys(ctx context.Context, lines []*LogLine) error {
+	taken := map[string]struct{}{}
+	for _, line := range lines {
+		taken[line.key] = struct{}{}
+	}
+
+	for _, line := range lines {
+		_, err := l.repository.GetByKey(ctx, line.key)
+		if errors.Is(err, ErrLogLineNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get key %s, error %w", line.key, err)
+		}
+
+		k, err := linekey.Decode(line.key)
+		if err != nil {
+			return fmt.Errorf("unable to decode key %s, error %w", line.key, err)
+		}
+		for {
+			k.Sequence++
+			if _, ok := taken[linekey.Encode(k)]; !ok {
+				break
+			}
+		}
+		line.key = linekey.Encode(k)
+		taken[line.key] = struct{}{}
 	}
+
+	return nil
 }
 
-func logLineKey(source string, t time.Time) string {
-	return fmt.Sprintf("%s_%d", source, t.UnixNano())
+// convertLogLineRequest builds request log line, lines without creation time are created at now with the next
+// service key sequence. Client timed lines sequence counts previous request lines taken with the same source and
+// creation time, so signing clients can reproduce its key
+func (l *LogService) convertLogLineRequest(r *v1.CreateLogLineRequest, now time.Time, taken map[string]uint32) *LogLine {
+	k := linekey.Key{Source: r.GetSource(), Time: now.UTC()}
+	if r.GetCreatedAt() != nil {
+		k.Time = r.GetCreatedAt().AsTime()
+		id := linekey.Encode(k)
+		k.Sequence = taken[id]
+		taken[id]++
+	} else {
+		k.Sequence = atomic.AddUint32(&l.sequence, 1)
+	}
+
+	return &LogLine{
+		key:   linekey.Encode(k),
+		value: r.GetValue(),
+
+		bucket: r.GetBucket(),
+		time:   k.Time,
+	}
+}
+
+func convertLogLinesToProtocol(l *LogLine) *v1.LogLine {
+	res := &v1.LogLine{
+		Key:    l.key,
+		Value:  l.value,
+		Bucket: l.bucket,
+	}
+	if !l.time.IsZero() {
+		res.CreatedAt = timestamppb.New(l.time)
+	}
+	if l.signature != nil {
+		res.SigningKeyId = l.signature.KeyID
+		res.Signature = l.signature.Value
+	}
+
+	return res
 }
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestItAssignsKeySequencesToBatchLinesSharingSourceAndCreationTime(t *testing.T) {
+	svc := NewLogService(&fakeLogRepository{})
+
+	ts := &timestamppb.Timestamp{Seconds: 1659469226, Nanos: 165084420}
+	res, err := svc.BatchCreateLogLines(context.Background(), &v1.BatchCreateLogLinesRequest{Lines: []*v1.CreateLogLineRequest{
+		{Source: "app", Bucket: "debug", Value: "foo", CreatedAt: ts},
+		{Source: "app_worker", Bucket: "debug", Value: "foo", CreatedAt: ts},
+		{Source: "app", Bucket: "debug", Value: "bar", CreatedAt: ts},
+		{Source: "app", Bucket: "debug", Value: "foo"},
+		{Source: "app", Bucket: "debug", Value: "bar"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error creating log lines %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, key := range res.GetKey() {
+		if seen[key] {
+			t.Errorf("duplicated key %s", key)
+		}
+		seen[key] = true
+	}
+
+	for i, expected := range []uint32{0, 0, 1} {
+		k, err := ParseLogLineKey(res.GetKey()[i])
+		if err != nil {
+			t.Fatalf("unexpected error parsing key %v", err)
+		}
+		if got := k.Sequence; expected != got {
+			t.Errorf("line %d key sequences do not match, expected %d got %d", i, expected, got)
+		}
+		if !k.Time.Equal(ts.AsTime()) {
+			t.Errorf("line %d key creation times do not match, expected %v got %v", i, ts.AsTime(), k.Time)
+		}
+	}
+}
+
+func TestItMovesServerTimedLinesCollidingWithStoredKeysToNextSequence(t *testing.T) {
+	r := &racingLogRepository{}
+	svc := NewLogService(r)
+
+	res, err := svc.CreateLogLine(context.Background(), &v1.CreateLogLineRequest{Source: "app", Bucket: "debug", Value: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating log line %v", err)
+	}
+
+	k, err := ParseLogLineKey(res.GetKey())
+	if err != nil {
+		t.Fatalf("unexpected error parsing key %v", err)
+	}
+	if expected, got := uint32(2), k.Sequence; expected != got {
+		t.Errorf("key sequences do not match, expected %d got %d", expected, got)
+	}
+
+	line, err := r.GetByKey(context.Background(), res.GetKey())
+	if err != nil {
+		t.Fatalf("unexpected error getting key %v", err)
+	}
+	if expected, got := "foo", string(line.Value()); expected != got {
+		t.Errorf("stored values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItUpdatesStoredKeysOnClientTimedLines(t *testing.T) {
+	r := &fakeLogRepository{}
+	svc := NewLogService(r)
+
+	ts := &timestamppb.Timestamp{Seconds: 1659469226, Nanos: 165084420}
+	keys := []string{}
+	for _, value := range []string{"foo", "bar"} {
+		res, err := svc.CreateLogLine(context.Background(), &v1.CreateLogLineRequest{Source: "app", Bucket: "debug", Value: value, CreatedAt: ts})
+		if err != nil {
+			t.Fatalf("unexpected error creating log line %v", err)
+		}
+		keys = append(keys, res.GetKey())
+	}
+
+	if keys[0] != keys[1] {
+		t.Fatalf("keys do not match, expected %s got %s", keys[0], keys[1])
+	}
+	if expected, got := 2, len(r.lines); expected != got {
+		t.Fatalf("stored revisions do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := "bar", string(r.lines[1].Value()); expected != got {
+		t.Errorf("stored values do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItFailsOnUnreadableKeysCollidingWithStoredKeys(t *testing.T) {
+	r := &racingLogRepository{readErr: errors.New("fake transport error")}
+	svc := NewLogService(r)
+
+	_, err := svc.CreateLogLine(context.Background(), &v1.CreateLogLineRequest{Source: "app", Bucket: "debug", Value: "foo"})
+	if expected, got := codes.Internal, status.Code(err); expected != got {
+		t.Fatalf("status codes do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := 1, len(r.lines); expected != got {
+		t.Errorf("stored lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+// racingLogRepository stores first created keys before creating them, as a concurrent service instance would
+type racingLogRepository struct {
+	fakeLogRepository
+	raced   bool
+	readErr error
+}
+
+func (f *racingLogRepository) CreateBatch(ctx context.Context, lines []*LogLine) error {
+	if !f.raced {
+		f.raced = true
+		for _, line := range lines {
+			f.lines = append(f.lines, NewLogLine(line.key, "raced"))
+		}
+	}
+	return f.fakeLogRepository.CreateBatch(ctx, lines)
+}
+
+func (f *racingLogRepository) GetByKey(ctx context.Context, key string) (*LogLine, error) {
+	if f.readErr != nil {
+		return nil, f.readErr
+	}
+	return f.fakeLogRepository.GetByKey(ctx, key)
+}
+
+type fakeSignatures struct{}
+
+func (fakeSignatures) VerifySource(source string, line *LogLine, signature []byte) (*Signature, error) {
+	return &Signature{KeyID: source, Value: signature}, nil
+}
+
+func (fakeSignatures) Keys() []*SigningKey {
+	return nil
+}
+
+func TestItReadsSourceLegacyKeysOnPrefixReads(t *testing.T) {
+	ts := time.Unix(0, 1659469226165084420)
+	r := &fakeLogRepository{lines: []*LogLine{
+		NewLogLineWithBucket("debug", "app_1659469226165084420", "legacy", ts),
+		NewLogLineWithBucket("debug", "app_worker_1659469226165084420", "another source legacy", ts),
+		NewSourceLogLine("app", "debug", "foo", ts),
+	}}
+
+	res, err := NewLogService(r).WithLegacyKeyReads().GetLogLinesByPrefix(context.Background(), &v1.LogLineByPrefixRequest{Prefix: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix %v", err)
+	}
+
+	if expected, got := 3, len(res.GetLogLines()); expected != got {
+		t.Fatalf("log lines do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := "app_1659469226165084420", res.GetLogLines()[0].GetKey(); expected != got {
+		t.Errorf("keys do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := linekey.Encode(linekey.Key{Source: "app", Time: ts}), res.GetLogLines()[2].GetKey(); expected != got {
+		t.Errorf("keys do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItReadsEveryLogLineOnEmptyPrefix(t *testing.T) {
+	ts := time.Unix(0, 1659469226165084420)
+	r := &fakeLogRepository{lines: []*LogLine{
+		NewSourceLogLine("app", "debug", "foo", ts),
+		NewSourceLogLine("worker", "debug", "bar", ts),
+	}}
+
+	res, err := NewLogService(r).GetLogLinesByPrefix(context.Background(), &v1.LogLineByPrefixRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix %v", err)
+	}
+
+	if expected, got := 2, len(res.GetLogLines()); expected != got {
+		t.Fatalf("log lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItReadsEverySourceStartingByPartialPrefix(t *testing.T) {
+	ts := time.Unix(0, 1659469226165084420)
+	r := &fakeLogRepository{lines: []*LogLine{
+		NewSourceLogLine("app", "debug", "foo", ts),
+		NewSourceLogLine("app-1", "debug", "bar", ts),
+		NewSourceLogLine("worker", "debug", "baz", ts),
+	}}
+
+	res, err := NewLogService(r).GetLogLinesByPrefix(context.Background(), &v1.LogLineByPrefixRequest{Prefix: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error getting by prefix %v", err)
+	}
+
+	if expected, got := 2, len(res.GetLogLines()); expected != got {
+		t.Fatalf("log lines do not match, expected %d got %d", expected, got)
+	}
+	for i, expected := range []string{"app", "app-1"} {
+		k, err := ParseLogLineKey(res.GetLogLines()[i].GetKey())
+		if err != nil {
+			t.Fatalf("unexpected error parsing key %v", err)
+		}
+		if got := k.Source; expected != got {
+			t.Errorf("line %d sources do not match, expected %s got %s", i, expected, got)
+		}
+	}
+}
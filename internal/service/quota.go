@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"github.com/marcosQuesada/log-api/internal/quota"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// QuotaReporter reports subjects limits and usage
+type QuotaReporter interface {
+	Usage(s quota.Subject) quota.Usage
+}
+
+type QuotaService struct {
+	v1.UnimplementedQuotaServiceServer
+	reporter QuotaReporter
+}
+
+func NewQuotaService(r QuotaReporter) *QuotaService {
+	return &QuotaService{
+		reporter: r,
+	}
+}
+
+// GetQuotaUsage reports caller principal and API key usage, plus requested buckets usage on caller tenant
+func (q *QuotaService) GetQuotaUsage(ctx context.Context, req *v1.QuotaUsageRequest) (*v1.QuotaUsages, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Cannot report quota usage without principal!")
+	}
+
+	subjects := []quota.Subject{{Kind: quota.KindPrincipal, ID: p.ID}}
+	if p.KeyID != "" {
+		subjects = append(subjects, quota.Subject{Kind: quota.KindAPIKey, ID: p.KeyID})
+	}
+	for _, b := range req.GetBuckets() {
+		subjects = append(subjects, quota.Subject{Kind: quota.KindBucket, Scope: p.Tenant, ID: b})
+	}
+
+	res := &v1.QuotaUsages{}
+	for _, s := range subjects {
+		u := q.reporter.Usage(s)
+		res.Usages = append(res.Usages, &v1.QuotaUsage{
+			Kind:       u.Subject.Kind,
+			Id:         u.Subject.ID,
+			Rate:       u.Limit.Rate,
+			Burst:      u.Limit.Burst,
+			Available:  u.Available,
+			DailyLines: u.Limit.DailyLines,
+			DailyBytes: u.Limit.DailyBytes,
+			UsedLines:  u.Lines,
+			UsedBytes:  u.Bytes,
+			ResetsAt:   timestamppb.New(u.ResetsAt),
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import "errors"
+
+var ErrInvalidSignature = errors.New("invalid log line signature")
+
+// Signature holds a log line signature and the id of the key verifying it
+type Signature struct {
+	KeyID string
+	Value []byte
+}
+
+// SigningKey describes a public key verifying log line signatures, server key has no source
+type SigningKey struct {
+	ID        string
+	Source    string
+	PublicKey []byte
+}
+
+// signatures verifies client submitted signatures against registered source keys
+type signatures interface {
+	VerifySource(source string, line *LogLine, signature []byte) (*Signature, error)
+	Keys() []*SigningKey
+}
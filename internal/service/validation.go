@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sourceRegexp keeps sources readable on its keys, any other byte would be escaped
+var sourceRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// reservedValuePrefixes tag stored values, signed values by signing and encrypted ones by envelope repositories.
+// Client values can not start by them, so stored signatures and envelopes can not be forged nor break its reads
+var reservedValuePrefixes = []string{"sig1:", "enc1:"}
+
+// LineLimits bounds ingested log lines, zero values are unbounded
+type LineLimits struct {
+	MaxValueSize int
+	MaxBatchSize int
+}
+
+// validateBatch returns batch field violations, each line violations are reported under its index
+func (l LineLimits) validateBatch(r *v1.BatchCreateLogLinesRequest) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	if len(r.GetLines()) == 0 {
+		violations = append(violations, violation("lines", "at least a log line is required"))
+	}
+	if l.MaxBatchSize > 0 && len(r.GetLines()) > l.MaxBatchSize {
+		violations = append(violations, violation("lines", fmt.Sprintf("%d log lines exceed max batch size %d", len(r.GetLines()), l.MaxBatchSize)))
+	}
+
+	for i, line := range r.GetLines() {
+		violations = append(violations, l.validateLine(line, fmt.Sprintf("lines[%d].", i))...)
+	}
+
+	return violations
+}
+
+// validateLine returns log line field violations, field names are prefixed by prefix
+func (l LineLimits) validateLine(r *v1.CreateLogLineRequest, prefix string) []*errdetails.BadRequest_FieldViolation {
+	if r == nil {
+		return []*errdetails.BadRequest_FieldViolation{violation(strings.TrimSuffix(prefix, "."), "log line is required")}
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	if !sourceRegexp.MatchString(r.GetSource()) {
+		violations = append(violations, violation(prefix+"source", "source must be 1 to 128 letters, digits, dots, dashes or underscores, starting by a letter or digit"))
+	}
+	if !bucketNameRegexp.MatchString(r.GetBucket()) {
+		violations = append(violations, violation(prefix+"bucket", "bucket must be 1 to 63 letters, digits, dots, dashes or underscores, starting by a letter or digit"))
+	}
+	if r.GetValue() == "" {
+		violations = append(violations, violation(prefix+"value", "value is required"))
+	}
+	for _, p := range reservedValuePrefixes {
+		if strings.HasPrefix(r.GetValue(), p) {
+			violations = append(violations, violation(prefix+"value", fmt.Sprintf("value can not start by reserved prefix %q", p)))
+		}
+	}
+	if l.MaxValueSize > 0 && len(r.GetValue()) > l.MaxValueSize {
+		violations = append(violations, violation(prefix+"value", fmt.Sprintf("value of %d bytes exceeds max value size %d", len(r.GetValue()), l.MaxValueSize)))
+	}
+	if r.GetCreatedAt() != nil {
+		if err := r.GetCreatedAt().CheckValid(); err != nil {
+			violations = append(violations, violation(prefix+"created_at", err.Error()))
+		}
+	}
+	if r.GetCreatedAt() == nil && len(r.GetSignature()) > 0 {
+		violations = append(violations, violation(prefix+"created_at", "created_at is required on signed log lines"))
+	}
+
+	return violations
+}
+
+func violation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+// invalidArgument returns invalid argument status carrying field violations as bad request details,
+// its message describes the first one
+func invalidArgument(msg string, violations []*errdetails.BadRequest_FieldViolation) error {
+	msg = fmt.Sprintf("%s, %s: %s", msg, violations[0].Field, violations[0].Description)
+	if len(violations) > 1 {
+		msg = fmt.Sprintf("%s, and %d more field violations", msg, len(violations)-1)
+	}
+
+	st := status.New(codes.InvalidArgument, msg)
+	ds, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+
+	return ds.Err()
+}
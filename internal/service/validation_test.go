@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/marcosQuesada/log-api/internal/proto/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestItRefusesInvalidLogLinesWithFieldViolations(t *testing.T) {
+	r := &fakeLogRepository{}
+	svc := NewLogService(r).WithLineLimits(LineLimits{MaxValueSize: 8})
+
+	for _, tc := range []struct {
+		name  string
+		req   *v1.CreateLogLineRequest
+		field string
+	}{
+		{"empty source", &v1.CreateLogLineRequest{Bucket: "debug", Value: "foo"}, "source"},
+		{"slashed source", &v1.CreateLogLineRequest{Source: "fake/source", Bucket: "debug", Value: "foo"}, "source"},
+		{"odd bucket", &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "de/bug", Value: "foo"}, "bucket"},
+		{"empty value", &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug"}, "value"},
+		{"oversized value", &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: strings.Repeat("x", 9)}, "value"},
+		{"invalid creation time", &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "foo", CreatedAt: &timestamppb.Timestamp{Nanos: -1}}, "created_at"},
+		{"signed without creation time", &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "foo", Signature: []byte("fake")}, "created_at"},
+	} {
+		_, err := svc.CreateLogLine(context.Background(), tc.req)
+		if expected, got := []string{tc.field}, violatedFields(t, err); len(got) != 1 || expected[0] != got[0] {
+			t.Errorf("%s violated fields do not match, expected %v got %v", tc.name, expected, got)
+		}
+	}
+
+	if expected, got := 0, len(r.lines); expected != got {
+		t.Errorf("stored lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItRefusesValuesStartingByReservedPrefixes(t *testing.T) {
+	r := &fakeLogRepository{}
+	ts := timestamppb.Now()
+	for _, tc := range []struct {
+		name string
+		svc  *LogService
+		req  *v1.CreateLogLineRequest
+	}{
+		{"forged signature without server key", NewLogService(r), &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "sig1:eyJrZXlfaWQiOiJmYWtlIn0=:foo", CreatedAt: ts}},
+		{"malformed signature with signatures enabled", NewLogService(r).WithSignatures(fakeSignatures{}), &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "sig1:foo", CreatedAt: ts}},
+		{"forged envelope", NewLogService(r), &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "enc1:foo"}},
+	} {
+		_, err := tc.svc.CreateLogLine(context.Background(), tc.req)
+		if expected, got := []string{"value"}, violatedFields(t, err); len(got) != 1 || expected[0] != got[0] {
+			t.Errorf("%s violated fields do not match, expected %v got %v", tc.name, expected, got)
+		}
+	}
+
+	if expected, got := 0, len(r.lines); expected != got {
+		t.Errorf("stored lines do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItRefusesBatchesOverMaxBatchSize(t *testing.T) {
+	r := &fakeLogRepository{}
+	svc := NewLogService(r).WithLineLimits(LineLimits{MaxBatchSize: 2})
+
+	line := &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "foo"}
+	_, err := svc.BatchCreateLogLines(context.Background(), &v1.BatchCreateLogLinesRequest{Lines: []*v1.CreateLogLineRequest{line, line, {Source: "fake/source"}}})
+
+	expected := []string{"lines", "lines[2].source", "lines[2].bucket", "lines[2].value"}
+	got := violatedFields(t, err)
+	if len(expected) != len(got) {
+		t.Fatalf("violated fields do not match, expected %v got %v", expected, got)
+	}
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Errorf("violated fields do not match, expected %v got %v", expected, got)
+		}
+	}
+}
+
+func TestItAssignsServerTimestampOnLogLinesWithoutCreationTime(t *testing.T) {
+	r := &fakeLogRepository{}
+	svc := NewLogService(r)
+
+	before := time.Now()
+	res, err := svc.CreateLogLine(context.Background(), &v1.CreateLogLineRequest{Source: "fake-source", Bucket: "debug", Value: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating log line %v", err)
+	}
+
+	if expected, got := 1, len(r.lines); expected != got {
+		t.Fatalf("stored lines do not match, expected %d got %d", expected, got)
+	}
+	if ts := r.lines[0].Time(); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("unexpected log line creation time %v", ts)
+	}
+
+	k, err := ParseLogLineKey(res.GetKey())
+	if err != nil {
+		t.Fatalf("unexpected error parsing key %v", err)
+	}
+	if expected, got := "fake-source", k.Source; expected != got {
+		t.Errorf("sources do not match, expected %s got %s", expected, got)
+	}
+	if expected, got := r.lines[0].Time().UnixNano(), k.Time.UnixNano(); expected != got {
+		t.Errorf("key creation times do not match, expected %d got %d", expected, got)
+	}
+}
+
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	st := status.Convert(err)
+	if expected, got := codes.InvalidArgument, st.Code(); expected != got {
+		t.Fatalf("status codes do not match, expected %s got %s, error %v", expected, got, err)
+	}
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+
+	return fields
+}
+
+type fakeLogRepository struct {
+	Repository
+	lines []*LogLine
+}
+
+func (f *fakeLogRepository) Add(ctx context.Context, line *LogLine) error {
+	f.lines = append(f.lines, line)
+	return nil
+}
+
+func (f *fakeLogRepository) AddBatch(ctx context.Context, lines []*LogLine) error {
+	f.lines = append(f.lines, lines...)
+	return nil
+}
+
+func (f *fakeLogRepository) CreateBatch(ctx context.Context, lines []*LogLine) error {
+	for _, line := range lines {
+		if _, err := f.GetByKey(ctx, line.key); err == nil {
+			return ErrLogLineExists
+		}
+	}
+	return f.AddBatch(ctx, lines)
+}
+
+func (f *fakeLogRepository) GetByKey(ctx context.Context, key string) (*LogLine, error) {
+	for _, l := range f.lines {
+		if l.key == key {
+			return l, nil
+		}
+	}
+	return nil, ErrLogLineNotFound
+}
+
+func (f *fakeLogRepository) GetByPrefix(ctx context.Context, prefix string) ([]*LogLine, error) {
+	res := []*LogLine{}
+	for _, l := range f.lines {
+		if strings.HasPrefix(string(l.Key()), prefix) {
+			res = append(res, l)
+		}
+	}
+	return res, nil
+}
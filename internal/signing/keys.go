@@ -0,0 +1,155 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// canonicalDomain separates log line signatures from any other ed25519 signature made with the same key
+const canonicalDomain = "log-api/log-line/v1"
+
+var (
+	ErrInvalidSigningKey = errors.New("signing key must be a 32 bytes ed25519 seed")
+	ErrInvalidPublicKey  = errors.New("invalid ed25519 public key")
+)
+
+// Canonical returns log line signed encoding, each field length prefixed so fields can not be shifted between them
+func Canonical(key, value, bucket string, createdAt time.Time) []byte {
+	buf := []byte(canonicalDomain)
+	size := make([]byte, 4)
+	for _, f := range []string{key, value, bucket} {
+		binary.BigEndian.PutUint32(size, uint32(len(f)))
+		buf = append(append(buf, size...), f...)
+	}
+
+	ts := make([]byte, 8)
+	binary.BigEndian.PutUint64(ts, uint64(createdAt.UnixNano()))
+
+	return append(buf, ts...)
+}
+
+// Verify checks signature of log line fields with public key
+func Verify(publicKey []byte, key, value, bucket string, createdAt time.Time, signature []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	return ed25519.Verify(publicKey, Canonical(key, value, bucket, createdAt), signature)
+}
+
+// KeyID identifies a public key by its hash
+func KeyID(pub ed25519.PublicKey) string {
+	h := sha256.Sum256(pub)
+	return hex.EncodeToString(h[:8])
+}
+
+// Keys holds server signing key and registered source public keys
+type Keys struct {
+	server   ed25519.PrivateKey
+	serverID string
+	sources  map[string]ed25519.PublicKey
+}
+
+// NewKeys instantiates signing keys, server seed is optional, without it just client signed lines get signatures
+func NewKeys(seed []byte, sources map[string]ed25519.PublicKey) (*Keys, error) {
+	k := &Keys{sources: sources}
+	if k.sources == nil {
+		k.sources = map[string]ed25519.PublicKey{}
+	}
+
+	if seed == nil {
+		return k, nil
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidSigningKey
+	}
+	k.server = ed25519.NewKeyFromSeed(seed)
+	k.serverID = KeyID(k.server.Public().(ed25519.PublicKey))
+
+	return k, nil
+}
+
+// LoadSourceKeys reads source public keys from a json file mapping each source to its base64 encoded public key
+func LoadSourceKeys(path string) (map[string]ed25519.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read source keys file %s, error %w", path, err)
+	}
+
+	encoded := map[string]string{}
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, fmt.Errorf("unable to decode source keys file %s, error %w", path, err)
+	}
+
+	keys := map[string]ed25519.PublicKey{}
+	for source, e := range encoded {
+		pub, err := base64.StdEncoding.DecodeString(e)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("source %s, error %w", source, ErrInvalidPublicKey)
+		}
+		keys[source] = pub
+	}
+
+	return keys, nil
+}
+
+// Sign signs log line with server key, returns nil without server key
+func (k *Keys) Sign(line *service.LogLine) *service.Signature {
+	if k.server == nil {
+		return nil
+	}
+
+	return &service.Signature{
+		KeyID: k.serverID,
+		Value: ed25519.Sign(k.server, canonicalLine(line)),
+	}
+}
+
+// VerifySource verifies client signature with its source registered public key
+func (k *Keys) VerifySource(source string, line *service.LogLine, signature []byte) (*service.Signature, error) {
+	pub, ok := k.sources[source]
+	if !ok {
+		return nil, fmt.Errorf("source %s has no registered key, error %w", source, service.ErrInvalidSignature)
+	}
+
+	if !ed25519.Verify(pub, canonicalLine(line), signature) {
+		return nil, fmt.Errorf("source %s signature does not verify, error %w", source, service.ErrInvalidSignature)
+	}
+
+	return &service.Signature{KeyID: KeyID(pub), Value: signature}, nil
+}
+
+// Keys returns public keys verifying log line signatures, server key goes first
+func (k *Keys) Keys() []*service.SigningKey {
+	var res []*service.SigningKey
+	if k.server != nil {
+		res = append(res, &service.SigningKey{ID: k.serverID, PublicKey: k.server.Public().(ed25519.PublicKey)})
+	}
+
+	sources := make([]string, 0, len(k.sources))
+	for s := range k.sources {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	for _, s := range sources {
+		res = append(res, &service.SigningKey{ID: KeyID(k.sources[s]), Source: s, PublicKey: k.sources[s]})
+	}
+
+	return res
+}
+
+func canonicalLine(l *service.LogLine) []byte {
+	return Canonical(string(l.Key()), string(l.Value()), l.Bucket(), l.Time())
+}
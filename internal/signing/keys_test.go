@@ -0,0 +1,58 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+func TestItVerifiesRegisteredSourceSignatures(t *testing.T) {
+	pub, priv := fakeSourceKey()
+	k, err := NewKeys(nil, map[string]ed25519.PublicKey{"fake_source": pub})
+	if err != nil {
+		t.Fatalf("unable to build keys, error %v", err)
+	}
+
+	line := service.NewSourceLogLine("fake_source", "fake_bucket", "fake value", time.Now())
+	sig := ed25519.Sign(priv, Canonical(string(line.Key()), "fake value", "fake_bucket", line.Time()))
+
+	s, err := k.VerifySource("fake_source", line, sig)
+	if err != nil {
+		t.Fatalf("unexpected error verifying signature, error %v", err)
+	}
+
+	if expected, got := KeyID(pub), s.KeyID; expected != got {
+		t.Errorf("key ids do not match, expected %s got %s", expected, got)
+	}
+
+	if _, err := k.VerifySource("another_source", line, sig); !errors.Is(err, service.ErrInvalidSignature) {
+		t.Errorf("unexpected error on unregistered source, got %v", err)
+	}
+
+	tampered := service.NewLogLineWithBucket("fake_bucket", string(line.Key()), "another value", line.Time())
+	if _, err := k.VerifySource("fake_source", tampered, sig); !errors.Is(err, service.ErrInvalidSignature) {
+		t.Errorf("unexpected error on tampered line, got %v", err)
+	}
+}
+
+func TestItEncodesCanonicalFieldsUnambiguously(t *testing.T) {
+	ts := time.Now()
+	if bytes.Equal(Canonical("foo_0", "ab", "c", ts), Canonical("foo_0", "a", "bc", ts)) {
+		t.Error("expected different encodings on shifted fields")
+	}
+}
+
+func TestItRefusesInvalidServerSigningKeys(t *testing.T) {
+	if _, err := NewKeys([]byte("short"), nil); !errors.Is(err, ErrInvalidSigningKey) {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func fakeSourceKey() (ed25519.PublicKey, ed25519.PrivateKey) {
+	priv := ed25519.NewKeyFromSeed([]byte("fedcba9876543210fedcba9876543210"))
+	return priv.Public().(ed25519.PublicKey), priv
+}
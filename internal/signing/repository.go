@@ -0,0 +1,270 @@
+package signing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+// signedPrefix tags signed values, anything else is handled as an unsigned value. Service validation refuses
+// client values starting by it, so they can not be taken as signed
+const signedPrefix = "sig1:"
+
+var errInvalidSignedValue = errors.New("invalid signed value")
+
+// header holds stored signature with the signed fields a log line read does not return
+type header struct {
+	KeyID     string `json:"key_id"`
+	Bucket    string `json:"bucket"`
+	CreatedAt int64  `json:"created_at"`
+	Signature []byte `json:"signature"`
+}
+
+type repository struct {
+	service.Repository
+	keys *Keys
+}
+
+// NewRepository decorates a repository storing log line signatures with its values. Client signed lines keep
+// their verified signature, unsigned lines get signed with server key. Reads return lines with its signature
+func NewRepository(r service.Repository, k *Keys) *repository {
+	return &repository{
+		Repository: r,
+		keys:       k,
+	}
+}
+
+// Add signs log line and stores it
+func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
+	return r.Repository.Add(ctx, r.sign(line))
+}
+
+// AddBatch signs all log lines and stores them in a batch
+func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	ls := make([]*service.LogLine, 0, len(lines))
+	for _, line := range lines {
+		ls = append(ls, r.sign(line))
+	}
+
+	return r.Repository.AddBatch(ctx, ls)
+}
+
+// CreateBatch signs all log lines and stores them as new lines
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	ls := make([]*service.LogLine, 0, len(lines))
+	for _, line := range lines {
+		ls = append(ls, r.sign(line))
+	}
+
+	return r.Repository.CreateBatch(ctx, ls)
+}
+
+// History returns key revisions values without its signatures
+func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	h, err := r.Repository.History(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHistory(h)
+}
+
+// Histories returns keys revisions values without its signatures
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	hs, err := r.Repository.Histories(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*service.LogLineHistory, 0, len(hs))
+	for _, h := range hs {
+		d, err := decodeHistory(h)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, nil
+}
+
+// RevisionHistory returns key revisions values without its signatures, with its transaction timestamp and writer
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	h, err := r.Repository.RevisionHistory(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHistory(h)
+}
+
+// GetByKey returns log line with its signature
+func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	l, err := r.Repository.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLine(l)
+}
+
+// GetByPrefix returns log lines with its signatures
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLines(ls)
+}
+
+// GetLastNLogLines returns log lines with its signatures
+func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetLastNLogLines(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLines(ls)
+}
+
+// GetByBucket returns log lines with its signatures
+func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByBucket(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLines(ls)
+}
+
+// GetByKeyAt returns log line as it was on transaction tx with its signature
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	l, err := r.Repository.GetByKeyAt(ctx, key, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLine(l)
+}
+
+// GetByPrefixAt returns log lines as they were on transaction tx with its signatures
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByPrefixAt(ctx, prefix, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLines(ls)
+}
+
+// GetByBucketAt returns log lines as they were on transaction tx with its signatures
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	ls, err := r.Repository.GetByBucketAt(ctx, bucket, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLines(ls)
+}
+
+// ExportByBucket hands log lines values without its signatures to fn
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	return r.Repository.ExportByBucket(ctx, bucket, from, to, func(e *service.ExportedLogLine) error {
+		v, _, err := decode(e.Key, string(e.Value))
+		if err != nil {
+			return err
+		}
+		e.Value = []byte(v)
+
+		return fn(e)
+	})
+}
+
+func (r *repository) sign(line *service.LogLine) *service.LogLine {
+	s := line.Signature()
+	if s == nil {
+		s = r.keys.Sign(line)
+	}
+	if s == nil {
+		return line
+	}
+
+	h, _ := json.Marshal(&header{
+		KeyID:     s.KeyID,
+		Bucket:    line.Bucket(),
+		CreatedAt: line.Time().UnixNano(),
+		Signature: s.Value,
+	})
+
+	v := signedPrefix + base64.StdEncoding.EncodeToString(h) + ":" + string(line.Value())
+	return service.NewLogLineWithBucket(line.Bucket(), string(line.Key()), v, line.Time())
+}
+
+func decodeHistory(h *service.LogLineHistory) (*service.LogLineHistory, error) {
+	for _, rv := range h.Revision {
+		v, _, err := decode(h.Key, string(rv.Value))
+		if err != nil {
+			return nil, err
+		}
+		rv.Value = []byte(v)
+	}
+
+	return h, nil
+}
+
+func decodeLines(ls []*service.LogLine) ([]*service.LogLine, error) {
+	res := make([]*service.LogLine, 0, len(ls))
+	for _, l := range ls {
+		d, err := decodeLine(l)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, nil
+}
+
+func decodeLine(l *service.LogLine) (*service.LogLine, error) {
+	v, h, err := decode(string(l.Key()), string(l.Value()))
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return l, nil
+	}
+
+	line := service.NewLogLineWithBucket(h.Bucket, string(l.Key()), v, time.Unix(0, h.CreatedAt).UTC())
+	return line.WithSignature(&service.Signature{KeyID: h.KeyID, Value: h.Signature}), nil
+}
+
+// decode splits stored value from its signature header, unsigned values have no header
+func decode(lineKey, value string) (string, *header, error) {
+	if !strings.HasPrefix(value, signedPrefix) {
+		return value, nil, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, signedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", nil, fmt.Errorf("unable to decode key %s value, error %w", lineKey, errInvalidSignedValue)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to decode key %s signature, error %w", lineKey, err)
+	}
+
+	h := &header{}
+	if err := json.Unmarshal(raw, h); err != nil {
+		return "", nil, fmt.Errorf("unable to decode key %s signature, error %w", lineKey, err)
+	}
+
+	return parts[1], h, nil
+}
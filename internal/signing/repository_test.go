@@ -0,0 +1,149 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/linekey"
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+var fakeServerSeed = []byte("0123456789abcdef0123456789abcdef")
+
+func TestItSignsLogLinesWithServerKeyAndReturnsSignaturesOnRead(t *testing.T) {
+	fr := newFakeRepository()
+	k, err := NewKeys(fakeServerSeed, nil)
+	if err != nil {
+		t.Fatalf("unable to build keys, error %v", err)
+	}
+	r := NewRepository(fr, k)
+	ctx := context.Background()
+
+	ts := time.Now()
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "foo_0", "fake value", ts)); err != nil {
+		t.Fatalf("unable to add log line, error %v", err)
+	}
+
+	if stored := string(fr.lines["foo_0"].Value()); !strings.HasPrefix(stored, signedPrefix) {
+		t.Fatalf("expected signed value, got %s", stored)
+	}
+
+	l, err := r.GetByKey(ctx, "foo_0")
+	if err != nil {
+		t.Fatalf("unable to get by key, error %v", err)
+	}
+
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if l.Signature() == nil {
+		t.Fatal("expected signature")
+	}
+
+	server := k.Keys()[0]
+	if expected, got := server.ID, l.Signature().KeyID; expected != got {
+		t.Errorf("key ids do not match, expected %s got %s", expected, got)
+	}
+	if !Verify(server.PublicKey, "foo_0", "fake value", l.Bucket(), l.Time(), l.Signature().Value) {
+		t.Error("expected signature to verify")
+	}
+	if !l.Time().Equal(ts) {
+		t.Errorf("creation times do not match, expected %s got %s", ts, l.Time())
+	}
+}
+
+func TestItKeepsClientSignatures(t *testing.T) {
+	fr := newFakeRepository()
+	pub, priv := fakeSourceKey()
+	k, err := NewKeys(fakeServerSeed, map[string]ed25519.PublicKey{"fake_source": pub})
+	if err != nil {
+		t.Fatalf("unable to build keys, error %v", err)
+	}
+	r := NewRepository(fr, k)
+	ctx := context.Background()
+
+	line := service.NewSourceLogLine("fake_source", "fake_bucket", "fake value", time.Now())
+	s, err := k.VerifySource("fake_source", line, ed25519.Sign(priv, canonicalLine(line)))
+	if err != nil {
+		t.Fatalf("unable to verify signature, error %v", err)
+	}
+	if err := r.AddBatch(ctx, []*service.LogLine{line.WithSignature(s)}); err != nil {
+		t.Fatalf("unable to add batch, error %v", err)
+	}
+
+	all, err := r.GetByPrefix(ctx, linekey.SourcePrefix("fake_source"))
+	if err != nil {
+		t.Fatalf("unable to get by prefix, error %v", err)
+	}
+
+	if expected, got := 1, len(all); expected != got {
+		t.Fatalf("lines do not match, expected %d got %d", expected, got)
+	}
+	if expected, got := KeyID(pub), all[0].Signature().KeyID; expected != got {
+		t.Errorf("key ids do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItPassesThroughUnsignedValuesWithoutServerKey(t *testing.T) {
+	fr := newFakeRepository()
+	k, err := NewKeys(nil, nil)
+	if err != nil {
+		t.Fatalf("unable to build keys, error %v", err)
+	}
+	r := NewRepository(fr, k)
+	ctx := context.Background()
+
+	if err := r.Add(ctx, service.NewLogLineWithBucket("fake_bucket", "foo_0", "fake value", time.Now())); err != nil {
+		t.Fatalf("unable to add log line, error %v", err)
+	}
+
+	l, err := r.GetByKey(ctx, "foo_0")
+	if err != nil {
+		t.Fatalf("unable to get by key, error %v", err)
+	}
+
+	if expected, got := "fake value", string(l.Value()); expected != got {
+		t.Errorf("values do not match, expected %s got %s", expected, got)
+	}
+	if l.Signature() != nil {
+		t.Error("expected unsigned line")
+	}
+}
+
+type fakeRepository struct {
+	service.Repository
+	lines map[string]*service.LogLine
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{lines: map[string]*service.LogLine{}}
+}
+
+func (f *fakeRepository) Add(ctx context.Context, line *service.LogLine) error {
+	f.lines[string(line.Key())] = line
+	return nil
+}
+
+func (f *fakeRepository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	for _, line := range lines {
+		f.lines[string(line.Key())] = line
+	}
+	return nil
+}
+
+func (f *fakeRepository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	return service.NewLogLine(key, string(f.lines[key].Value())), nil
+}
+
+func (f *fakeRepository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	res := []*service.LogLine{}
+	for k, l := range f.lines {
+		if strings.HasPrefix(k, prefix) {
+			res = append(res, service.NewLogLine(k, string(l.Value())))
+		}
+	}
+	return res, nil
+}
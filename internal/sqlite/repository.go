@@ -0,0 +1,447 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+	_ "modernc.org/sqlite"
+)
+
+// schema keeps each write as a transaction with its log line revisions, lines table holds last revision
+// with its bucket and creation time, set on its first write as immudb sorted sets do
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS txs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		committed_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS lines (
+		key TEXT PRIMARY KEY,
+		bucket TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		value BLOB NOT NULL,
+		tx INTEGER NOT NULL,
+		revision INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS lines_bucket ON lines (bucket, created_at, key)`,
+	`CREATE INDEX IF NOT EXISTS lines_tx ON lines (tx)`,
+	`CREATE TABLE IF NOT EXISTS revisions (
+		key TEXT NOT NULL,
+		revision INTEGER NOT NULL,
+		tx INTEGER NOT NULL,
+		value BLOB NOT NULL,
+		principal TEXT NOT NULL,
+		PRIMARY KEY (key, revision)
+	)`,
+	`CREATE TABLE IF NOT EXISTS buckets (
+		name TEXT PRIMARY KEY,
+		data BLOB NOT NULL
+	)`,
+}
+
+// valueAt selects key value as it was on a transaction, it takes key and transaction as arguments
+const valueAt = `SELECT value FROM revisions WHERE key = ? AND tx <= ? ORDER BY revision DESC LIMIT 1`
+
+// lineValueAt selects lines row value as it was on a transaction, it takes the transaction as argument
+const lineValueAt = `(SELECT value FROM revisions WHERE key = l.key AND tx <= ? ORDER BY revision DESC LIMIT 1)`
+
+type repository struct {
+	db *sql.DB
+}
+
+// Open opens sqlite database on path, a single connection serializes writes
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite database %s, error %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// NewRepository instantiates new sqlite repository
+func NewRepository(db *sql.DB) *repository {
+	return &repository{db: db}
+}
+
+// Initialize creates repository tables
+func (r *repository) Initialize(ctx context.Context) error {
+	for _, stmt := range schema {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("unable to create sqlite schema, error %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Add stores logLine as a new transaction, existing keys get a new revision
+func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
+	return r.AddBatch(ctx, []*service.LogLine{line})
+}
+
+// AddBatch stores logLines on a single transaction
+func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	return r.addBatch(ctx, lines, false)
+}
+
+// CreateBatch stores new logLines on a single transaction, none is stored if any key already is
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	return r.addBatch(ctx, lines, true)
+}
+
+func (r *repository) addBatch(ctx context.Context, lines []*service.LogLine, create bool) error {
+	var writer string
+	if p, ok := principal.FromContext(ctx); ok {
+		writer = p.ID
+	}
+
+	dbTx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction, error %w", err)
+	}
+	defer dbTx.Rollback()
+
+	res, err := dbTx.ExecContext(ctx, `INSERT INTO txs (committed_at) VALUES (?)`, time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("unable to insert transaction, error %w", err)
+	}
+	tx, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("unable to get transaction id, error %w", err)
+	}
+
+	for _, line := range lines {
+		key := string(line.Key())
+		var revision uint64
+		err := dbTx.QueryRowContext(ctx, `SELECT revision FROM lines WHERE key = ?`, key).Scan(&revision)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("unable to get key %s revision, error %w", key, err)
+		}
+		if create && revision > 0 {
+			return fmt.Errorf("unable to create key %s, error %w", key, service.ErrLogLineExists)
+		}
+		revision++
+
+		if revision == 1 {
+			_, err = dbTx.ExecContext(ctx, `INSERT INTO lines (key, bucket, created_at, value, tx, revision) VALUES (?, ?, ?, ?, ?, ?)`,
+				key, line.Bucket(), line.Time().UnixNano(), line.Value(), tx, revision)
+		} else {
+			_, err = dbTx.ExecContext(ctx, `UPDATE lines SET value = ?, tx = ?, revision = ? WHERE key = ?`, line.Value(), tx, revision, key)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to store key %s, error %w", key, err)
+		}
+
+		_, err = dbTx.ExecContext(ctx, `INSERT INTO revisions (key, revision, tx, value, principal) VALUES (?, ?, ?, ?, ?)`,
+			key, revision, tx, line.Value(), writer)
+		if err != nil {
+			return fmt.Errorf("unable to store key %s revision, error %w", key, err)
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction, error %w", err)
+	}
+
+	return nil
+}
+
+// History returns all revisions from a key
+func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	return r.history(ctx, key, false)
+}
+
+// Histories returns keys revisions in keys order, embedded database queries take no round trip
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	res := make([]*service.LogLineHistory, 0, len(keys))
+	for _, k := range keys {
+		h, err := r.history(ctx, k, false)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, h)
+	}
+
+	return res, nil
+}
+
+// RevisionHistory returns key revisions with its transaction time and writer principal
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	return r.history(ctx, key, true)
+}
+
+func (r *repository) history(ctx context.Context, key string, detailed bool) (*service.LogLineHistory, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT r.value, r.tx, r.revision, t.committed_at, r.principal
+		FROM revisions r JOIN txs t ON t.id = r.tx WHERE r.key = ? ORDER BY r.revision`, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key %s history, error %w", key, err)
+	}
+	defer rows.Close()
+
+	h := &service.LogLineHistory{Key: key}
+	for rows.Next() {
+		rv := &service.LogLineRevision{}
+		var committedAt int64
+		var writer string
+		if err := rows.Scan(&rv.Value, &rv.Tx, &rv.Revision, &committedAt, &writer); err != nil {
+			return nil, fmt.Errorf("unable to read key %s history, error %w", key, err)
+		}
+		if detailed {
+			rv.CommittedAt = time.Unix(0, committedAt).UTC()
+			rv.Principal = writer
+		}
+		h.Revision = append(h.Revision, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read key %s history, error %w", key, err)
+	}
+
+	if len(h.Revision) == 0 {
+		return nil, fmt.Errorf("unable to get key %s history, error %w", key, service.ErrLogLineNotFound)
+	}
+
+	return h, nil
+}
+
+// Count returns total log lines
+func (r *repository) Count(ctx context.Context) (uint64, error) {
+	var total uint64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM lines`).Scan(&total); err != nil {
+		return 0, fmt.Errorf("unable to count lines, error %w", err)
+	}
+
+	return total, nil
+}
+
+// GetByKey returns logLine by Key
+func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	var value []byte
+	err := r.db.QueryRowContext(ctx, `SELECT value FROM lines WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unable to get key %s error %w", key, service.ErrLogLineNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key %s error %w", key, err)
+	}
+
+	return service.DecodeLogLine(key, string(value)), nil
+}
+
+// GetByPrefix gets logLines with prefixed key sorted by key
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	return r.query(ctx, `SELECT key, value FROM lines WHERE instr(key, ?) = 1 ORDER BY key`, prefix)
+}
+
+// GetLastNLogLines gets last N written logLines, newest first
+func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
+	if n <= 0 {
+		return []*service.LogLine{}, nil
+	}
+
+	return r.query(ctx, `SELECT key, value FROM lines ORDER BY tx DESC, key LIMIT ?`, n)
+}
+
+// GetByBucket gets bucket logLines sorted by creation time
+func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
+	return r.query(ctx, `SELECT key, value FROM lines WHERE bucket = ? ORDER BY created_at, key`, bucket)
+}
+
+// TxAt returns last transaction committed at or before t, zero if no transaction was committed by then
+func (r *repository) TxAt(ctx context.Context, t time.Time) (uint64, error) {
+	var tx uint64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM txs WHERE committed_at <= ?`, t.UnixNano()).Scan(&tx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get tx at %v, error %w", t, err)
+	}
+
+	return tx, nil
+}
+
+// GetByKeyAt returns logLine value as it was on transaction tx
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	var value []byte
+	err := r.db.QueryRowContext(ctx, valueAt, key, tx).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unable to get key %s on tx %d, error %w", key, tx, service.ErrLogLineNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key %s on tx %d, error %w", key, tx, err)
+	}
+
+	return service.DecodeLogLine(key, string(value)), nil
+}
+
+// GetByPrefixAt gets prefixed logLines as they were on transaction tx sorted by key
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	return r.query(ctx, `SELECT key, v FROM (SELECT l.key, `+lineValueAt+` AS v FROM lines l WHERE instr(l.key, ?) = 1)
+		WHERE v IS NOT NULL ORDER BY key`, tx, prefix)
+}
+
+// GetByBucketAt gets bucket logLines as they were on transaction tx sorted by creation time
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	return r.query(ctx, `SELECT key, v FROM (SELECT l.key, l.created_at, `+lineValueAt+` AS v FROM lines l WHERE l.bucket = ?)
+		WHERE v IS NOT NULL ORDER BY created_at, key`, tx, bucket)
+}
+
+// ExportByBucket walks bucket log lines created on [from, to], zero times leave the range open
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	q := `SELECT key, value, tx, revision, created_at FROM lines WHERE bucket = ?`
+	args := []interface{}{bucket}
+	if !from.IsZero() {
+		q += ` AND created_at >= ?`
+		args = append(args, from.UnixNano())
+	}
+	if !to.IsZero() {
+		q += ` AND created_at <= ?`
+		args = append(args, to.UnixNano())
+	}
+
+	rows, err := r.db.QueryContext(ctx, q+` ORDER BY created_at, key`, args...)
+	if err != nil {
+		return fmt.Errorf("unable to scan bucket %s, error %w", bucket, err)
+	}
+	defer rows.Close()
+
+	res := []*service.ExportedLogLine{}
+	for rows.Next() {
+		l := &service.ExportedLogLine{Bucket: bucket}
+		var createdAt int64
+		if err := rows.Scan(&l.Key, &l.Value, &l.Tx, &l.Revision, &createdAt); err != nil {
+			return fmt.Errorf("unable to read bucket %s, error %w", bucket, err)
+		}
+		l.CreatedAt = time.Unix(0, createdAt).Round(time.Microsecond).UTC()
+		res = append(res, l)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to read bucket %s, error %w", bucket, err)
+	}
+	rows.Close()
+
+	for _, l := range res {
+		if err := fn(l); err != nil {
+			return fmt.Errorf("unable to export key %s, error %w", l.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateBucket stores bucket metadata, fails if it already exists
+func (r *repository) CreateBucket(ctx context.Context, b *service.Bucket) error {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("unable to marshal bucket %s, error %w", b.Name, err)
+	}
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO buckets (name, data) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`, b.Name, raw)
+	if err != nil {
+		return fmt.Errorf("unable to create bucket %s, error %w", b.Name, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("unable to create bucket %s, error %w", b.Name, service.ErrBucketAlreadyExists)
+	}
+
+	return nil
+}
+
+// UpdateBucket stores bucket metadata
+func (r *repository) UpdateBucket(ctx context.Context, b *service.Bucket) error {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("unable to marshal bucket %s, error %w", b.Name, err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `INSERT INTO buckets (name, data) VALUES (?, ?) ON CONFLICT (name) DO UPDATE SET data = excluded.data`, b.Name, raw); err != nil {
+		return fmt.Errorf("unable to update bucket %s, error %w", b.Name, err)
+	}
+
+	return nil
+}
+
+// GetBucket returns bucket metadata
+func (r *repository) GetBucket(ctx context.Context, name string) (*service.Bucket, error) {
+	var raw []byte
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM buckets WHERE name = ?`, name).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unable to get bucket %s, error %w", name, service.ErrBucketNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get bucket %s, error %w", name, err)
+	}
+
+	b := &service.Bucket{}
+	if err := json.Unmarshal(raw, b); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal bucket %s, error %w", name, err)
+	}
+
+	return b, nil
+}
+
+// ListBuckets returns all buckets metadata sorted by name
+func (r *repository) ListBuckets(ctx context.Context) ([]*service.Bucket, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name, data FROM buckets ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan buckets, error %w", err)
+	}
+	defer rows.Close()
+
+	res := []*service.Bucket{}
+	for rows.Next() {
+		var name string
+		var raw []byte
+		if err := rows.Scan(&name, &raw); err != nil {
+			return nil, fmt.Errorf("unable to read buckets, error %w", err)
+		}
+		b := &service.Bucket{}
+		if err := json.Unmarshal(raw, b); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal bucket %s, error %w", name, err)
+		}
+		res = append(res, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read buckets, error %w", err)
+	}
+
+	return res, nil
+}
+
+// CountBucketLines returns total bucket log lines
+func (r *repository) CountBucketLines(ctx context.Context, name string) (uint64, error) {
+	var total uint64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM lines WHERE bucket = ?`, name).Scan(&total); err != nil {
+		return 0, fmt.Errorf("unable to count bucket %s lines, error %w", name, err)
+	}
+
+	return total, nil
+}
+
+// query returns log lines from key and value rows
+func (r *repository) query(ctx context.Context, q string, args ...interface{}) ([]*service.LogLine, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query lines, error %w", err)
+	}
+	defer rows.Close()
+
+	logs := []*service.LogLine{}
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("unable to read lines, error %w", err)
+		}
+		logs = append(logs, service.DecodeLogLine(key, string(value)))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read lines, error %w", err)
+	}
+
+	return logs, nil
+}
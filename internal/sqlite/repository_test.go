@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcosQuesada/log-api/internal/repotest"
+)
+
+func TestItPassesRepositoryConformance(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "log-api.db"))
+	if err != nil {
+		t.Fatalf("unexpected error opening database, error %v", err)
+	}
+	defer db.Close()
+
+	r := NewRepository(db)
+	if err := r.Initialize(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing repository, error %v", err)
+	}
+
+	repotest.Run(t, r)
+}
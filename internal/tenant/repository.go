@@ -0,0 +1,196 @@
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/service"
+)
+
+type repository struct {
+	router *Router
+}
+
+// NewRepository instantiates a repository routing each call to the request tenant repositories
+func NewRepository(r *Router) *repository {
+	return &repository{router: r}
+}
+
+func (r *repository) Add(ctx context.Context, line *service.LogLine) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Logs.Add(ctx, line)
+}
+
+func (r *repository) AddBatch(ctx context.Context, lines []*service.LogLine) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Logs.AddBatch(ctx, lines)
+}
+
+func (r *repository) CreateBatch(ctx context.Context, lines []*service.LogLine) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Logs.CreateBatch(ctx, lines)
+}
+
+func (r *repository) History(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.History(ctx, key)
+}
+
+func (r *repository) Histories(ctx context.Context, keys []string) ([]*service.LogLineHistory, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.Histories(ctx, keys)
+}
+
+func (r *repository) RevisionHistory(ctx context.Context, key string) (*service.LogLineHistory, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.RevisionHistory(ctx, key)
+}
+
+func (r *repository) Count(ctx context.Context) (uint64, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return st.Logs.Count(ctx)
+}
+
+func (r *repository) GetByKey(ctx context.Context, key string) (*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByKey(ctx, key)
+}
+
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) ([]*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByPrefix(ctx, prefix)
+}
+
+func (r *repository) GetLastNLogLines(ctx context.Context, n int) ([]*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetLastNLogLines(ctx, n)
+}
+
+func (r *repository) GetByBucket(ctx context.Context, bucket string) ([]*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByBucket(ctx, bucket)
+}
+
+func (r *repository) TxAt(ctx context.Context, t time.Time) (uint64, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return st.Logs.TxAt(ctx, t)
+}
+
+func (r *repository) GetByKeyAt(ctx context.Context, key string, tx uint64) (*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByKeyAt(ctx, key, tx)
+}
+
+func (r *repository) GetByPrefixAt(ctx context.Context, prefix string, tx uint64) ([]*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByPrefixAt(ctx, prefix, tx)
+}
+
+func (r *repository) GetByBucketAt(ctx context.Context, bucket string, tx uint64) ([]*service.LogLine, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Logs.GetByBucketAt(ctx, bucket, tx)
+}
+
+func (r *repository) ExportByBucket(ctx context.Context, bucket string, from, to time.Time, fn func(*service.ExportedLogLine) error) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Logs.ExportByBucket(ctx, bucket, from, to, fn)
+}
+
+func (r *repository) CreateBucket(ctx context.Context, b *service.Bucket) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Buckets.CreateBucket(ctx, b)
+}
+
+func (r *repository) UpdateBucket(ctx context.Context, b *service.Bucket) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	return st.Buckets.UpdateBucket(ctx, b)
+}
+
+func (r *repository) GetBucket(ctx context.Context, name string) (*service.Bucket, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Buckets.GetBucket(ctx, name)
+}
+
+func (r *repository) ListBuckets(ctx context.Context) ([]*service.Bucket, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Buckets.ListBuckets(ctx)
+}
+
+func (r *repository) CountBucketLines(ctx context.Context, name string) (uint64, error) {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return st.Buckets.CountBucketLines(ctx, name)
+}
+
+func (r *repository) AuditByBucket(ctx context.Context, bucket string, from, to time.Time, state func(*service.AuditState) error, fn func(*service.AuditedLogLine) error) error {
+	st, err := r.router.Stack(ctx)
+	if err != nil {
+		return err
+	}
+	if st.Audit == nil {
+		return service.ErrAuditUnsupported
+	}
+	return st.Audit.AuditByBucket(ctx, bucket, from, to, state, fn)
+}
@@ -0,0 +1,231 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"github.com/marcosQuesada/log-api/internal/service"
+	"github.com/marcosQuesada/log-api/internal/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidTenant  = errors.New("invalid tenant id")
+	ErrTenantRequired = errors.New("tenant required")
+	ErrUnknownTenant  = errors.New("unknown tenant")
+	ErrNotInitialized = errors.New("database not initialized")
+)
+
+// buildTimeout bounds database stack builds, they run detached from the request triggering them
+const buildTimeout = 30 * time.Second
+
+var (
+	tenantRegexp    = regexp.MustCompile(`^[a-z0-9]{1,64}$`)
+	reservedTenants = map[string]struct{}{"defaultdb": {}, "systemdb": {}}
+)
+
+// Stack holds tenant repositories on top of its own storage, as its immudb database session.
+// Close releases its storage, Ready checks it is usable, audit is nil on storages without proofs
+type Stack struct {
+	Close   func(ctx context.Context) error
+	Ready   func(ctx context.Context) error
+	Logs    service.Repository
+	Buckets service.BucketRepository
+	Audit   service.AuditRepository
+}
+
+// Builder opens database storage and builds its repositories
+type Builder func(ctx context.Context, database string) (*Stack, error)
+
+// Router routes each request to its tenant stack, tenants are mapped to immudb databases with the same name.
+// Requests without tenant are routed to the default database unless tenant is required
+type Router struct {
+	builder         Builder
+	defaultDatabase string
+	required        bool
+
+	mutex    sync.Mutex
+	stacks   map[string]*Stack
+	building map[string]*build
+}
+
+// build is an in flight database stack build, done gets closed once finished
+type build struct {
+	done  chan struct{}
+	stack *Stack
+	err   error
+}
+
+// NewRouter instantiates tenant router
+func NewRouter(b Builder, defaultDatabase string, required bool) *Router {
+	return &Router{
+		builder:         b,
+		defaultDatabase: defaultDatabase,
+		required:        required,
+		stacks:          map[string]*Stack{},
+		building:        map[string]*build{},
+	}
+}
+
+// Validate checks tenant id can be used as immudb database name
+func Validate(tenant string) error {
+	if !tenantRegexp.MatchString(tenant) {
+		return fmt.Errorf("tenant %q must be lowercase alphanumeric, error %w", tenant, ErrInvalidTenant)
+	}
+	if _, ok := reservedTenants[tenant]; ok {
+		return fmt.Errorf("tenant %q is reserved, error %w", tenant, ErrInvalidTenant)
+	}
+
+	return nil
+}
+
+// Stack returns request tenant stack, its session gets opened on first usage. Stacks are built without holding
+// the router lock, so slow databases do not block other tenants, concurrent requests wait for the same build
+func (r *Router) Stack(ctx context.Context) (*Stack, error) {
+	database, err := r.database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	if st, ok := r.stacks[database]; ok {
+		r.mutex.Unlock()
+		return st, nil
+	}
+	b, ok := r.building[database]
+	if !ok {
+		b = &build{done: make(chan struct{})}
+		r.building[database] = b
+		go r.build(tracing.Detach(ctx), database, b)
+	}
+	r.mutex.Unlock()
+
+	select {
+	case <-b.done:
+		return b.stack, b.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("unable to build database %s stack, error %w", database, ctx.Err())
+	}
+}
+
+// build opens database stack, it gets registered on success, failed builds are retried by next request.
+// It is shared by every request waiting on database, so it does not follow any request cancellation
+func (r *Router) build(ctx context.Context, database string, b *build) {
+	ctx, cancel := context.WithTimeout(ctx, buildTimeout)
+	defer cancel()
+
+	st, err := r.builder(ctx, database)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	defer close(b.done)
+
+	delete(r.building, database)
+	if err != nil {
+		b.err = fmt.Errorf("unable to build database %s stack, error %w", database, err)
+		return
+	}
+	r.stacks[database] = st
+	b.stack = st
+	log.Printf("Opened tenant database %s session", database)
+}
+
+// Interceptor resolves request tenant stack once authenticated, so unknown tenants are refused early
+func (r *Router) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := r.resolve(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor resolves stream tenant stack once authenticated
+func (r *Router) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := r.resolve(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (r *Router) resolve(ctx context.Context) error {
+	if _, ok := principal.FromContext(ctx); !ok {
+		return nil
+	}
+
+	if _, err := r.Stack(ctx); err != nil {
+		if errors.Is(err, ErrInvalidTenant) || errors.Is(err, ErrTenantRequired) || errors.Is(err, ErrUnknownTenant) {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		return status.Errorf(codes.Unavailable, "tenant unavailable, %v", err)
+	}
+
+	return nil
+}
+
+// Ready checks opened tenant storages are usable, default database must be initialized unless tenant is required
+func (r *Router) Ready(ctx context.Context) error {
+	r.mutex.Lock()
+	databases := make([]string, 0, len(r.stacks))
+	stacks := make(map[string]*Stack, len(r.stacks))
+	for database, st := range r.stacks {
+		databases = append(databases, database)
+		stacks[database] = st
+	}
+	r.mutex.Unlock()
+
+	if _, ok := stacks[r.defaultDatabase]; !ok && !r.required {
+		return fmt.Errorf("default database %s, error %w", r.defaultDatabase, ErrNotInitialized)
+	}
+
+	sort.Strings(databases)
+	for _, database := range databases {
+		if st := stacks[database]; st.Ready != nil {
+			if err := st.Ready(ctx); err != nil {
+				return fmt.Errorf("database %s not ready, error %w", database, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes all opened tenant storages
+func (r *Router) Close(ctx context.Context) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for database, st := range r.stacks {
+		if st.Close != nil {
+			if err := st.Close(ctx); err != nil {
+				log.Printf("unable to close database %s session, error %v", database, err)
+			}
+		}
+		delete(r.stacks, database)
+	}
+}
+
+func (r *Router) database(ctx context.Context) (string, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok || p.Tenant == "" {
+		if r.required {
+			return "", ErrTenantRequired
+		}
+		return r.defaultDatabase, nil
+	}
+
+	if err := Validate(p.Tenant); err != nil {
+		return "", err
+	}
+
+	return p.Tenant, nil
+}
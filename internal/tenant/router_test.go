@@ -0,0 +1,169 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcosQuesada/log-api/internal/principal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItRoutesRequestsToTenantDatabaseReusingItsStack(t *testing.T) {
+	b := &fakeBuilder{}
+	r := NewRouter(b.build, "defaultdb", false)
+
+	acme := principal.NewContext(context.Background(), &principal.Principal{Tenant: "acme"})
+	for i := 0; i < 2; i++ {
+		if _, err := r.Stack(acme); err != nil {
+			t.Fatalf("unexpected error resolving stack, error %v", err)
+		}
+	}
+
+	if _, err := r.Stack(context.Background()); err != nil {
+		t.Fatalf("unexpected error resolving stack, error %v", err)
+	}
+
+	if expected, got := []string{"acme", "defaultdb"}, b.databases; len(expected) != len(got) || expected[0] != got[0] || expected[1] != got[1] {
+		t.Errorf("built databases do not match, expected %v got %v", expected, got)
+	}
+}
+
+func TestItBuildsTenantStacksOnceWithoutBlockingOtherTenants(t *testing.T) {
+	release := make(chan struct{})
+	var builds int32
+	r := NewRouter(func(ctx context.Context, database string) (*Stack, error) {
+		if database == "acme" {
+			atomic.AddInt32(&builds, 1)
+			<-release
+		}
+		return &Stack{}, nil
+	}, "defaultdb", false)
+
+	acme := principal.NewContext(context.Background(), &principal.Principal{Tenant: "acme"})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Stack(acme); err != nil {
+				t.Errorf("unexpected error resolving stack, error %v", err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := r.Stack(ctx); err != nil {
+		t.Errorf("unexpected error resolving default stack while acme builds, error %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if expected, got := int32(1), atomic.LoadInt32(&builds); expected != got {
+		t.Errorf("acme builds do not match, expected %d got %d", expected, got)
+	}
+}
+
+func TestItKeepsBuildingTenantStacksWhenTriggeringRequestIsCancelled(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	r := NewRouter(func(ctx context.Context, database string) (*Stack, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &Stack{}, nil
+	}, "defaultdb", false)
+
+	first, cancel := context.WithCancel(principal.NewContext(context.Background(), &principal.Principal{Tenant: "acme"}))
+	errs := make(chan error, 1)
+	go func() {
+		_, err := r.Stack(first)
+		errs <- err
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := r.Stack(principal.NewContext(context.Background(), &principal.Principal{Tenant: "acme"}))
+		waiter <- err
+	}()
+
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error on cancelled request, got %v", err)
+	}
+
+	close(release)
+	if err := <-waiter; err != nil {
+		t.Errorf("unexpected error resolving stack, error %v", err)
+	}
+}
+
+func TestItRefusesRequestsWithoutTenantWhenRequired(t *testing.T) {
+	r := NewRouter((&fakeBuilder{}).build, "defaultdb", true)
+
+	ctx := principal.NewContext(context.Background(), &principal.Principal{ID: "fake_principal"})
+	if _, err := r.Stack(ctx); !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+
+	_, err := r.Interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.FakeService/Foo"}, nopUnaryHandler)
+	if expected, got := codes.PermissionDenied, status.Code(err); expected != got {
+		t.Errorf("unexpected code, expected %v got %v", expected, got)
+	}
+}
+
+func TestItValidatesTenantIds(t *testing.T) {
+	for _, id := range []string{"", "Acme", "acme-corp", "defaultdb", "systemdb"} {
+		if err := Validate(id); !errors.Is(err, ErrInvalidTenant) {
+			t.Errorf("unexpected error type on %q, got %v", id, err)
+		}
+	}
+
+	if err := Validate("acme01"); err != nil {
+		t.Errorf("unexpected error validating tenant, error %v", err)
+	}
+}
+
+func TestItIsReadyOnceDefaultDatabaseIsInitializedAndStoragesAreReady(t *testing.T) {
+	b := &fakeBuilder{}
+	r := NewRouter(b.build, "defaultdb", false)
+
+	if err := r.Ready(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+
+	if _, err := r.Stack(context.Background()); err != nil {
+		t.Fatalf("unexpected error resolving stack, error %v", err)
+	}
+	if err := r.Ready(context.Background()); err != nil {
+		t.Errorf("unexpected readiness error %v", err)
+	}
+
+	b.ready = errors.New("fake session lost")
+	if err := r.Ready(context.Background()); !errors.Is(err, b.ready) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+}
+
+type fakeBuilder struct {
+	databases []string
+	ready     error
+}
+
+func (f *fakeBuilder) build(ctx context.Context, database string) (*Stack, error) {
+	f.databases = append(f.databases, database)
+	return &Stack{Ready: func(ctx context.Context) error { return f.ready }}, nil
+}
+
+func nopUnaryHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return nil, nil
+}
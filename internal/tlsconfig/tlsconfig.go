@@ -0,0 +1,116 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNoCertificates = errors.New("no PEM certificates found")
+var ErrIncompleteKeyPair = errors.New("certificate and key must be set together")
+
+// Server builds server TLS config from its key pair, clientCAFile enables client certificates
+// verification, connections without certificate are still accepted and get authenticated by token
+func Server(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("server key pair, error %w", ErrIncompleteKeyPair)
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load key pair %s, error %w", certFile, err)
+	}
+
+	c := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile == "" {
+		return c, nil
+	}
+
+	pool, err := Pool(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	c.ClientCAs = pool
+	c.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return c, nil
+}
+
+// Client builds client TLS config, caFile replaces system roots on server certificate verification,
+// certFile and keyFile are presented to servers asking for client certificates
+func Client(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	c := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pool, err := Pool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+
+	if certFile == "" && keyFile == "" {
+		return c, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("client key pair, error %w", ErrIncompleteKeyPair)
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load key pair %s, error %w", certFile, err)
+	}
+	c.Certificates = []tls.Certificate{cert}
+
+	return c, nil
+}
+
+// Loopback builds client TLS config trusting server own certificate, so its gateway dials it whatever its names
+// are, server name is its first DNS or IP name
+func Loopback(certFile string) (*tls.Config, error) {
+	pool, err := Pool(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read certificates %s, error %w", certFile, err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("certificates %s, error %w", certFile, ErrNoCertificates)
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse certificate %s, error %w", certFile, err)
+	}
+
+	serverName := "localhost"
+	switch {
+	case len(leaf.DNSNames) > 0:
+		serverName = leaf.DNSNames[0]
+	case len(leaf.IPAddresses) > 0:
+		serverName = leaf.IPAddresses[0].String()
+	}
+
+	return &tls.Config{RootCAs: pool, ServerName: serverName, MinVersion: tls.VersionTLS12}, nil
+}
+
+// Pool loads PEM certificates from file
+func Pool(file string) (*x509.CertPool, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read certificates %s, error %w", file, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("certificates %s, error %w", file, ErrNoCertificates)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,143 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestItVerifiesClientCertificatesIssuedByClientCA(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := issue(t, dir, "ca", &x509.Certificate{Subject: pkix.Name{CommonName: "fake_ca"}, IsCA: true, KeyUsage: x509.KeyUsageCertSign}, nil, nil)
+	issue(t, dir, "server", &x509.Certificate{Subject: pkix.Name{CommonName: "fake_server"}, DNSNames: []string{"log-api.test"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, ca, caKey)
+	issue(t, dir, "client", &x509.Certificate{Subject: pkix.Name{CommonName: "fake_client"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, ca, caKey)
+
+	srv, err := Server(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatalf("unexpected error building server config %v", err)
+	}
+
+	cl, err := Client(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key"), "log-api.test")
+	if err != nil {
+		t.Fatalf("unexpected error building client config %v", err)
+	}
+	if expected, got := "fake_client", handshake(t, srv, cl); expected != got {
+		t.Errorf("client certificate subjects do not match, expected %s got %s", expected, got)
+	}
+
+	anonymous, err := Client(filepath.Join(dir, "ca.pem"), "", "", "log-api.test")
+	if err != nil {
+		t.Fatalf("unexpected error building client config %v", err)
+	}
+	if expected, got := "", handshake(t, srv, anonymous); expected != got {
+		t.Errorf("client certificate subjects do not match, expected none got %s", got)
+	}
+}
+
+func TestItDialsServerOwnCertificateOnLoopback(t *testing.T) {
+	dir := t.TempDir()
+	issue(t, dir, "server", &x509.Certificate{Subject: pkix.Name{CommonName: "fake_server"}, DNSNames: []string{"log-api.test"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, nil, nil)
+
+	srv, err := Server(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), "")
+	if err != nil {
+		t.Fatalf("unexpected error building server config %v", err)
+	}
+
+	cl, err := Loopback(filepath.Join(dir, "server.pem"))
+	if err != nil {
+		t.Fatalf("unexpected error building loopback config %v", err)
+	}
+	if expected, got := "log-api.test", cl.ServerName; expected != got {
+		t.Errorf("server names do not match, expected %s got %s", expected, got)
+	}
+
+	handshake(t, srv, cl)
+}
+
+func TestItFailsOnIncompleteKeyPair(t *testing.T) {
+	if _, err := Server("server.pem", "", ""); !errors.Is(err, ErrIncompleteKeyPair) {
+		t.Errorf("unexpected error building server config, got %v", err)
+	}
+
+	if _, err := Client("", "client.pem", "", ""); !errors.Is(err, ErrIncompleteKeyPair) {
+		t.Errorf("unexpected error building client config, got %v", err)
+	}
+}
+
+// handshake runs a TLS handshake between srv and cl configs, returning verified client certificate common name
+func handshake(t *testing.T, srv, cl *tls.Config) string {
+	t.Helper()
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	errs := make(chan error, 1)
+	c := tls.Client(b, cl)
+	go func() { errs <- c.Handshake() }()
+
+	s := tls.Server(a, srv)
+	if err := s.Handshake(); err != nil {
+		t.Fatalf("unexpected server handshake error %v", err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected client handshake error %v", err)
+	}
+
+	chains := s.ConnectionState().VerifiedChains
+	if len(chains) == 0 {
+		return ""
+	}
+
+	return chains[0][0].Subject.CommonName
+}
+
+// issue writes name certificate and key PEM files on dir, signed by parent or self signed when parent is nil
+func issue(t *testing.T, dir, name string, tpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating key %v", err)
+	}
+
+	tpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tpl.NotBefore = time.Now().Add(-time.Hour)
+	tpl.NotAfter = time.Now().Add(time.Hour)
+	tpl.BasicConstraintsValid = true
+	tpl.KeyUsage |= x509.KeyUsageDigitalSignature
+	if parent == nil {
+		parent, parentKey = tpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unexpected error parsing certificate %v", err)
+	}
+
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling key %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("unexpected error writing certificate %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}), 0o600); err != nil {
+		t.Fatalf("unexpected error writing key %v", err)
+	}
+
+	return cert, key
+}
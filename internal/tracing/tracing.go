@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/marcosQuesada/log-api"
+	serviceName         = "log-api"
+)
+
+// Exporters
+const (
+	ExporterNone = "none"
+	ExporterFile = "file"
+	ExporterOTLP = "otlp"
+)
+
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Options configures trace export, file exporter writes JSON spans to File, otlp exporter sends them to Endpoint collector
+type Options struct {
+	Exporter string
+	File     string
+	Endpoint string
+	Insecure bool
+	Ratio    float64
+}
+
+// Setup installs global tracer provider and W3C trace context propagation, its shutdown flushes pending spans.
+// None exporter keeps propagation so incoming trace context still reaches immudb calls
+func Setup(ctx context.Context, o *Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exp sdktrace.SpanExporter
+	var closeFn func() error
+	switch o.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterFile:
+		f, err := os.OpenFile(o.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open trace file %s, error %w", o.File, err)
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("unable to build file trace exporter, error %w", err)
+		}
+		exp, closeFn = e, f.Close
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(o.Endpoint)}
+		if o.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		e, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build otlp trace exporter on %s, error %w", o.Endpoint, err)
+		}
+		exp = e
+	default:
+		return nil, fmt.Errorf("exporter %q, error %w", o.Exporter, ErrUnknownExporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.Ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		if err := tp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("unable to flush spans, error %w", err)
+		}
+		if closeFn != nil {
+			return closeFn()
+		}
+		return nil
+	}, nil
+}
+
+// Start starts a span named name as ctx span child
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name)
+}
+
+// Detach returns a context carrying ctx span without its cancellation nor deadline,
+// so calls meant to outlive requests are still traced under them
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestItExportsSpansToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), &Options{Exporter: ExporterFile, File: path, Ratio: 1})
+	if err != nil {
+		t.Fatalf("unexpected error setting up tracing, error %v", err)
+	}
+
+	ctx, parent := Start(context.Background(), "fake_parent")
+	_, child := Start(ctx, "fake_child")
+	child.End()
+	parent.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error shutting down tracing, error %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading traces, error %v", err)
+	}
+	for _, name := range []string{"fake_parent", "fake_child", parent.SpanContext().TraceID().String()} {
+		if !strings.Contains(string(raw), name) {
+			t.Errorf("expected %s on exported spans", name)
+		}
+	}
+}
+
+func TestItDetachesContextKeepingItsSpan(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	ctx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), sc))
+	cancel()
+
+	d := Detach(ctx)
+	if d.Err() != nil {
+		t.Errorf("unexpected detached context error %v", d.Err())
+	}
+	if expected, got := sc.TraceID(), trace.SpanContextFromContext(d).TraceID(); expected != got {
+		t.Errorf("trace ids do not match, expected %s got %s", expected, got)
+	}
+}
+
+func TestItRefusesUnknownExporters(t *testing.T) {
+	if _, err := Setup(context.Background(), &Options{Exporter: "fake"}); !errors.Is(err, ErrUnknownExporter) {
+		t.Errorf("unexpected error type, got %v", err)
+	}
+}